@@ -17,16 +17,67 @@ limitations under the License.
 package main
 
 import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/net-istio/pkg/dryrun"
 	"knative.dev/net-istio/pkg/reconciler/informerfiltering"
 	"knative.dev/net-istio/pkg/reconciler/ingress"
+	"knative.dev/net-istio/pkg/reconciler/istioconfig"
 	"knative.dev/net-istio/pkg/reconciler/serverlessservice"
+	"knative.dev/pkg/injection"
 	"knative.dev/pkg/signals"
 
 	// This defines the shared main for injected controllers.
 	"knative.dev/pkg/injection/sharedmain"
 )
 
+const component = "net-istio-controller"
+
 func main() {
 	ctx := informerfiltering.GetContextWithFilteringLabelSelector(signals.NewContext())
-	sharedmain.MainWithContext(ctx, "net-istio-controller", ingress.NewController, serverlessservice.NewController)
+	ctors := []injection.ControllerConstructor{ingress.NewController, serverlessservice.NewController, istioconfig.NewController}
+
+	if dryRun, _ := strconv.ParseBool(os.Getenv(dryrun.EnabledEnvKey)); dryRun {
+		mainDryRun(ctx, ctors...)
+		return
+	}
+	sharedmain.MainWithContext(ctx, component, ctors...)
+}
+
+// mainDryRun runs the controllers in shadow of the active ones: nothing is written, the
+// changes the controllers would make are served on the dry-run debug endpoint.
+func mainDryRun(ctx context.Context, ctors ...injection.ControllerConstructor) {
+	recorder := dryrun.NewRecorder(nil)
+	cfg := injection.ParseAndGetRESTConfigOrDie()
+	// The diffs are computed from the JSON of the requests.
+	cfg.ContentType = runtime.ContentTypeJSON
+	cfg.AcceptContentTypes = runtime.ContentTypeJSON
+	cfg.Wrap(recorder.Wrap)
+
+	port := os.Getenv(dryrun.PortEnvKey)
+	if port == "" {
+		port = strconv.Itoa(dryrun.DefaultPort)
+	}
+	mux := http.NewServeMux()
+	mux.Handle(dryrun.Path, recorder)
+	server := &http.Server{
+		Addr:              ":" + port,
+		Handler:           mux,
+		ReadHeaderTimeout: 15 * time.Second,
+	}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("Failed to serve the dry-run debug endpoint: ", err)
+		}
+	}()
+
+	// Leader election would take the leases over from the active controllers.
+	sharedmain.MainWithConfig(sharedmain.WithHADisabled(ctx), component, cfg, ctors...)
 }
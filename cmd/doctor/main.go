@@ -0,0 +1,71 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// doctor inspects the cluster of the current kubeconfig context and prints the problems
+// of the net-istio installation, one per line. It exits with status 1 when it finds any.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	istioclientset "istio.io/client-go/pkg/clientset/versioned"
+	"k8s.io/client-go/kubernetes"
+	netistioclientset "knative.dev/net-istio/pkg/client/clientset/versioned"
+	"knative.dev/net-istio/pkg/doctor"
+	networkingclientset "knative.dev/networking/pkg/client/clientset/versioned"
+	"knative.dev/pkg/environment"
+	"knative.dev/pkg/system"
+)
+
+func main() {
+	env := new(environment.ClientConfig)
+	env.InitFlags(flag.CommandLine)
+	namespace := flag.String("namespace", "knative-serving",
+		"The namespace of the controller, used for gateways configured without namespace.")
+	flag.Parse()
+
+	if os.Getenv(system.NamespaceEnvKey) == "" {
+		if err := os.Setenv(system.NamespaceEnvKey, *namespace); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	cfg, err := env.GetRESTConfig()
+	if err != nil {
+		log.Fatal("Error building kubeconfig: ", err)
+	}
+	d := &doctor.Doctor{
+		KubeClient:        kubernetes.NewForConfigOrDie(cfg),
+		IstioClient:       istioclientset.NewForConfigOrDie(cfg),
+		NetworkingClient:  networkingclientset.NewForConfigOrDie(cfg),
+		IstioConfigClient: netistioclientset.NewForConfigOrDie(cfg),
+	}
+
+	problems, err := d.Diagnose(context.Background())
+	if err != nil {
+		log.Fatal("Failed to inspect the cluster: ", err)
+	}
+	for _, problem := range problems {
+		fmt.Println(problem)
+	}
+	if len(problems) > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// migrate-config rewrites a config-istio ConfigMap using the legacy gateway.* and
+// local-gateway.* keys to the external-gateways and local-gateways keys, e.g.
+//
+//	kubectl get configmap -n knative-serving config-istio -oyaml | migrate-config | kubectl apply -f -
+//
+// The migration fails unless the rewritten ConfigMap configures the same gateways.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"knative.dev/net-istio/pkg/configmigration"
+	"knative.dev/pkg/system"
+)
+
+func main() {
+	file := flag.String("f", "-", "The file containing the config-istio ConfigMap, - for stdin.")
+	namespace := flag.String("namespace", "knative-serving",
+		"The namespace of the controller, used for gateways configured without namespace.")
+	flag.Parse()
+
+	if os.Getenv(system.NamespaceEnvKey) == "" {
+		if err := os.Setenv(system.NamespaceEnvKey, *namespace); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	var (
+		manifest []byte
+		err      error
+	)
+	if *file == "-" {
+		manifest, err = io.ReadAll(os.Stdin)
+	} else {
+		manifest, err = os.ReadFile(*file)
+	}
+	if err != nil {
+		log.Fatal("Failed to read the ConfigMap: ", err)
+	}
+	migrated, err := configmigration.Migrate(manifest)
+	if err != nil {
+		log.Fatal("Failed to migrate the ConfigMap: ", err)
+	}
+	fmt.Print(string(migrated))
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// render-ingress prints the VirtualServices, Gateways and Secrets the controller produces
+// for Knative Ingresses, without a cluster, e.g.
+//
+//	render-ingress ingresses.yaml config-istio.yaml config-network.yaml gateways.yaml
+//
+// The given files contain the Ingresses, the config-istio and config-network ConfigMaps,
+// and optionally the Gateways, Secrets and gateway Services read by the controller.
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"go.uber.org/zap"
+	istioscheme "istio.io/client-go/pkg/clientset/versioned/scheme"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	kubescheme "k8s.io/client-go/kubernetes/scheme"
+	"knative.dev/net-istio/pkg/reconciler/ingress"
+	"knative.dev/net-istio/pkg/reconciler/ingress/config"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+	networkingscheme "knative.dev/networking/pkg/client/clientset/versioned/scheme"
+	netconfig "knative.dev/networking/pkg/config"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/system"
+	"sigs.k8s.io/yaml"
+)
+
+func main() {
+	namespace := flag.String("namespace", "knative-serving",
+		"The namespace of the controller, used for gateways configured without namespace.")
+	flag.Parse()
+	if flag.NArg() == 0 {
+		log.Fatal("Usage: render-ingress FILE...")
+	}
+
+	if os.Getenv(system.NamespaceEnvKey) == "" {
+		if err := os.Setenv(system.NamespaceEnvKey, *namespace); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	var objects []runtime.Object
+	for _, file := range flag.Args() {
+		fileObjects, err := decodeFile(file)
+		if err != nil {
+			log.Fatalf("Failed to read %s: %v", file, err)
+		}
+		objects = append(objects, fileObjects...)
+	}
+
+	cfg, ingresses, objects, err := split(objects)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// The reconciler logs every step, only the rendered objects are printed.
+	ctx := logging.WithLogger(context.Background(), zap.NewNop().Sugar())
+	rendered, err := ingress.Render(ctx, cfg, ingresses, objects)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, obj := range rendered {
+		out, err := yaml.Marshal(obj)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("---\n%s", out)
+	}
+}
+
+// split separates the configuration and the Ingresses from the other objects.
+func split(objects []runtime.Object) (*config.Config, []*v1alpha1.Ingress, []runtime.Object, error) {
+	istioConfigMap, networkConfigMap := &corev1.ConfigMap{}, &corev1.ConfigMap{}
+	var (
+		ingresses []*v1alpha1.Ingress
+		rest      []runtime.Object
+	)
+	for _, obj := range objects {
+		switch o := obj.(type) {
+		case *v1alpha1.Ingress:
+			ingresses = append(ingresses, o)
+		case *corev1.ConfigMap:
+			switch o.Name {
+			case config.IstioConfigName:
+				istioConfigMap = o
+			case netconfig.ConfigMapName:
+				networkConfigMap = o
+			default:
+				return nil, nil, nil, fmt.Errorf("unexpected ConfigMap %s", o.Name)
+			}
+		default:
+			rest = append(rest, obj)
+		}
+	}
+
+	istio, err := config.NewIstioFromConfigMap(istioConfigMap)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid %s: %w", config.IstioConfigName, err)
+	}
+	network, err := netconfig.NewConfigFromConfigMap(networkConfigMap)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid %s: %w", netconfig.ConfigMapName, err)
+	}
+	return &config.Config{Istio: istio, Network: network}, ingresses, rest, nil
+}
+
+// decodeFile decodes the YAML or JSON documents of the given file.
+func decodeFile(file string) ([]runtime.Object, error) {
+	scheme := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{
+		kubescheme.AddToScheme,
+		istioscheme.AddToScheme,
+		networkingscheme.AddToScheme,
+	} {
+		if err := addToScheme(scheme); err != nil {
+			return nil, err
+		}
+	}
+	decoder := serializer.NewCodecFactory(scheme).UniversalDeserializer()
+
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var objects []runtime.Object
+	reader := k8syaml.NewYAMLReader(bufio.NewReader(f))
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return objects, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if js, err := yaml.YAMLToJSON(doc); err != nil {
+			return nil, err
+		} else if string(js) == "null" {
+			// Empty document, e.g. only comments.
+			continue
+		}
+		obj, _, err := decoder.Decode(doc, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, obj)
+	}
+}
@@ -22,34 +22,17 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"knative.dev/net-istio/pkg/defaults"
-	istioconfig "knative.dev/net-istio/pkg/reconciler/ingress/config"
+	"knative.dev/net-istio/pkg/webhook/configvalidation"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/injection/sharedmain"
 	"knative.dev/pkg/signals"
 	"knative.dev/pkg/webhook"
 	"knative.dev/pkg/webhook/certificates"
-	"knative.dev/pkg/webhook/configmaps"
 	"knative.dev/pkg/webhook/resourcesemantics"
 	"knative.dev/pkg/webhook/resourcesemantics/defaulting"
 )
 
-func NewConfigValidationController(ctx context.Context, _ configmap.Watcher) *controller.Impl {
-	return configmaps.NewAdmissionController(ctx,
-
-		// Name of the resource webhook.
-		"config.webhook.istio.networking.internal.knative.dev",
-
-		// The path on which to serve the webhook.
-		"/config-validation",
-
-		// The configmaps to validate.
-		configmap.Constructors{
-			istioconfig.IstioConfigName: istioconfig.NewIstioFromConfigMap,
-		},
-	)
-}
-
 var types = map[schema.GroupVersionKind]resourcesemantics.GenericCRD{
 	appsv1.SchemeGroupVersion.WithKind("Deployment"): &defaults.IstioDeployment{},
 }
@@ -89,6 +72,6 @@ func main() {
 		ctx, "net-istio-webhook",
 		certificates.NewController,
 		NewDefaultingAdmissionController,
-		NewConfigValidationController,
+		configvalidation.NewAdmissionController,
 	)
 }
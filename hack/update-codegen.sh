@@ -27,8 +27,29 @@ export PATH="${PATH}:${GOBIN}"
 
 echo "=== Update Codegen for $MODULE_NAME"
 
+group "Kubernetes Codegen"
+
+source "${CODEGEN_PKG}/kube_codegen.sh"
+
+kube::codegen::gen_helpers \
+  --boilerplate "${REPO_ROOT_DIR}/hack/boilerplate/boilerplate.go.txt" \
+  "${REPO_ROOT_DIR}/pkg/apis"
+
+kube::codegen::gen_client \
+  --boilerplate "${REPO_ROOT_DIR}/hack/boilerplate/boilerplate.go.txt" \
+  --output-dir "${REPO_ROOT_DIR}/pkg/client" \
+  --output-pkg "knative.dev/net-istio/pkg/client" \
+  --with-watch \
+  "${REPO_ROOT_DIR}/pkg/apis"
+
 group "Knative Codegen"
 
+# Knative Injection (for the net-istio configuration)
+${KNATIVE_CODEGEN_PKG}/hack/generate-knative.sh "injection" \
+  knative.dev/net-istio/pkg/client knative.dev/net-istio/pkg/apis \
+  "istio:v1alpha1" \
+  --go-header-file ${REPO_ROOT_DIR}/hack/boilerplate/boilerplate.go.txt
+
 # Knative Injection (for istio)
 OUTPUT_PKG="knative.dev/net-istio/pkg/client/istio/injection" \
 ${KNATIVE_CODEGEN_PKG}/hack/generate-knative.sh "injection" \
@@ -0,0 +1,73 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"knative.dev/pkg/apis"
+)
+
+const (
+	// IstioConfigConditionValid is set when the configuration could be parsed and validated.
+	IstioConfigConditionValid apis.ConditionType = "Valid"
+
+	// IstioConfigConditionActive is set when the configuration is used by the controller,
+	// which is only the case for the IstioConfig named config-istio in its namespace.
+	IstioConfigConditionActive apis.ConditionType = "Active"
+)
+
+var istioConfigCondSet = apis.NewLivingConditionSet(
+	IstioConfigConditionValid,
+	IstioConfigConditionActive,
+)
+
+// InitializeConditions initializes the IstioConfig conditions.
+func (ics *IstioConfigStatus) InitializeConditions() {
+	istioConfigCondSet.Manage(ics).InitializeConditions()
+}
+
+// MarkValid marks the configuration as valid.
+func (ics *IstioConfigStatus) MarkValid() {
+	istioConfigCondSet.Manage(ics).MarkTrue(IstioConfigConditionValid)
+}
+
+// MarkInvalid marks the configuration as invalid with the given parsing or validation error.
+func (ics *IstioConfigStatus) MarkInvalid(message string) {
+	istioConfigCondSet.Manage(ics).MarkFalse(IstioConfigConditionValid, "Invalid", message)
+}
+
+// MarkActive marks the configuration as used by the controller.
+func (ics *IstioConfigStatus) MarkActive() {
+	istioConfigCondSet.Manage(ics).MarkTrue(IstioConfigConditionActive)
+}
+
+// MarkInactive marks the configuration as not used by the controller.
+func (ics *IstioConfigStatus) MarkInactive(reason, message string) {
+	istioConfigCondSet.Manage(ics).MarkFalse(IstioConfigConditionActive, reason, message)
+}
+
+// GetCondition gets a specific condition of the IstioConfig status.
+func (ics *IstioConfigStatus) GetCondition(t apis.ConditionType) *apis.Condition {
+	return istioConfigCondSet.Manage(ics).GetCondition(t)
+}
+
+// IsReady returns true if the IstioConfig is valid and used, and its status reflects
+// its current generation.
+func (ic *IstioConfig) IsReady() bool {
+	ics := ic.Status
+	return ics.ObservedGeneration == ic.Generation &&
+		ics.GetCondition(apis.ConditionReady).IsTrue()
+}
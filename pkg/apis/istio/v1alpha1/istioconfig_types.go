@@ -0,0 +1,200 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/kmeta"
+)
+
+// +genclient
+// +genreconciler
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// IstioConfig is the typed configuration of net-istio. The IstioConfig named config-istio
+// in the namespace of the controller takes precedence over the config-istio ConfigMap.
+type IstioConfig struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the desired configuration.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	// +optional
+	Spec IstioConfigSpec `json:"spec,omitempty"`
+
+	// Status reports whether the configuration is valid and used.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	// +optional
+	Status IstioConfigStatus `json:"status,omitempty"`
+}
+
+var (
+	// Check that we can create OwnerReferences to an IstioConfig.
+	_ kmeta.OwnerRefable = (*IstioConfig)(nil)
+
+	// Check that the type conforms to the duck Knative Resource shape.
+	_ duckv1.KRShaped = (*IstioConfig)(nil)
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// IstioConfigList is a collection of IstioConfig objects.
+type IstioConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// Items is the list of IstioConfig objects.
+	Items []IstioConfig `json:"items"`
+}
+
+// IstioConfigSpec is the configuration of the Istio gateways and of how Ingresses are
+// programmed on them. It mirrors the keys of the config-istio ConfigMap.
+type IstioConfigSpec struct {
+	// ExternalGateways are the gateways serving public Ingresses. The default
+	// knative-ingress-gateway is used when omitted, while an empty list configures no
+	// external gateway.
+	// +optional
+	ExternalGateways []Gateway `json:"externalGateways"`
+
+	// LocalGateways are the gateways serving cluster-local Ingresses. The default
+	// knative-local-gateway is used when omitted, while an empty list configures no
+	// local gateway.
+	// +optional
+	LocalGateways []Gateway `json:"localGateways"`
+
+	// EnableHTTP3 serves and advertises HTTP/3 next to the HTTPS servers of the
+	// external gateways.
+	// +optional
+	EnableHTTP3 bool `json:"enableHTTP3,omitempty"`
+
+	// HTTPSRedirect configures how plain HTTP requests are redirected to HTTPS.
+	// +optional
+	HTTPSRedirect *HTTPSRedirect `json:"httpsRedirect,omitempty"`
+
+	// ProbeHosts configures which hosts of an Ingress are probed on each gateway:
+	// "single", "all" or the maximum number of hosts to probe. Defaults to "single".
+	// +optional
+	ProbeHosts string `json:"probeHosts,omitempty"`
+
+	// ReadinessStrategy configures how the readiness of Ingresses is checked: "probe",
+	// "config-status" or "both". Defaults to "probe".
+	// +optional
+	ReadinessStrategy string `json:"readinessStrategy,omitempty"`
+
+	// ProbePods configures how many pods of each gateway are probed before an Ingress is
+	// marked ready, the others being probed in the background: "all" or a positive
+	// number. Defaults to "all".
+	// +optional
+	ProbePods string `json:"probePods,omitempty"`
+
+	// ProbeQuorum configures the percentage of the probed pods of each gateway which must
+	// serve an Ingress before it is marked ready, between 0 and 100. 0 requires all the
+	// probed pods, like the default of 100.
+	// +optional
+	ProbeQuorum int `json:"probeQuorum,omitempty"`
+
+	// ProbeIPFamilies configures which IP families of the gateway pods are probed:
+	// "primary" or "all". Defaults to "primary".
+	// +optional
+	ProbeIPFamilies string `json:"probeIPFamilies,omitempty"`
+
+	// DomainClaims reserve the hosts of domains to the Ingresses of some namespaces.
+	// The hosts of other domains may be claimed by any namespace.
+	// +optional
+	DomainClaims []DomainClaim `json:"domainClaims,omitempty"`
+}
+
+// DomainClaim reserves the hosts of a domain to the Ingresses of some namespaces.
+type DomainClaim struct {
+	// Domain is the domain of the claimed hosts, e.g. "*.example.com" for the
+	// subdomains of example.com or "example.com" for example.com as well.
+	Domain string `json:"domain"`
+
+	// Namespaces are the namespaces whose Ingresses may claim the hosts of the domain.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// Gateway is an Istio gateway and the Kubernetes Service backing it.
+type Gateway struct {
+	// Namespace is the namespace of the Istio Gateway.
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the Istio Gateway.
+	Name string `json:"name"`
+
+	// Service is the hostname of the Kubernetes Service backing the gateway, e.g.
+	// istio-ingressgateway.istio-system.svc.cluster.local.
+	Service string `json:"service"`
+
+	// LabelSelector selects the Ingresses served by the gateway by their labels.
+	// +optional
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+
+	// NamespaceSelector selects the Ingresses served by the gateway by the labels of
+	// their namespace.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// Domains are the domain suffixes of the hosts served by the gateway.
+	// +optional
+	Domains []string `json:"domains,omitempty"`
+}
+
+// HTTPSRedirect configures the redirection of plain HTTP requests to HTTPS.
+type HTTPSRedirect struct {
+	// Code is the HTTP status code of the redirect, 301 by default.
+	// +optional
+	Code uint32 `json:"code,omitempty"`
+
+	// Port is the port the redirect points to, 443 by default.
+	// +optional
+	Port uint32 `json:"port,omitempty"`
+
+	// ExemptPaths are the path prefixes served over plain HTTP instead of being redirected.
+	// +optional
+	ExemptPaths []string `json:"exemptPaths,omitempty"`
+}
+
+// IstioConfigStatus is the status of an IstioConfig.
+type IstioConfigStatus struct {
+	duckv1.Status `json:",inline"`
+}
+
+// GetGroupVersionKind implements kmeta.OwnerRefable.
+func (*IstioConfig) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("IstioConfig")
+}
+
+// GetConditionSet retrieves the condition set for this resource. Implements the KRShaped interface.
+func (*IstioConfig) GetConditionSet() apis.ConditionSet {
+	return istioConfigCondSet
+}
+
+// GetStatus retrieves the status of the IstioConfig. Implements the KRShaped interface.
+func (ic *IstioConfig) GetStatus() *duckv1.Status {
+	return &ic.Status.Status
+}
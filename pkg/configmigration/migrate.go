@@ -0,0 +1,202 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package configmigration rewrites config-istio ConfigMaps from the legacy gateway.* and
+// local-gateway.* keys to the external-gateways and local-gateways keys.
+package configmigration
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+	"go.yaml.in/yaml/v3"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/net-istio/pkg/reconciler/ingress/config"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+// gateway is a gateway in the external-gateways and local-gateways format. The legacy keys
+// can not configure selectors or domains.
+type gateway struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Service   string `json:"service"`
+}
+
+// Migrate rewrites the given config-istio ConfigMap manifest, e.g. the output of
+// `kubectl get configmap config-istio -oyaml`, to configure its gateways with the
+// external-gateways and local-gateways keys. Other keys and comments are kept, the
+// comments of the legacy keys move to the keys replacing them.
+//
+// It returns an error unless the migrated ConfigMap configures exactly the same as the
+// given one.
+func Migrate(manifest []byte) ([]byte, error) {
+	before, err := parse(manifest)
+	if err != nil {
+		return nil, err
+	}
+	if !config.UsesLegacyFormat(before) {
+		return nil, errors.New("the ConfigMap does not use the legacy gateway.* or local-gateway.* keys")
+	}
+	want, err := config.NewIstioFromConfigMap(before)
+	if err != nil {
+		return nil, fmt.Errorf("the ConfigMap is invalid: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(manifest, &doc); err != nil {
+		return nil, err
+	}
+	data := dataNode(&doc)
+	if data == nil {
+		return nil, errors.New("the ConfigMap has no data")
+	}
+
+	if err := replaceLegacyKeys(data, want); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	if err := verify(out.Bytes(), want); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// verify returns an error unless the migrated manifest configures the wanted configuration.
+func verify(manifest []byte, want *config.Istio) error {
+	after, err := parse(manifest)
+	if err != nil {
+		return err
+	}
+	got, err := config.NewIstioFromConfigMap(after)
+	if err != nil {
+		// The legacy format allows several gateways without selector, the new one does not.
+		return fmt.Errorf("the migrated ConfigMap is invalid: %w", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		return fmt.Errorf("the migrated ConfigMap configures differently (-want, +got): %s", diff)
+	}
+	return nil
+}
+
+func parse(manifest []byte) (*corev1.ConfigMap, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := sigsyaml.Unmarshal(manifest, configMap); err != nil {
+		return nil, fmt.Errorf("failed to parse the ConfigMap: %w", err)
+	}
+	return configMap, nil
+}
+
+// dataNode returns the mapping node of the data of the ConfigMap in the given document.
+func dataNode(doc *yaml.Node) *yaml.Node {
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "data" && root.Content[i+1].Kind == yaml.MappingNode {
+			return root.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// replaceLegacyKeys replaces the legacy keys of the given data with the external-gateways
+// and local-gateways keys, placed where the first legacy key was.
+func replaceLegacyKeys(data *yaml.Node, istio *config.Istio) error {
+	content := make([]*yaml.Node, 0, len(data.Content))
+	var (
+		comments              []string
+		insertAt              = -1
+		hasExternal, hasLocal bool
+	)
+	for i := 0; i+1 < len(data.Content); i += 2 {
+		key, value := data.Content[i], data.Content[i+1]
+		if !config.IsLegacyGatewayKey(key.Value) {
+			content = append(content, key, value)
+			continue
+		}
+		if insertAt < 0 {
+			insertAt = len(content)
+		}
+		if config.IsLegacyLocalGatewayKey(key.Value) {
+			hasLocal = true
+		} else {
+			hasExternal = true
+		}
+		for _, comment := range []string{key.HeadComment, key.LineComment, value.LineComment, value.FootComment, key.FootComment} {
+			if comment != "" {
+				comments = append(comments, comment)
+			}
+		}
+	}
+
+	var replacement []*yaml.Node
+	if hasExternal {
+		nodes, err := gatewaysNodes(config.ExternalGatewaysKey, istio.IngressGateways)
+		if err != nil {
+			return err
+		}
+		replacement = append(replacement, nodes...)
+	}
+	if hasLocal {
+		nodes, err := gatewaysNodes(config.LocalGatewaysKey, istio.LocalGateways)
+		if err != nil {
+			return err
+		}
+		replacement = append(replacement, nodes...)
+	}
+	replacement[0].HeadComment = strings.Join(comments, "\n")
+
+	data.Content = slices.Insert(content, insertAt, replacement...)
+	return nil
+}
+
+func gatewaysNodes(key string, gateways []config.Gateway) ([]*yaml.Node, error) {
+	converted := make([]gateway, 0, len(gateways))
+	for _, gtw := range gateways {
+		converted = append(converted, gateway{
+			Name:      gtw.Name,
+			Namespace: gtw.Namespace,
+			Service:   gtw.ServiceURL,
+		})
+	}
+	value, err := sigsyaml.Marshal(converted)
+	if err != nil {
+		return nil, err
+	}
+	return []*yaml.Node{
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: string(value), Style: yaml.LiteralStyle},
+	}, nil
+}
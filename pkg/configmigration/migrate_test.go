@@ -0,0 +1,123 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configmigration
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	_ "knative.dev/pkg/system/testing"
+)
+
+func TestMigrate(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr string
+	}{{
+		name: "external and local gateways",
+		in: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config-istio
+  namespace: knative-serving
+data:
+  # The public gateway.
+  gateway.knative-serving.knative-ingress-gateway: "istio-ingressgateway.istio-system.svc.cluster.local"
+  enable-http3: "true" # Serve HTTP/3.
+  local-gateway.knative-local-gateway: "knative-local-gateway.istio-system.svc.cluster.local"
+`,
+		want: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config-istio
+  namespace: knative-serving
+data:
+  # The public gateway.
+  external-gateways: |
+    - name: knative-ingress-gateway
+      namespace: knative-serving
+      service: istio-ingressgateway.istio-system.svc.cluster.local
+  local-gateways: |
+    - name: knative-local-gateway
+      namespace: knative-testing
+      service: knative-local-gateway.istio-system.svc.cluster.local
+  enable-http3: "true" # Serve HTTP/3.
+`,
+	}, {
+		name: "only external gateway",
+		in: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config-istio
+data:
+  gateway.knative-serving.gateway: "gateway.istio-system.svc.cluster.local"
+`,
+		want: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config-istio
+data:
+  external-gateways: |
+    - name: gateway
+      namespace: knative-serving
+      service: gateway.istio-system.svc.cluster.local
+`,
+	}, {
+		name: "new format",
+		in: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config-istio
+data:
+  external-gateways: "[]"
+`,
+		wantErr: "the ConfigMap does not use the legacy gateway.* or local-gateway.* keys",
+	}, {
+		name: "several default gateways",
+		in: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config-istio
+data:
+  gateway.knative-serving.gateway: "gateway.istio-system.svc.cluster.local"
+  gateway.knative-serving.other-gateway: "other-gateway.istio-system.svc.cluster.local"
+`,
+		wantErr: "the migrated ConfigMap is invalid",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Migrate([]byte(tt.in))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Migrate() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("Migrate() =", err)
+			}
+			if diff := cmp.Diff(tt.want, string(got)); diff != "" {
+				t.Error("Unexpected manifest (-want, +got):", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,363 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package doctor inspects a cluster for problems of a net-istio installation.
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	istiov1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+	istioclientset "istio.io/client-go/pkg/clientset/versioned"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+	netistioclientset "knative.dev/net-istio/pkg/client/clientset/versioned"
+	"knative.dev/net-istio/pkg/reconciler/ingress/config"
+	"knative.dev/net-istio/pkg/reconciler/ingress/resources"
+	"knative.dev/networking/pkg/apis/networking"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+	networkingclientset "knative.dev/networking/pkg/client/clientset/versioned"
+	netconfig "knative.dev/networking/pkg/config"
+	"knative.dev/pkg/reconciler"
+	"knative.dev/pkg/system"
+)
+
+// The checks reported by the Doctor.
+const (
+	CheckUnsupportedIstioAPI    = "UnsupportedIstioAPI"
+	CheckMissingGateway         = "MissingGateway"
+	CheckIngressNotReady        = "IngressNotReady"
+	CheckOrphanedVirtualService = "OrphanedVirtualService"
+	CheckOrphanedGateway        = "OrphanedGateway"
+	CheckOrphanedSecret         = "OrphanedSecret"
+	CheckStaleGatewayServer     = "StaleGatewayServer"
+	CheckConflictingHost        = "ConflictingHost"
+)
+
+// meshGateway is the reserved gateway name of the sidecars.
+const meshGateway = "mesh"
+
+var isIstioIngress = reconciler.AnnotationFilterFunc(networking.IngressClassAnnotationKey, netconfig.IstioIngressClassName, true)
+
+// Problem is a problem found in the cluster.
+type Problem struct {
+	// Check is the check that found the problem.
+	Check string
+	// Object is the kind and the qualified name of the object with the problem.
+	Object string
+	// Message describes the problem.
+	Message string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s: %s", p.Check, p.Object, p.Message)
+}
+
+// Doctor inspects a cluster through the given clients.
+type Doctor struct {
+	KubeClient        kubernetes.Interface
+	IstioClient       istioclientset.Interface
+	NetworkingClient  networkingclientset.Interface
+	IstioConfigClient netistioclientset.Interface
+}
+
+// Diagnose returns the problems found in the cluster. An error is returned when the
+// cluster can not be inspected.
+func (d *Doctor) Diagnose(ctx context.Context) ([]Problem, error) {
+	problems, err := d.checkIstioAPI()
+	if err != nil || len(problems) > 0 {
+		// The Istio resources can not be inspected.
+		return problems, err
+	}
+
+	ingressList, err := d.NetworkingClient.NetworkingV1alpha1().Ingresses(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Ingresses: %w", err)
+	}
+	vsList, err := d.IstioClient.NetworkingV1beta1().VirtualServices(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list VirtualServices: %w", err)
+	}
+	gatewayList, err := d.IstioClient.NetworkingV1beta1().Gateways(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Gateways: %w", err)
+	}
+
+	gatewayProblems, err := d.checkGateways(ctx, gatewayList.Items)
+	if err != nil {
+		return nil, err
+	}
+	problems = append(problems, gatewayProblems...)
+	problems = append(problems, checkIngresses(ingressList.Items)...)
+	problems = append(problems, checkOrphans(ingressList.Items, vsList.Items, gatewayList.Items)...)
+	secretProblems, err := d.checkSecrets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	problems = append(problems, secretProblems...)
+	problems = append(problems, checkServers(ingressList.Items, gatewayList.Items)...)
+	problems = append(problems, checkHosts(vsList.Items)...)
+	return problems, nil
+}
+
+// checkIstioAPI checks that the Istio API version used by the controller is served.
+func (d *Doctor) checkIstioAPI() ([]Problem, error) {
+	groupVersion := istiov1beta1.SchemeGroupVersion.String()
+	resourceList, err := d.KubeClient.Discovery().ServerResourcesForGroupVersion(groupVersion)
+	if apierrs.IsNotFound(err) {
+		return []Problem{{
+			Check:   CheckUnsupportedIstioAPI,
+			Object:  "APIGroup " + groupVersion,
+			Message: "the API version used by the controller is not served, are the Istio CRDs installed?",
+		}}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to discover %s: %w", groupVersion, err)
+	}
+
+	served := sets.New[string]()
+	for _, resource := range resourceList.APIResources {
+		served.Insert(resource.Name)
+	}
+	var problems []Problem
+	for _, resource := range []string{"gateways", "virtualservices"} {
+		if !served.Has(resource) {
+			problems = append(problems, Problem{
+				Check:   CheckUnsupportedIstioAPI,
+				Object:  "APIGroup " + groupVersion,
+				Message: fmt.Sprintf("%s are not served in the API version used by the controller", resource),
+			})
+		}
+	}
+	return problems, nil
+}
+
+// checkGateways checks that the Gateways configured in config-istio exist.
+func (d *Doctor) checkGateways(ctx context.Context, gateways []*istiov1beta1.Gateway) ([]Problem, error) {
+	istio, err := d.istioConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := sets.New[string]()
+	for _, gateway := range gateways {
+		existing.Insert(gateway.Namespace + "/" + gateway.Name)
+	}
+	var problems []Problem
+	for _, gateways := range [][]config.Gateway{istio.IngressGateways, istio.LocalGateways} {
+		for _, gateway := range gateways {
+			if !existing.Has(gateway.QualifiedName()) {
+				problems = append(problems, Problem{
+					Check:   CheckMissingGateway,
+					Object:  "Gateway " + gateway.QualifiedName(),
+					Message: "the Gateway is configured in " + config.IstioConfigName + " but does not exist",
+				})
+			}
+		}
+	}
+	return problems, nil
+}
+
+// istioConfig returns the configuration used by the controller, where the IstioConfig
+// takes precedence over the ConfigMap.
+func (d *Doctor) istioConfig(ctx context.Context) (*config.Istio, error) {
+	istioConfig, err := d.IstioConfigClient.IstioV1alpha1().IstioConfigs(system.Namespace()).Get(ctx, config.IstioConfigName, metav1.GetOptions{})
+	if err == nil {
+		istio, err := config.NewIstioFromIstioConfig(&istioConfig.Spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IstioConfig %s/%s: %w", system.Namespace(), config.IstioConfigName, err)
+		}
+		return istio, nil
+	} else if !apierrs.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get the IstioConfig: %w", err)
+	}
+
+	configMap, err := d.KubeClient.CoreV1().ConfigMaps(system.Namespace()).Get(ctx, config.IstioConfigName, metav1.GetOptions{})
+	if apierrs.IsNotFound(err) {
+		// The controller falls back to the defaults.
+		configMap = &corev1.ConfigMap{}
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get the %s ConfigMap: %w", config.IstioConfigName, err)
+	}
+	istio, err := config.NewIstioFromConfigMap(configMap)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ConfigMap %s/%s: %w", system.Namespace(), config.IstioConfigName, err)
+	}
+	return istio, nil
+}
+
+// checkIngresses reports the Ingresses of net-istio that are not ready.
+func checkIngresses(ingresses []v1alpha1.Ingress) []Problem {
+	var problems []Problem
+	for i := range ingresses {
+		ing := &ingresses[i]
+		if !isIstioIngress(ing) || ing.IsReady() {
+			continue
+		}
+		var reason string
+		cond := ing.Status.GetCondition(v1alpha1.IngressConditionReady)
+		switch {
+		case cond == nil:
+			reason = "the Ingress has not been reconciled"
+		case ing.Status.ObservedGeneration != ing.Generation:
+			reason = fmt.Sprintf("generation %d has not been reconciled", ing.Generation)
+		case cond.Reason == "" && cond.Message == "":
+			reason = fmt.Sprintf("the Ready condition is %s", cond.Status)
+		default:
+			reason = fmt.Sprintf("%s: %s", cond.Reason, cond.Message)
+		}
+		problems = append(problems, Problem{
+			Check:   CheckIngressNotReady,
+			Object:  "Ingress " + ing.Namespace + "/" + ing.Name,
+			Message: reason,
+		})
+	}
+	return problems
+}
+
+// checkOrphans reports the VirtualServices and Gateways owned by Ingresses that do not exist.
+func checkOrphans(ingresses []v1alpha1.Ingress, vses []*istiov1beta1.VirtualService, gateways []*istiov1beta1.Gateway) []Problem {
+	existing := sets.New[string]()
+	for i := range ingresses {
+		existing.Insert(ingresses[i].Namespace + "/" + ingresses[i].Name)
+	}
+	isOrphan := func(obj metav1.Object) bool {
+		owner := metav1.GetControllerOf(obj)
+		if owner == nil || owner.Kind != "Ingress" || !strings.HasPrefix(owner.APIVersion, networking.GroupName+"/") {
+			return false
+		}
+		return !existing.Has(obj.GetNamespace() + "/" + owner.Name)
+	}
+
+	var problems []Problem
+	for _, vs := range vses {
+		if isOrphan(vs) {
+			problems = append(problems, Problem{
+				Check:   CheckOrphanedVirtualService,
+				Object:  "VirtualService " + vs.Namespace + "/" + vs.Name,
+				Message: fmt.Sprintf("the owning Ingress %s does not exist", metav1.GetControllerOf(vs).Name),
+			})
+		}
+	}
+	for _, gateway := range gateways {
+		if isOrphan(gateway) {
+			problems = append(problems, Problem{
+				Check:   CheckOrphanedGateway,
+				Object:  "Gateway " + gateway.Namespace + "/" + gateway.Name,
+				Message: fmt.Sprintf("the owning Ingress %s does not exist", metav1.GetControllerOf(gateway).Name),
+			})
+		}
+	}
+	return problems
+}
+
+// checkSecrets reports the copies of TLS secrets whose origin secret does not exist.
+func (d *Doctor) checkSecrets(ctx context.Context) ([]Problem, error) {
+	secrets, err := d.KubeClient.CoreV1().Secrets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		LabelSelector: networking.OriginSecretNamespaceLabelKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Secrets: %w", err)
+	}
+
+	var problems []Problem
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		origin := resources.ExtractOriginSecretRef(secret)
+		_, err := d.KubeClient.CoreV1().Secrets(origin.Namespace).Get(ctx, origin.Name, metav1.GetOptions{})
+		if apierrs.IsNotFound(err) {
+			problems = append(problems, Problem{
+				Check:   CheckOrphanedSecret,
+				Object:  "Secret " + secret.Namespace + "/" + secret.Name,
+				Message: fmt.Sprintf("the origin secret %s/%s does not exist", origin.Namespace, origin.Name),
+			})
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to get Secret %s/%s: %w", origin.Namespace, origin.Name, err)
+		}
+	}
+	return problems, nil
+}
+
+// checkServers reports the Gateway servers made for Ingresses that do not exist.
+func checkServers(ingresses []v1alpha1.Ingress, gateways []*istiov1beta1.Gateway) []Problem {
+	prefixes := sets.New[string]()
+	for i := range ingresses {
+		prefixes.Insert(resources.IngressPortNamePrefix(&ingresses[i]))
+	}
+
+	var problems []Problem
+	for _, gateway := range gateways {
+		for _, server := range gateway.Spec.GetServers() {
+			if prefix, ok := resources.ServerPortNamePrefix(server); ok && !prefixes.Has(prefix) {
+				problems = append(problems, Problem{
+					Check:  CheckStaleGatewayServer,
+					Object: "Gateway " + gateway.Namespace + "/" + gateway.Name,
+					Message: fmt.Sprintf("the server %s for hosts %s belongs to the Ingress %s which does not exist",
+						server.GetPort().GetName(), strings.Join(server.GetHosts(), ","), prefix),
+				})
+			}
+		}
+	}
+	return problems
+}
+
+// checkHosts reports the hosts claimed by more than one VirtualService on the same gateway.
+func checkHosts(vses []*istiov1beta1.VirtualService) []Problem {
+	type gatewayHost struct {
+		gateway, host string
+	}
+	claims := make(map[gatewayHost][]string)
+	for _, vs := range vses {
+		gateways := vs.Spec.GetGateways()
+		if len(gateways) == 0 {
+			gateways = []string{meshGateway}
+		}
+		for _, gateway := range gateways {
+			if gateway != meshGateway && !strings.Contains(gateway, "/") {
+				gateway = vs.Namespace + "/" + gateway
+			}
+			for _, host := range vs.Spec.GetHosts() {
+				key := gatewayHost{gateway: gateway, host: host}
+				claims[key] = append(claims[key], vs.Namespace+"/"+vs.Name)
+			}
+		}
+	}
+
+	var problems []Problem
+	for key, names := range claims {
+		if len(names) < 2 {
+			continue
+		}
+		sort.Strings(names)
+		problems = append(problems, Problem{
+			Check:   CheckConflictingHost,
+			Object:  "Host " + key.host,
+			Message: fmt.Sprintf("claimed on gateway %s by the VirtualServices %s", key.gateway, strings.Join(names, ", ")),
+		})
+	}
+	sort.Slice(problems, func(i, j int) bool {
+		if problems[i].Object != problems[j].Object {
+			return problems[i].Object < problems[j].Object
+		}
+		return problems[i].Message < problems[j].Message
+	})
+	return problems
+}
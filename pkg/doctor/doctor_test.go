@@ -0,0 +1,332 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package doctor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	istioapiv1beta1 "istio.io/api/networking/v1beta1"
+	istiov1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
+	fakeistioclientset "istio.io/client-go/pkg/clientset/versioned/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	fakekubeclientset "k8s.io/client-go/kubernetes/fake"
+	istioconfigv1alpha1 "knative.dev/net-istio/pkg/apis/istio/v1alpha1"
+	fakenetistioclientset "knative.dev/net-istio/pkg/client/clientset/versioned/fake"
+	"knative.dev/net-istio/pkg/reconciler/ingress/config"
+	"knative.dev/networking/pkg/apis/networking"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+	fakenetworkingclientset "knative.dev/networking/pkg/client/clientset/versioned/fake"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/kmeta"
+
+	_ "knative.dev/pkg/system/testing"
+)
+
+var (
+	istioAPI = &metav1.APIResourceList{
+		GroupVersion: istiov1beta1.SchemeGroupVersion.String(),
+		APIResources: []metav1.APIResource{{Name: "gateways"}, {Name: "virtualservices"}},
+	}
+
+	readyIngress = ingress("hello", apis.Condition{
+		Type:   v1alpha1.IngressConditionReady,
+		Status: corev1.ConditionTrue,
+	})
+
+	ingressGateway = &istiov1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "knative-testing", Name: config.KnativeIngressGateway},
+		Spec: istioapiv1beta1.Gateway{
+			Servers: []*istioapiv1beta1.Server{{
+				Hosts: []string{"hello.example.com"},
+				Port:  &istioapiv1beta1.Port{Name: "default/hello:0", Number: 443, Protocol: "HTTPS"},
+			}, {
+				Hosts: []string{"*"},
+				Port:  &istioapiv1beta1.Port{Name: "http-server", Number: 80, Protocol: "HTTP"},
+			}},
+		},
+	}
+
+	localGateway = &istiov1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "knative-testing", Name: config.KnativeLocalGateway},
+	}
+
+	originSecret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "hello-tls"},
+	}
+
+	copiedSecret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "istio-system",
+			Name:      "hello-uid",
+			Labels: map[string]string{
+				networking.OriginSecretNamespaceLabelKey: "default",
+				networking.OriginSecretNameLabelKey:      "hello-tls",
+			},
+		},
+	}
+)
+
+func TestDiagnose(t *testing.T) {
+	healthy := []runtime.Object{
+		readyIngress,
+		virtualService("hello-ingress", readyIngress, []string{"knative-testing/" + config.KnativeIngressGateway}, "hello.example.com"),
+		virtualService("hello-mesh", readyIngress, nil, "hello.default.svc.cluster.local"),
+		ingressGateway,
+		localGateway,
+		originSecret,
+		copiedSecret,
+	}
+
+	tests := []struct {
+		name    string
+		api     *metav1.APIResourceList
+		objects []runtime.Object
+		want    []Problem
+	}{{
+		name:    "healthy",
+		api:     istioAPI,
+		objects: healthy,
+	}, {
+		name:    "istio API not served",
+		objects: healthy,
+		want: []Problem{{
+			Check:   CheckUnsupportedIstioAPI,
+			Object:  "APIGroup networking.istio.io/v1beta1",
+			Message: "the API version used by the controller is not served, are the Istio CRDs installed?",
+		}},
+	}, {
+		name: "virtualservices not served",
+		api: &metav1.APIResourceList{
+			GroupVersion: istiov1beta1.SchemeGroupVersion.String(),
+			APIResources: []metav1.APIResource{{Name: "gateways"}},
+		},
+		objects: healthy,
+		want: []Problem{{
+			Check:   CheckUnsupportedIstioAPI,
+			Object:  "APIGroup networking.istio.io/v1beta1",
+			Message: "virtualservices are not served in the API version used by the controller",
+		}},
+	}, {
+		name:    "missing default gateway",
+		api:     istioAPI,
+		objects: without(healthy, localGateway),
+		want: []Problem{{
+			Check:   CheckMissingGateway,
+			Object:  "Gateway knative-testing/knative-local-gateway",
+			Message: "the Gateway is configured in config-istio but does not exist",
+		}},
+	}, {
+		name: "IstioConfig takes precedence",
+		api:  istioAPI,
+		objects: append(without(healthy, localGateway), &istioconfigv1alpha1.IstioConfig{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "knative-testing", Name: config.IstioConfigName},
+			Spec: istioconfigv1alpha1.IstioConfigSpec{
+				ExternalGateways: []istioconfigv1alpha1.Gateway{{
+					Namespace: "knative-testing",
+					Name:      config.KnativeIngressGateway,
+					Service:   "istio-ingressgateway.istio-system.svc.cluster.local",
+				}},
+				LocalGateways: []istioconfigv1alpha1.Gateway{},
+			},
+		}),
+	}, {
+		name: "ingress not ready",
+		api:  istioAPI,
+		objects: append(healthy, ingress("failing", apis.Condition{
+			Type:    v1alpha1.IngressConditionReady,
+			Status:  corev1.ConditionFalse,
+			Reason:  "ReconcileVirtualServiceFailed",
+			Message: "conflict",
+		}), ingress("new"), withClass(ingress("other"), "kourier.ingress.networking.knative.dev")),
+		want: []Problem{{
+			Check:   CheckIngressNotReady,
+			Object:  "Ingress default/failing",
+			Message: "ReconcileVirtualServiceFailed: conflict",
+		}, {
+			Check:   CheckIngressNotReady,
+			Object:  "Ingress default/new",
+			Message: "the Ingress has not been reconciled",
+		}},
+	}, {
+		name: "orphaned resources",
+		api:  istioAPI,
+		objects: append(healthy,
+			virtualService("gone-ingress", ingress("gone"), nil, "gone.example.com"),
+			&istiov1beta1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:       "default",
+					Name:            "gone-gateway",
+					OwnerReferences: []metav1.OwnerReference{*kmeta.NewControllerRef(ingress("gone"))},
+				},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "istio-system",
+					Name:      "gone-uid",
+					Labels: map[string]string{
+						networking.OriginSecretNamespaceLabelKey: "default",
+						networking.OriginSecretNameLabelKey:      "gone-tls",
+					},
+				},
+			}),
+		want: []Problem{{
+			Check:   CheckOrphanedVirtualService,
+			Object:  "VirtualService default/gone-ingress",
+			Message: "the owning Ingress gone does not exist",
+		}, {
+			Check:   CheckOrphanedGateway,
+			Object:  "Gateway default/gone-gateway",
+			Message: "the owning Ingress gone does not exist",
+		}, {
+			Check:   CheckOrphanedSecret,
+			Object:  "Secret istio-system/gone-uid",
+			Message: "the origin secret default/gone-tls does not exist",
+		}},
+	}, {
+		name: "stale gateway server",
+		api:  istioAPI,
+		objects: append(without(healthy, ingressGateway), &istiov1beta1.Gateway{
+			ObjectMeta: ingressGateway.ObjectMeta,
+			Spec: istioapiv1beta1.Gateway{
+				Servers: append(ingressGateway.Spec.Servers, &istioapiv1beta1.Server{
+					Hosts: []string{"gone.example.com"},
+					Port:  &istioapiv1beta1.Port{Name: "default/gone:0", Number: 443, Protocol: "HTTPS"},
+				}),
+			},
+		}),
+		want: []Problem{{
+			Check:   CheckStaleGatewayServer,
+			Object:  "Gateway knative-testing/knative-ingress-gateway",
+			Message: "the server default/gone:0 for hosts gone.example.com belongs to the Ingress default/gone which does not exist",
+		}},
+	}, {
+		name: "conflicting hosts",
+		api:  istioAPI,
+		objects: append(healthy,
+			virtualService("custom", nil, []string{"knative-testing/" + config.KnativeIngressGateway}, "hello.example.com"),
+			// Different gateway.
+			virtualService("custom-local", nil, []string{config.KnativeLocalGateway}, "hello.example.com")),
+		want: []Problem{{
+			Check:   CheckConflictingHost,
+			Object:  "Host hello.example.com",
+			Message: "claimed on gateway knative-testing/knative-ingress-gateway by the VirtualServices default/custom, default/hello-ingress",
+		}},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d := newDoctor(t, test.api, test.objects)
+			got, err := d.Diagnose(context.Background())
+			if err != nil {
+				t.Fatal("Diagnose() =", err)
+			}
+			if !cmp.Equal(got, test.want, cmpopts.EquateEmpty()) {
+				t.Error("Diagnose() (-want, +got):", cmp.Diff(test.want, got, cmpopts.EquateEmpty()))
+			}
+		})
+	}
+}
+
+func newDoctor(t *testing.T, api *metav1.APIResourceList, objects []runtime.Object) *Doctor {
+	t.Helper()
+	ctx := context.Background()
+	kubeClient := fakekubeclientset.NewClientset()
+	if api != nil {
+		kubeClient.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{api}
+	}
+	istioClient := fakeistioclientset.NewSimpleClientset()
+	networkingClient := fakenetworkingclientset.NewSimpleClientset()
+	istioConfigClient := fakenetistioclientset.NewSimpleClientset()
+
+	for _, obj := range objects {
+		var err error
+		switch o := obj.(type) {
+		case *v1alpha1.Ingress:
+			_, err = networkingClient.NetworkingV1alpha1().Ingresses(o.Namespace).Create(ctx, o, metav1.CreateOptions{})
+		case *istiov1beta1.VirtualService:
+			_, err = istioClient.NetworkingV1beta1().VirtualServices(o.Namespace).Create(ctx, o, metav1.CreateOptions{})
+		case *istiov1beta1.Gateway:
+			_, err = istioClient.NetworkingV1beta1().Gateways(o.Namespace).Create(ctx, o, metav1.CreateOptions{})
+		case *corev1.Secret:
+			_, err = kubeClient.CoreV1().Secrets(o.Namespace).Create(ctx, o, metav1.CreateOptions{})
+		case *istioconfigv1alpha1.IstioConfig:
+			_, err = istioConfigClient.IstioV1alpha1().IstioConfigs(o.Namespace).Create(ctx, o, metav1.CreateOptions{})
+		default:
+			t.Fatalf("Unexpected object %T", obj)
+		}
+		if err != nil {
+			t.Fatal("Failed to create object:", err)
+		}
+	}
+
+	return &Doctor{
+		KubeClient:        kubeClient,
+		IstioClient:       istioClient,
+		NetworkingClient:  networkingClient,
+		IstioConfigClient: istioConfigClient,
+	}
+}
+
+func ingress(name string, conditions ...apis.Condition) *v1alpha1.Ingress {
+	return &v1alpha1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "default",
+			Name:        name,
+			Generation:  1,
+			Annotations: map[string]string{networking.IngressClassAnnotationKey: "istio.ingress.networking.knative.dev"},
+		},
+		Status: v1alpha1.IngressStatus{
+			Status: duckv1.Status{ObservedGeneration: 1, Conditions: conditions},
+		},
+	}
+}
+
+func withClass(ing *v1alpha1.Ingress, class string) *v1alpha1.Ingress {
+	ing.Annotations[networking.IngressClassAnnotationKey] = class
+	return ing
+}
+
+func virtualService(name string, owner *v1alpha1.Ingress, gateways []string, hosts ...string) *istiov1beta1.VirtualService {
+	vs := &istiov1beta1.VirtualService{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		Spec: istioapiv1beta1.VirtualService{
+			Gateways: gateways,
+			Hosts:    hosts,
+		},
+	}
+	if owner != nil {
+		vs.OwnerReferences = []metav1.OwnerReference{*kmeta.NewControllerRef(owner)}
+	}
+	return vs
+}
+
+func without(objects []runtime.Object, remove runtime.Object) []runtime.Object {
+	ret := make([]runtime.Object, 0, len(objects))
+	for _, obj := range objects {
+		if obj != remove {
+			ret = append(ret, obj)
+		}
+	}
+	return ret
+}
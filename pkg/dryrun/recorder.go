@@ -0,0 +1,138 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dryrun runs the controller in shadow of another one: all its writes to the
+// API server are turned into server-side dry runs, and the changes they would have
+// made are logged, counted and served on a debug endpoint instead.
+package dryrun
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/observability/attributekey"
+)
+
+const (
+	// EnabledEnvKey is the environment variable enabling the dry-run mode of the controller.
+	EnabledEnvKey = "DRY_RUN"
+
+	// PortEnvKey is the environment variable overriding the port of the debug endpoint.
+	PortEnvKey = "DRY_RUN_PORT"
+
+	// DefaultPort is the default port of the debug endpoint.
+	DefaultPort = 8009
+
+	// Path is the path of the debug endpoint listing the changes.
+	Path = "/debug/dryrun"
+
+	scopeName = "knative.dev/net-istio/pkg/dryrun"
+)
+
+var (
+	resourceAttr  = attributekey.String("kn.netistio.resource")
+	operationAttr = attributekey.String("kn.netistio.operation")
+)
+
+// Change is a change to an object the controller would have made.
+type Change struct {
+	Operation   string    `json:"operation"`
+	Resource    string    `json:"resource"`
+	Subresource string    `json:"subresource,omitempty"`
+	Namespace   string    `json:"namespace,omitempty"`
+	Name        string    `json:"name"`
+	Diff        string    `json:"diff"`
+	Time        time.Time `json:"time"`
+}
+
+func (c *Change) key() string {
+	return c.Resource + "/" + c.Subresource + "/" + c.Namespace + "/" + c.Name
+}
+
+// Recorder keeps the latest change of every object. As nothing is written, the
+// controller retries the same changes on every resync until another controller
+// makes them.
+type Recorder struct {
+	mu      sync.Mutex
+	changes map[string]Change
+
+	changeCount metric.Int64Counter
+}
+
+// NewRecorder returns a Recorder counting the changes with the given provider, or the
+// global one when nil.
+func NewRecorder(provider metric.MeterProvider) *Recorder {
+	if provider == nil {
+		provider = otel.GetMeterProvider()
+	}
+	changeCount, err := provider.Meter(scopeName).Int64Counter(
+		"kn.netistio.dryrun.changes",
+		metric.WithDescription("The number of changes the controller would have made in dry-run mode."),
+		metric.WithUnit("{change}"),
+	)
+	if err != nil {
+		panic(err)
+	}
+	return &Recorder{
+		changes:     make(map[string]Change),
+		changeCount: changeCount,
+	}
+}
+
+// Record records the given change.
+func (r *Recorder) Record(ctx context.Context, change Change) {
+	logging.FromContext(ctx).Infow("Dry run of "+change.Operation,
+		"resource", change.Resource, "subresource", change.Subresource,
+		"namespace", change.Namespace, "name", change.Name, "diff", change.Diff)
+	r.changeCount.Add(ctx, 1, metric.WithAttributes(
+		resourceAttr.With(change.Resource),
+		operationAttr.With(change.Operation),
+	))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.changes[change.key()] = change
+}
+
+// Changes returns the latest change of every object, ordered by object.
+func (r *Recorder) Changes() []Change {
+	r.mu.Lock()
+	ret := make([]Change, 0, len(r.changes))
+	for _, change := range r.changes {
+		ret = append(ret, change)
+	}
+	r.mu.Unlock()
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].key() < ret[j].key()
+	})
+	return ret
+}
+
+// ServeHTTP serves the changes as JSON.
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(r.Changes()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,229 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dryrun
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var operations = map[string]string{
+	http.MethodPost:   "create",
+	http.MethodPut:    "update",
+	http.MethodPatch:  "patch",
+	http.MethodDelete: "delete",
+}
+
+// The fields set by the API server, which are left out of the diffs.
+var serverFields = []string{"resourceVersion", "uid", "generation", "creationTimestamp", "managedFields"}
+
+// Wrap returns a transport turning the writes of the requests passed to the given
+// transport into dry runs, and recording the changes they would have made. It is meant
+// to be the WrapTransport of the rest.Config of the controller, whose clients must use
+// JSON.
+func (r *Recorder) Wrap(rt http.RoundTripper) http.RoundTripper {
+	return &transport{next: rt, recorder: r}
+}
+
+type transport struct {
+	next     http.RoundTripper
+	recorder *Recorder
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	operation, ok := operations[req.Method]
+	if !ok {
+		return t.next.RoundTrip(req)
+	}
+	info, ok := parsePath(req.URL.Path)
+	if !ok {
+		return t.next.RoundTrip(req)
+	}
+
+	var current []byte
+	if req.Method != http.MethodPost && info.name != "" {
+		var err error
+		if current, err = t.get(req); err != nil {
+			return nil, err
+		}
+	}
+
+	dryRun := req.Clone(req.Context())
+	query := dryRun.URL.Query()
+	query.Set("dryRun", metav1.DryRunAll)
+	dryRun.URL.RawQuery = query.Encode()
+	resp, err := t.next.RoundTrip(dryRun)
+	if err != nil || resp.StatusCode >= http.StatusMultipleChoices || info.isEvent() {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	desired := body
+	if req.Method == http.MethodDelete {
+		desired = nil
+	}
+	diff, name, err := diffObjects(current, desired)
+	if err != nil {
+		diff = fmt.Sprintf("failed to compute the diff: %v", err)
+	}
+	if diff == "" {
+		return resp, nil
+	}
+	if info.name == "" {
+		info.name = name
+	}
+	t.recorder.Record(req.Context(), Change{
+		Operation:   operation,
+		Resource:    info.resource(),
+		Subresource: info.subresource,
+		Namespace:   info.namespace,
+		Name:        info.name,
+		Diff:        diff,
+		Time:        time.Now(),
+	})
+	return resp, nil
+}
+
+// get returns the object the given request writes, or nil if it does not exist.
+func (t *transport) get(req *http.Request) ([]byte, error) {
+	get, err := http.NewRequestWithContext(req.Context(), http.MethodGet, req.URL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	get.URL.RawQuery = ""
+	get.Header = req.Header.Clone()
+	get.Header.Del("Content-Type")
+	get.Header.Set("Accept", "application/json")
+
+	resp, err := t.next.RoundTrip(get)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, nil
+	case resp.StatusCode >= http.StatusMultipleChoices:
+		return nil, fmt.Errorf("failed to get %s: %s", req.URL.Path, resp.Status)
+	}
+	return body, nil
+}
+
+// diffObjects returns the diff between the given JSON objects, without the fields set
+// by the API server, and the name of the desired object.
+func diffObjects(current, desired []byte) (string, string, error) {
+	currentObj, err := decode(current)
+	if err != nil {
+		return "", "", err
+	}
+	desiredObj, err := decode(desired)
+	if err != nil {
+		return "", "", err
+	}
+
+	var name string
+	if metadata, ok := desiredObj["metadata"].(map[string]any); ok {
+		name, _ = metadata["name"].(string)
+	}
+	return cmp.Diff(currentObj, desiredObj), name, nil
+}
+
+func decode(obj []byte) (map[string]any, error) {
+	if len(obj) == 0 {
+		return nil, nil
+	}
+	var ret map[string]any
+	if err := json.Unmarshal(obj, &ret); err != nil {
+		return nil, err
+	}
+	if metadata, ok := ret["metadata"].(map[string]any); ok {
+		for _, field := range serverFields {
+			delete(metadata, field)
+		}
+	}
+	return ret, nil
+}
+
+// requestInfo identifies the object of a request to the API server.
+type requestInfo struct {
+	group       string
+	namespace   string
+	plural      string
+	name        string
+	subresource string
+}
+
+// parsePath parses paths of the form /api/v1/namespaces/ns/plural/name/subresource
+// or /apis/group/version/namespaces/ns/plural/name/subresource.
+func parsePath(path string) (requestInfo, bool) {
+	var info requestInfo
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case len(segments) >= 3 && segments[0] == "api":
+		segments = segments[2:]
+	case len(segments) >= 4 && segments[0] == "apis":
+		info.group = segments[1]
+		segments = segments[3:]
+	default:
+		return info, false
+	}
+
+	if len(segments) > 2 && segments[0] == "namespaces" {
+		info.namespace = segments[1]
+		segments = segments[2:]
+	}
+	info.plural = segments[0]
+	if len(segments) > 1 {
+		info.name = segments[1]
+	}
+	if len(segments) > 2 {
+		info.subresource = strings.Join(segments[2:], "/")
+	}
+	return info, true
+}
+
+// resource returns the resource like kubectl, e.g. virtualservices.networking.istio.io.
+func (i *requestInfo) resource() string {
+	if i.group == "" {
+		return i.plural
+	}
+	return i.plural + "." + i.group
+}
+
+// isEvent returns whether the object is an event, whose dry runs are not recorded.
+func (i *requestInfo) isEvent() bool {
+	return i.plural == "events" && (i.group == "" || i.group == "events.k8s.io")
+}
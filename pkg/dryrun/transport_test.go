@@ -0,0 +1,213 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dryrun
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// apiServer serves the objects of a map, and fails the test on writes which are not
+// dry runs.
+type apiServer struct {
+	t       *testing.T
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (s *apiServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == http.MethodGet {
+		obj, ok := s.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(&metav1.Status{Status: metav1.StatusFailure, Code: http.StatusNotFound, Reason: metav1.StatusReasonNotFound})
+			return
+		}
+		w.Write(obj)
+		return
+	}
+
+	if got := r.URL.Query().Get("dryRun"); got != metav1.DryRunAll {
+		s.t.Errorf("%s %s: dryRun = %q, want %q", r.Method, r.URL.Path, got, metav1.DryRunAll)
+	}
+	switch r.Method {
+	case http.MethodDelete:
+		json.NewEncoder(w).Encode(&metav1.Status{Status: metav1.StatusSuccess})
+	default:
+		body, _ := io.ReadAll(r.Body)
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+		}
+		w.Write(body)
+	}
+}
+
+func secret(name, value string) *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "istio-system", Name: name, ResourceVersion: "1"},
+		StringData: map[string]string{"key": value},
+	}
+}
+
+func TestTransport(t *testing.T) {
+	existing, _ := json.Marshal(secret("existing", "old"))
+	server := &apiServer{
+		t: t,
+		objects: map[string][]byte{
+			"/api/v1/namespaces/istio-system/secrets/existing": existing,
+		},
+	}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	reader := sdkmetric.NewManualReader()
+	recorder := NewRecorder(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	cfg := &rest.Config{Host: ts.URL, WrapTransport: recorder.Wrap}
+	cfg.ContentType = runtime.ContentTypeJSON
+	client := kubernetes.NewForConfigOrDie(cfg)
+	ctx := context.Background()
+	secrets := client.CoreV1().Secrets("istio-system")
+
+	if _, err := secrets.Create(ctx, secret("new", "value"), metav1.CreateOptions{}); err != nil {
+		t.Fatal("Create() =", err)
+	}
+	if _, err := secrets.Update(ctx, secret("existing", "new"), metav1.UpdateOptions{}); err != nil {
+		t.Fatal("Update() =", err)
+	}
+	// An update without changes is not recorded.
+	if _, err := secrets.Update(ctx, secret("existing", "old"), metav1.UpdateOptions{}); err != nil {
+		t.Fatal("Update() =", err)
+	}
+	if err := secrets.Delete(ctx, "existing", metav1.DeleteOptions{}); err != nil {
+		t.Fatal("Delete() =", err)
+	}
+	if _, err := client.CoreV1().Events("istio-system").Create(ctx, &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "istio-system", Name: "event"},
+	}, metav1.CreateOptions{}); err != nil {
+		t.Fatal("Create() =", err)
+	}
+
+	// The existing secret was both updated and deleted, the latest change is kept.
+	changes := recorder.Changes()
+	got := make([]string, 0, len(changes))
+	for _, change := range changes {
+		got = append(got, change.Operation+" "+change.Resource+" "+change.Namespace+"/"+change.Name)
+	}
+	want := []string{"delete secrets istio-system/existing", "create secrets istio-system/new"}
+	if !cmp.Equal(got, want) {
+		t.Error("Changes (-want, +got):", cmp.Diff(want, got))
+	}
+	for _, change := range changes {
+		if change.Diff == "" || strings.Contains(change.Diff, "resourceVersion") {
+			t.Errorf("Diff of %s = %q, want a diff without server fields", change.Name, change.Diff)
+		}
+	}
+
+	if got, want := len(server.objects), 1; got != want {
+		t.Errorf("Number of objects = %d, want %d", got, want)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatal("Collect() =", err)
+	}
+	var total int64
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			for _, point := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				total += point.Value
+			}
+		}
+	}
+	if total != 3 {
+		t.Errorf("Number of changes counted = %d, want 3", total)
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	recorder := NewRecorder(nil)
+	recorder.Record(context.Background(), Change{Operation: "update", Resource: "gateways.networking.istio.io", Namespace: "knative-serving", Name: "knative-ingress-gateway", Diff: "diff"})
+
+	rec := httptest.NewRecorder()
+	recorder.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, Path, nil))
+
+	var got []Change
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatal("Failed to decode the changes:", err)
+	}
+	if len(got) != 1 || got[0].Name != "knative-ingress-gateway" || got[0].Diff != "diff" {
+		t.Errorf("ServeHTTP() = %v, want the recorded change", got)
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		path   string
+		want   requestInfo
+		wantOK bool
+	}{{
+		path:   "/api/v1/namespaces/ns/secrets/name",
+		want:   requestInfo{namespace: "ns", plural: "secrets", name: "name"},
+		wantOK: true,
+	}, {
+		path:   "/api/v1/namespaces/ns",
+		want:   requestInfo{plural: "namespaces", name: "ns"},
+		wantOK: true,
+	}, {
+		path:   "/apis/networking.istio.io/v1beta1/namespaces/ns/virtualservices",
+		want:   requestInfo{group: "networking.istio.io", namespace: "ns", plural: "virtualservices"},
+		wantOK: true,
+	}, {
+		path:   "/apis/networking.internal.knative.dev/v1alpha1/namespaces/ns/ingresses/name/status",
+		want:   requestInfo{group: "networking.internal.knative.dev", namespace: "ns", plural: "ingresses", name: "name", subresource: "status"},
+		wantOK: true,
+	}, {
+		path: "/version",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			got, ok := parsePath(test.path)
+			if ok != test.wantOK {
+				t.Fatalf("parsePath() = %t, want %t", ok, test.wantOK)
+			}
+			if ok && got != test.want {
+				t.Errorf("parsePath() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
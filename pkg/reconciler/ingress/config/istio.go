@@ -19,12 +19,17 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net/http"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation"
+	cm "knative.dev/pkg/configmap"
 	"knative.dev/pkg/network"
 	"knative.dev/pkg/system"
 	"sigs.k8s.io/yaml"
@@ -41,11 +46,50 @@ const (
 	// localGatewayKeyPrefix is the prefix of all keys to configure Istio gateways for public & private Ingresses.
 	localGatewayKeyPrefix = "local-gateway."
 
-	// externalGatewaysKey is the configmap key to configure Istio gateways for public Ingresses.
-	externalGatewaysKey = "external-gateways"
+	// ExternalGatewaysKey is the configmap key to configure Istio gateways for public Ingresses.
+	ExternalGatewaysKey = "external-gateways"
 
-	// localGatewaysKey is the configmap key to configure Istio gateways for private Ingresses.
-	localGatewaysKey = "local-gateways"
+	// LocalGatewaysKey is the configmap key to configure Istio gateways for private Ingresses.
+	LocalGatewaysKey = "local-gateways"
+
+	// enableHTTP3Key is the configmap key to serve HTTP/3 next to the HTTPS servers of the
+	// external gateways.
+	enableHTTP3Key = "enable-http3"
+
+	// HTTPSRedirectCodeKey is the key to configure the status code of HTTPS redirects.
+	HTTPSRedirectCodeKey = "https-redirect-code"
+
+	// HTTPSRedirectPortKey is the key to configure the port HTTPS redirects point to.
+	HTTPSRedirectPortKey = "https-redirect-port"
+
+	// HTTPSRedirectExemptPathsKey is the key to configure the comma-separated path prefixes
+	// that are served over plain HTTP instead of being redirected to HTTPS.
+	HTTPSRedirectExemptPathsKey = "https-redirect-exempt-paths"
+
+	// ProbeHostsKey is the configmap key to configure which hosts of an Ingress are probed
+	// on each gateway: "single", "all" or the maximum number of hosts to probe.
+	ProbeHostsKey = "probe-hosts"
+
+	// ReadinessStrategyKey is the configmap key to configure how the readiness of Ingresses
+	// is checked: "probe", "config-status" or "both".
+	ReadinessStrategyKey = "readiness-strategy"
+
+	// ProbePodsKey is the configmap key to configure how many pods of each gateway are
+	// probed before an Ingress is marked ready: "all" or a positive number.
+	ProbePodsKey = "probe-pods"
+
+	// ProbeQuorumKey is the configmap key to configure the percentage of the probed pods
+	// of each gateway which must serve an Ingress before it is marked ready, 0 meaning all
+	// of them like 100.
+	ProbeQuorumKey = "probe-quorum"
+
+	// ProbeIPFamiliesKey is the configmap key to configure which IP families of the gateway
+	// pods are probed: "primary" or "all".
+	ProbeIPFamiliesKey = "probe-ip-families"
+
+	// DomainClaimsKey is the configmap key to configure which namespaces may claim the
+	// hosts of which domains.
+	DomainClaimsKey = "domain-claims"
 
 	// KnativeIngressGateway is the name of the ingress gateway
 	KnativeIngressGateway = "knative-ingress-gateway"
@@ -82,6 +126,12 @@ type Gateway struct {
 	Name          string
 	ServiceURL    string                `json:"service"`
 	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+	// NamespaceSelector selects the Ingresses by the labels of their namespace.
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// Domains are the domain suffixes of the hosts served by the gateway, e.g.
+	// "*.internal.example.com". Hosts matching them are placed on the gateway
+	// instead of the gateways selected for their Ingress.
+	Domains []string `json:"domains,omitempty"`
 }
 
 // QualifiedName returns gateway name in '{namespace}/{name}' format.
@@ -110,6 +160,60 @@ func (g Gateway) Validate() error {
 		return fmt.Errorf("failed to create selector from label selector: %w", err)
 	}
 
+	if _, err := metav1.LabelSelectorAsSelector(g.NamespaceSelector); err != nil {
+		return fmt.Errorf("failed to create selector from namespace selector: %w", err)
+	}
+
+	if len(g.Domains) > 0 && (g.LabelSelector != nil || g.NamespaceSelector != nil) {
+		return errors.New("domains can not be combined with a label or namespace selector")
+	}
+
+	for _, domain := range g.Domains {
+		if errs := validation.IsDNS1123Subdomain(strings.TrimPrefix(domain, "*.")); len(errs) > 0 {
+			return fmt.Errorf("invalid domain %q: %v", domain, errs)
+		}
+	}
+
+	return nil
+}
+
+// MatchDomain returns the length of the most specific domain of the gateway matching
+// the given host, or 0 if none matches. A domain starting with "*." only matches its
+// subdomains while other domains match themselves and their subdomains.
+func (g Gateway) MatchDomain(host string) int {
+	longest := 0
+	for _, domain := range g.Domains {
+		if matchDomain(domain, host) && len(domain) > longest {
+			longest = len(domain)
+		}
+	}
+	return longest
+}
+
+// matchDomain returns true if the given host matches the given domain. A domain starting
+// with "*." only matches its subdomains while other domains match themselves and their
+// subdomains.
+func matchDomain(domain, host string) bool {
+	if suffix, ok := strings.CutPrefix(domain, "*."); ok {
+		return strings.HasSuffix(host, "."+suffix)
+	}
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// DomainClaim reserves the hosts of a domain to the Ingresses of some namespaces.
+type DomainClaim struct {
+	// Domain is the domain of the claimed hosts, e.g. "*.example.com" for the subdomains
+	// of example.com or "example.com" for example.com as well.
+	Domain string `json:"domain"`
+	// Namespaces are the namespaces whose Ingresses may claim the hosts of the domain.
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// Validate validates the domain claim.
+func (c DomainClaim) Validate() error {
+	if errs := validation.IsDNS1123Subdomain(strings.TrimPrefix(c.Domain, "*.")); len(errs) > 0 {
+		return fmt.Errorf("invalid domain %q: %v", c.Domain, errs)
+	}
 	return nil
 }
 
@@ -121,6 +225,207 @@ type Istio struct {
 
 	// LocalGateways specifies the gateway urls for public & private Ingress.
 	LocalGateways []Gateway
+
+	// EnableHTTP3 specifies whether HTTP/3 is served and advertised next to the HTTPS
+	// servers of the external gateways.
+	EnableHTTP3 bool
+
+	// HTTPSRedirect specifies how Ingresses using the Redirected HTTP option redirect
+	// plain HTTP requests to HTTPS.
+	HTTPSRedirect HTTPSRedirect
+
+	// ProbeHosts specifies how many hosts of an Ingress are probed on each gateway. The
+	// zero value probes a single host, ProbeAllHosts probes all of them.
+	ProbeHosts int
+
+	// ReadinessStrategy specifies how the readiness of Ingresses is checked. The zero
+	// value probes the gateways, like ReadinessStrategyProbe.
+	ReadinessStrategy ReadinessStrategy
+
+	// ProbePods specifies how many pods of each gateway are probed before an Ingress is
+	// marked ready, the other pods being probed in the background. The zero value probes
+	// all of them.
+	ProbePods int
+
+	// ProbeQuorum specifies the percentage of the probed pods of each gateway which must
+	// serve an Ingress before it is marked ready. The zero value requires all of them.
+	ProbeQuorum int
+
+	// ProbeIPFamilies specifies which IP families of the gateway pods are probed. The zero
+	// value probes the primary IP family of the gateway Services, like
+	// ProbeIPFamiliesPrimary.
+	ProbeIPFamilies ProbeIPFamilies
+
+	// DomainClaims reserve the hosts of domains to the Ingresses of some namespaces. The
+	// hosts of other domains may be claimed by any namespace.
+	DomainClaims []DomainClaim
+}
+
+// MayClaimHost returns true if the Ingresses of the given namespace may claim the given
+// host, which is the case if the most specific domain claim matching the host lists the
+// namespace, or if no domain claim matches it.
+func (i Istio) MayClaimHost(namespace, host string) bool {
+	var claim *DomainClaim
+	for j, c := range i.DomainClaims {
+		if matchDomain(c.Domain, host) && (claim == nil || len(c.Domain) > len(claim.Domain)) {
+			claim = &i.DomainClaims[j]
+		}
+	}
+	return claim == nil || slices.Contains(claim.Namespaces, namespace)
+}
+
+// ProbeIPFamilies is which IP families of the gateway pods are probed.
+type ProbeIPFamilies string
+
+const (
+	// ProbeIPFamiliesPrimary probes the gateway pods on the primary IP family of the
+	// gateway Services.
+	ProbeIPFamiliesPrimary ProbeIPFamilies = "primary"
+
+	// ProbeIPFamiliesAll probes the gateway pods on all the IP families of dual-stack
+	// gateway Services, and requires them to be ready on all of them.
+	ProbeIPFamiliesAll ProbeIPFamilies = "all"
+)
+
+// parseProbeIPFamilies parses the value of the probe-ip-families key.
+func parseProbeIPFamilies(value string) (ProbeIPFamilies, error) {
+	switch families := ProbeIPFamilies(value); families {
+	case "", ProbeIPFamiliesPrimary, ProbeIPFamiliesAll:
+		return families, nil
+	}
+	return "", fmt.Errorf("invalid %s %q, must be %q or %q", ProbeIPFamiliesKey, value,
+		ProbeIPFamiliesPrimary, ProbeIPFamiliesAll)
+}
+
+// ReadinessStrategy is how the readiness of Ingresses is checked.
+type ReadinessStrategy string
+
+const (
+	// ReadinessStrategyProbe probes the gateway pods for the hosts of the Ingresses.
+	ReadinessStrategyProbe ReadinessStrategy = "probe"
+
+	// ReadinessStrategyConfigStatus waits for istiod to report the VirtualServices and
+	// Gateways generated for the Ingresses as distributed to all the proxies.
+	ReadinessStrategyConfigStatus ReadinessStrategy = "config-status"
+
+	// ReadinessStrategyBoth waits for the config status and then probes the gateway pods.
+	ReadinessStrategyBoth ReadinessStrategy = "both"
+)
+
+// UsesConfigStatus returns true if the readiness strategy waits for the config status.
+func (s ReadinessStrategy) UsesConfigStatus() bool {
+	return s == ReadinessStrategyConfigStatus || s == ReadinessStrategyBoth
+}
+
+// parseReadinessStrategy parses the value of the readiness-strategy key.
+func parseReadinessStrategy(value string) (ReadinessStrategy, error) {
+	switch strategy := ReadinessStrategy(value); strategy {
+	case "", ReadinessStrategyProbe, ReadinessStrategyConfigStatus, ReadinessStrategyBoth:
+		return strategy, nil
+	}
+	return "", fmt.Errorf("invalid %s %q, must be %q, %q or %q", ReadinessStrategyKey, value,
+		ReadinessStrategyProbe, ReadinessStrategyConfigStatus, ReadinessStrategyBoth)
+}
+
+// ProbeAllHosts is the value of Istio.ProbeHosts probing all the hosts of an Ingress.
+const ProbeAllHosts = -1
+
+// parseProbeHosts parses the value of the probe-hosts key.
+func parseProbeHosts(value string) (int, error) {
+	switch value {
+	case "", "single":
+		return 0, nil
+	case "all":
+		return ProbeAllHosts, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid %s %q, must be \"single\", \"all\" or a positive number", ProbeHostsKey, value)
+	}
+	return n, nil
+}
+
+// parseProbePods parses the value of the probe-pods key.
+func parseProbePods(value string) (int, error) {
+	if value == "" || value == "all" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid %s %q, must be \"all\" or a positive number", ProbePodsKey, value)
+	}
+	return n, nil
+}
+
+// validateProbeQuorum validates the percentage of the probe-quorum key. 0 is accepted as
+// the zero value of ProbeQuorum, which requires all the probed pods.
+func validateProbeQuorum(quorum int) error {
+	if quorum < 0 || quorum > 100 {
+		return fmt.Errorf("invalid %s %d, must be a percentage between 0 and 100, 0 meaning all the probed pods", ProbeQuorumKey, quorum)
+	}
+	return nil
+}
+
+// HTTPSRedirect configures the redirection of plain HTTP requests to HTTPS. The zero
+// value redirects with a 301 to port 443, like the `httpsRedirect` flag of Gateway servers.
+type HTTPSRedirect struct {
+	// Code is the HTTP status code of the redirect.
+	Code uint32
+
+	// Port is the port the redirect points to.
+	Port uint32
+
+	// ExemptPaths are the path prefixes served over plain HTTP instead of being redirected,
+	// e.g. for ACME challenges or health checks.
+	ExemptPaths []string
+}
+
+// IsDefault returns true if the redirect behaves like the `httpsRedirect` flag of Gateway servers.
+func (r HTTPSRedirect) IsDefault() bool {
+	return (r.Code == 0 || r.Code == http.StatusMovedPermanently) &&
+		(r.Port == 0 || r.Port == 443) &&
+		len(r.ExemptPaths) == 0
+}
+
+// Validate checks that the redirect code, port and exempt paths are valid.
+func (r HTTPSRedirect) Validate() error {
+	switch r.Code {
+	case 0, http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+	default:
+		return fmt.Errorf("invalid redirect code %d, must be one of 301, 302, 303, 307 or 308", r.Code)
+	}
+	if r.Port > 65535 {
+		return fmt.Errorf("invalid redirect port %d", r.Port)
+	}
+	for _, path := range r.ExemptPaths {
+		if !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("invalid exempt path %q, must start with '/'", path)
+		}
+	}
+	return nil
+}
+
+// ParseHTTPSRedirect overrides the settings of base with the HTTPS redirect keys found in
+// data. The keys are expected to be prefixed by the given prefix, e.g. for annotations.
+func ParseHTTPSRedirect(data map[string]string, prefix string, base HTTPSRedirect) (HTTPSRedirect, error) {
+	ret := base
+	var exemptPaths sets.Set[string]
+	if err := cm.Parse(data,
+		cm.AsUint32(prefix+HTTPSRedirectCodeKey, &ret.Code),
+		cm.AsUint32(prefix+HTTPSRedirectPortKey, &ret.Port),
+		cm.AsStringSet(prefix+HTTPSRedirectExemptPathsKey, &exemptPaths),
+	); err != nil {
+		return ret, err
+	}
+	if exemptPaths != nil {
+		// An empty value clears the exempt paths of base.
+		ret.ExemptPaths = nil
+		if exemptPaths.Delete("").Len() > 0 {
+			ret.ExemptPaths = sets.List(exemptPaths)
+		}
+	}
+	return ret, ret.Validate()
 }
 
 func (i Istio) Validate() error {
@@ -134,17 +439,35 @@ func (i Istio) Validate() error {
 		if err := gtw.Validate(); err != nil {
 			return fmt.Errorf("invalid local gateway %s: %w", gtw.QualifiedName(), err)
 		}
+		if len(gtw.Domains) > 0 {
+			return fmt.Errorf("invalid local gateway %s: domains are only supported for external gateways", gtw.QualifiedName())
+		}
+	}
+
+	if err := i.HTTPSRedirect.Validate(); err != nil {
+		return fmt.Errorf("invalid HTTPS redirect: %w", err)
+	}
+
+	domains := sets.New[string]()
+	for _, claim := range i.DomainClaims {
+		if err := claim.Validate(); err != nil {
+			return fmt.Errorf("invalid domain claim: %w", err)
+		}
+		if domains.Has(claim.Domain) {
+			return fmt.Errorf("invalid domain claim: domain %q is claimed more than once", claim.Domain)
+		}
+		domains.Insert(claim.Domain)
 	}
 
 	return nil
 }
 
-// DefaultExternalGateways returns the external gateway without any label selector
+// DefaultExternalGateways returns the external gateway without any selector or domains
 func (i Istio) DefaultExternalGateways() []Gateway {
 	return defaultGateways(i.IngressGateways)
 }
 
-// DefaultLocalGateways returns the local gateway without any label selector
+// DefaultLocalGateways returns the local gateway without any label or namespace selector
 func (i Istio) DefaultLocalGateways() []Gateway {
 	return defaultGateways(i.LocalGateways)
 }
@@ -155,7 +478,7 @@ func defaultGateways(gtws []Gateway) []Gateway {
 	for _, gtw := range gtws {
 		gateway := gtw
 
-		if gtw.LabelSelector == nil {
+		if gtw.LabelSelector == nil && gtw.NamespaceSelector == nil && len(gtw.Domains) == 0 {
 			ret = append(ret, gateway)
 		}
 	}
@@ -163,6 +486,29 @@ func defaultGateways(gtws []Gateway) []Gateway {
 	return ret
 }
 
+// UsesNamespaceSelectors returns true if any gateway selects Ingresses by the labels
+// of their namespace.
+func (i Istio) UsesNamespaceSelectors() bool {
+	for _, gtws := range [][]Gateway{i.IngressGateways, i.LocalGateways} {
+		for _, gtw := range gtws {
+			if gtw.NamespaceSelector != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// UsesDomains returns true if any external gateway serves hosts by their domain.
+func (i Istio) UsesDomains() bool {
+	for _, gtw := range i.IngressGateways {
+		if len(gtw.Domains) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // GatewaysEnabled returns true if any ingress or local gateways are configured.
 // When both gateway lists are empty (e.g. via external-gateways: "[]" and
 // local-gateways: "[]"), this returns false, indicating mesh-only mode.
@@ -182,7 +528,7 @@ func NewIstioFromConfigMap(configMap *corev1.ConfigMap) (*Istio, error) {
 	case newFormatDefined && oldFormatDefined:
 		return nil, fmt.Errorf(
 			"invalid configmap: %q or %q can not be defined simultaneously with %q or %q",
-			localGatewaysKey, externalGatewaysKey, gatewayKeyPrefix, localGatewayKeyPrefix,
+			LocalGatewaysKey, ExternalGatewaysKey, gatewayKeyPrefix, localGatewayKeyPrefix,
 		)
 	case newFormatDefined:
 		ret, err = parseNewFormat(configMap)
@@ -195,6 +541,36 @@ func NewIstioFromConfigMap(configMap *corev1.ConfigMap) (*Istio, error) {
 		defaultValues(ret)
 	}
 
+	if err := cm.Parse(configMap.Data, cm.AsBool(enableHTTP3Key, &ret.EnableHTTP3)); err != nil {
+		return nil, fmt.Errorf("failed to parse configmap: %w", err)
+	}
+	if ret.HTTPSRedirect, err = ParseHTTPSRedirect(configMap.Data, "", HTTPSRedirect{}); err != nil {
+		return nil, fmt.Errorf("failed to parse configmap: %w", err)
+	}
+	if ret.ProbeHosts, err = parseProbeHosts(strings.TrimSpace(configMap.Data[ProbeHostsKey])); err != nil {
+		return nil, fmt.Errorf("failed to parse configmap: %w", err)
+	}
+	if ret.ReadinessStrategy, err = parseReadinessStrategy(strings.TrimSpace(configMap.Data[ReadinessStrategyKey])); err != nil {
+		return nil, fmt.Errorf("failed to parse configmap: %w", err)
+	}
+	if ret.ProbePods, err = parseProbePods(strings.TrimSpace(configMap.Data[ProbePodsKey])); err != nil {
+		return nil, fmt.Errorf("failed to parse configmap: %w", err)
+	}
+	if err := cm.Parse(configMap.Data, cm.AsInt(ProbeQuorumKey, &ret.ProbeQuorum)); err != nil {
+		return nil, fmt.Errorf("failed to parse configmap: %w", err)
+	}
+	if err := validateProbeQuorum(ret.ProbeQuorum); err != nil {
+		return nil, fmt.Errorf("failed to parse configmap: %w", err)
+	}
+	if ret.ProbeIPFamilies, err = parseProbeIPFamilies(strings.TrimSpace(configMap.Data[ProbeIPFamiliesKey])); err != nil {
+		return nil, fmt.Errorf("failed to parse configmap: %w", err)
+	}
+	if data, ok := configMap.Data[DomainClaimsKey]; ok {
+		if err := yaml.Unmarshal([]byte(data), &ret.DomainClaims); err != nil {
+			return nil, fmt.Errorf("failed to parse configmap: invalid %s: %w", DomainClaimsKey, err)
+		}
+	}
+
 	err = ret.Validate()
 	if err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
@@ -203,16 +579,32 @@ func NewIstioFromConfigMap(configMap *corev1.ConfigMap) (*Istio, error) {
 	return ret, nil
 }
 
+// UsesLegacyFormat returns true if the ConfigMap configures gateways with the deprecated
+// gateway.* and local-gateway.* keys instead of external-gateways and local-gateways.
+func UsesLegacyFormat(configMap *corev1.ConfigMap) bool {
+	return isOldFormatDefined(configMap)
+}
+
+// IsLegacyGatewayKey returns true for the deprecated gateway.* and local-gateway.* keys.
+func IsLegacyGatewayKey(key string) bool {
+	return strings.HasPrefix(key, gatewayKeyPrefix) || strings.HasPrefix(key, localGatewayKeyPrefix)
+}
+
+// IsLegacyLocalGatewayKey returns true for the deprecated local-gateway.* keys.
+func IsLegacyLocalGatewayKey(key string) bool {
+	return strings.HasPrefix(key, localGatewayKeyPrefix)
+}
+
 func isNewFormatDefined(configMap *corev1.ConfigMap) bool {
-	_, hasGateway := configMap.Data[externalGatewaysKey]
-	_, hasLocalGateway := configMap.Data[localGatewaysKey]
+	_, hasGateway := configMap.Data[ExternalGatewaysKey]
+	_, hasLocalGateway := configMap.Data[LocalGatewaysKey]
 
 	return hasGateway || hasLocalGateway
 }
 
 func isOldFormatDefined(configMap *corev1.ConfigMap) bool {
 	for key := range configMap.Data {
-		if strings.HasPrefix(key, gatewayKeyPrefix) || strings.HasPrefix(key, localGatewayKeyPrefix) {
+		if IsLegacyGatewayKey(key) {
 			return true
 		}
 	}
@@ -223,14 +615,14 @@ func isOldFormatDefined(configMap *corev1.ConfigMap) bool {
 func parseNewFormat(configMap *corev1.ConfigMap) (*Istio, error) {
 	ret := &Istio{}
 
-	gatewaysStr := configMap.Data[externalGatewaysKey]
+	gatewaysStr := configMap.Data[ExternalGatewaysKey]
 
 	// An empty/whitespace-only string (or absent key) means use defaults.
 	// An explicit non-empty value like "[]" means no gateways of that type.
 	if strings.TrimSpace(gatewaysStr) != "" {
 		gateways, err := parseNewFormatGateways(gatewaysStr)
 		if err != nil {
-			return ret, fmt.Errorf("failed to parse %q gateways: %w", externalGatewaysKey, err)
+			return ret, fmt.Errorf("failed to parse %q gateways: %w", ExternalGatewaysKey, err)
 		}
 
 		ret.IngressGateways = gateways
@@ -238,12 +630,12 @@ func parseNewFormat(configMap *corev1.ConfigMap) (*Istio, error) {
 		ret.IngressGateways = defaultIngressGateways()
 	}
 
-	localGatewaysStr := configMap.Data[localGatewaysKey]
+	localGatewaysStr := configMap.Data[LocalGatewaysKey]
 
 	if strings.TrimSpace(localGatewaysStr) != "" {
 		localGateways, err := parseNewFormatGateways(localGatewaysStr)
 		if err != nil {
-			return ret, fmt.Errorf("failed to parse %q gateways: %w", localGatewaysKey, err)
+			return ret, fmt.Errorf("failed to parse %q gateways: %w", LocalGatewaysKey, err)
 		}
 
 		ret.LocalGateways = localGateways
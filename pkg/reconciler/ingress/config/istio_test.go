@@ -567,6 +567,158 @@ func TestGatewayConfiguration(t *testing.T) {
 				},
 			},
 		},
+	}, {
+		name: "external gateway with namespace selector",
+		config: &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: system.Namespace(),
+				Name:      IstioConfigName,
+			},
+			Data: map[string]string{
+				"external-gateways": replaceTabs(`
+				- namespace: "namespace"
+				  name: "gateway"
+				  service: "istio-gateway.istio-system.svc.cluster.local"
+				- namespace: "namespace"
+				  name: "internal-gateway"
+				  service: "istio-internal-gateway.istio-system.svc.cluster.local"
+				  namespaceSelector:
+					matchLabels:
+					  tier: "internal"`),
+			},
+		},
+		wantIstio: &Istio{
+			IngressGateways: []Gateway{{
+				Namespace:  "namespace",
+				Name:       "gateway",
+				ServiceURL: "istio-gateway.istio-system.svc.cluster.local",
+			}, {
+				Namespace:  "namespace",
+				Name:       "internal-gateway",
+				ServiceURL: "istio-internal-gateway.istio-system.svc.cluster.local",
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"tier": "internal"},
+				},
+			}},
+			LocalGateways: defaultLocalGateways(),
+		},
+	}, {
+		name: "external gateway with domains",
+		config: &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: system.Namespace(),
+				Name:      IstioConfigName,
+			},
+			Data: map[string]string{
+				"external-gateways": replaceTabs(`
+				- namespace: "namespace"
+				  name: "gateway"
+				  service: "istio-gateway.istio-system.svc.cluster.local"
+				- namespace: "namespace"
+				  name: "internal-gateway"
+				  service: "istio-internal-gateway.istio-system.svc.cluster.local"
+				  domains:
+				  - "*.internal.example.com"`),
+			},
+		},
+		wantIstio: &Istio{
+			IngressGateways: []Gateway{{
+				Namespace:  "namespace",
+				Name:       "gateway",
+				ServiceURL: "istio-gateway.istio-system.svc.cluster.local",
+			}, {
+				Namespace:  "namespace",
+				Name:       "internal-gateway",
+				ServiceURL: "istio-internal-gateway.istio-system.svc.cluster.local",
+				Domains:    []string{"*.internal.example.com"},
+			}},
+			LocalGateways: defaultLocalGateways(),
+		},
+	}, {
+		name: "new format - invalid domain",
+		config: &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: system.Namespace(),
+				Name:      IstioConfigName,
+			},
+			Data: map[string]string{
+				"external-gateways": replaceTabs(`
+				- namespace: "default"
+				  name: "default"
+				  service: "default.default.svc.cluster.local"
+				- namespace: "namespace"
+				  name: "gateway"
+				  service: "istio-gateway.istio-system.svc.cluster.local"
+				  domains:
+				  - "*.Example_Com"`),
+			},
+		},
+		wantErr: true,
+	}, {
+		name: "new format - domains with a label selector",
+		config: &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: system.Namespace(),
+				Name:      IstioConfigName,
+			},
+			Data: map[string]string{
+				"external-gateways": replaceTabs(`
+				- namespace: "default"
+				  name: "default"
+				  service: "default.default.svc.cluster.local"
+				- namespace: "namespace"
+				  name: "gateway"
+				  service: "istio-gateway.istio-system.svc.cluster.local"
+				  domains:
+				  - "example.com"
+				  labelSelector:
+					matchLabels:
+					  key: "value"`),
+			},
+		},
+		wantErr: true,
+	}, {
+		name: "new format - local gateway with domains",
+		config: &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: system.Namespace(),
+				Name:      IstioConfigName,
+			},
+			Data: map[string]string{
+				"local-gateways": replaceTabs(`
+				- namespace: "default"
+				  name: "default"
+				  service: "default.default.svc.cluster.local"
+				- namespace: "namespace"
+				  name: "gateway"
+				  service: "istio-gateway.istio-system.svc.cluster.local"
+				  domains:
+				  - "example.com"`),
+			},
+		},
+		wantErr: true,
+	}, {
+		name: "new format - invalid namespace selector",
+		config: &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: system.Namespace(),
+				Name:      IstioConfigName,
+			},
+			Data: map[string]string{
+				"external-gateways": replaceTabs(`
+				- namespace: "default"
+				  name: "default"
+				  service: "default.default.svc.cluster.local"
+				- namespace: "namespace"
+				  name: "gateway"
+				  service: "istio-gateway.istio-system.svc.cluster.local"
+				  namespaceSelector:
+					matchExpressions:
+					- key: "key"
+					  operator: "In"`),
+			},
+		},
+		wantErr: true,
 	}, {
 		name: "new format - invalid label selector",
 		config: &corev1.ConfigMap{
@@ -620,6 +772,317 @@ func TestGatewayConfiguration(t *testing.T) {
 				"external-gateways": "[]",
 			},
 		},
+	}, {
+		name: "http3 enabled",
+		wantIstio: &Istio{
+			IngressGateways: defaultIngressGateways(),
+			LocalGateways:   defaultLocalGateways(),
+			EnableHTTP3:     true,
+		},
+		config: &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: system.Namespace(),
+				Name:      IstioConfigName,
+			},
+			Data: map[string]string{
+				"enable-http3": "true",
+			},
+		},
+	}, {
+		name:    "http3 with invalid value",
+		wantErr: true,
+		config: &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: system.Namespace(),
+				Name:      IstioConfigName,
+			},
+			Data: map[string]string{
+				"enable-http3": "sure",
+			},
+		},
+	}, {
+		name: "https redirect",
+		wantIstio: &Istio{
+			IngressGateways: defaultIngressGateways(),
+			LocalGateways:   defaultLocalGateways(),
+			HTTPSRedirect: HTTPSRedirect{
+				Code:        308,
+				Port:        8443,
+				ExemptPaths: []string{"/.well-known/acme-challenge/", "/healthz"},
+			},
+		},
+		config: &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: system.Namespace(),
+				Name:      IstioConfigName,
+			},
+			Data: map[string]string{
+				"https-redirect-code":         "308",
+				"https-redirect-port":         "8443",
+				"https-redirect-exempt-paths": "/healthz, /.well-known/acme-challenge/",
+			},
+		},
+	}, {
+		name:    "https redirect with invalid code",
+		wantErr: true,
+		config: &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: system.Namespace(),
+				Name:      IstioConfigName,
+			},
+			Data: map[string]string{
+				"https-redirect-code": "200",
+			},
+		},
+	}, {
+		name: "probe all hosts",
+		wantIstio: &Istio{
+			IngressGateways: defaultIngressGateways(),
+			LocalGateways:   defaultLocalGateways(),
+			ProbeHosts:      ProbeAllHosts,
+		},
+		config: &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: system.Namespace(),
+				Name:      IstioConfigName,
+			},
+			Data: map[string]string{
+				"probe-hosts": "all",
+			},
+		},
+	}, {
+		name: "probe a sample of hosts",
+		wantIstio: &Istio{
+			IngressGateways: defaultIngressGateways(),
+			LocalGateways:   defaultLocalGateways(),
+			ProbeHosts:      3,
+		},
+		config: &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: system.Namespace(),
+				Name:      IstioConfigName,
+			},
+			Data: map[string]string{
+				"probe-hosts": "3",
+			},
+		},
+	}, {
+		name: "probe a single host",
+		wantIstio: &Istio{
+			IngressGateways: defaultIngressGateways(),
+			LocalGateways:   defaultLocalGateways(),
+		},
+		config: &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: system.Namespace(),
+				Name:      IstioConfigName,
+			},
+			Data: map[string]string{
+				"probe-hosts": "single",
+			},
+		},
+	}, {
+		name:    "probe hosts with invalid value",
+		wantErr: true,
+		config: &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: system.Namespace(),
+				Name:      IstioConfigName,
+			},
+			Data: map[string]string{
+				"probe-hosts": "0",
+			},
+		},
+	}, {
+		name: "readiness from the config status",
+		wantIstio: &Istio{
+			IngressGateways:   defaultIngressGateways(),
+			LocalGateways:     defaultLocalGateways(),
+			ReadinessStrategy: ReadinessStrategyConfigStatus,
+		},
+		config: &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: system.Namespace(),
+				Name:      IstioConfigName,
+			},
+			Data: map[string]string{
+				"readiness-strategy": "config-status",
+			},
+		},
+	}, {
+		name:    "readiness strategy with invalid value",
+		wantErr: true,
+		config: &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: system.Namespace(),
+				Name:      IstioConfigName,
+			},
+			Data: map[string]string{
+				"readiness-strategy": "status",
+			},
+		},
+	}, {
+		name: "probe a sample of the gateway pods with a quorum",
+		wantIstio: &Istio{
+			IngressGateways: defaultIngressGateways(),
+			LocalGateways:   defaultLocalGateways(),
+			ProbePods:       10,
+			ProbeQuorum:     50,
+		},
+		config: &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: system.Namespace(),
+				Name:      IstioConfigName,
+			},
+			Data: map[string]string{
+				"probe-pods":   " 10 ",
+				"probe-quorum": "50",
+			},
+		},
+	}, {
+		name: "probe all the gateway pods",
+		wantIstio: &Istio{
+			IngressGateways: defaultIngressGateways(),
+			LocalGateways:   defaultLocalGateways(),
+			ProbeQuorum:     100,
+		},
+		config: &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: system.Namespace(),
+				Name:      IstioConfigName,
+			},
+			Data: map[string]string{
+				"probe-pods":   "all",
+				"probe-quorum": "100",
+			},
+		},
+	}, {
+		name: "probe quorum of 0 requires all the probed pods",
+		wantIstio: &Istio{
+			IngressGateways: defaultIngressGateways(),
+			LocalGateways:   defaultLocalGateways(),
+		},
+		config: &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: system.Namespace(),
+				Name:      IstioConfigName,
+			},
+			Data: map[string]string{
+				"probe-quorum": "0",
+			},
+		},
+	}, {
+		name:    "probe pods with invalid value",
+		wantErr: true,
+		config: &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: system.Namespace(),
+				Name:      IstioConfigName,
+			},
+			Data: map[string]string{
+				"probe-pods": "none",
+			},
+		},
+	}, {
+		name:    "probe quorum out of range",
+		wantErr: true,
+		config: &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: system.Namespace(),
+				Name:      IstioConfigName,
+			},
+			Data: map[string]string{
+				"probe-quorum": "150",
+			},
+		},
+	}, {
+		name: "probe all the IP families",
+		wantIstio: &Istio{
+			IngressGateways: defaultIngressGateways(),
+			LocalGateways:   defaultLocalGateways(),
+			ProbeIPFamilies: ProbeIPFamiliesAll,
+		},
+		config: &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: system.Namespace(),
+				Name:      IstioConfigName,
+			},
+			Data: map[string]string{
+				"probe-ip-families": "all",
+			},
+		},
+	}, {
+		name:    "probe IP families with invalid value",
+		wantErr: true,
+		config: &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: system.Namespace(),
+				Name:      IstioConfigName,
+			},
+			Data: map[string]string{
+				"probe-ip-families": "IPv6",
+			},
+		},
+	}, {
+		name: "domain claims",
+		wantIstio: &Istio{
+			IngressGateways: defaultIngressGateways(),
+			LocalGateways:   defaultLocalGateways(),
+			DomainClaims: []DomainClaim{{
+				Domain:     "example.com",
+				Namespaces: []string{"frontend"},
+			}, {
+				Domain: "*.reserved.example.com",
+			}},
+		},
+		config: &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: system.Namespace(),
+				Name:      IstioConfigName,
+			},
+			Data: map[string]string{
+				"domain-claims": replaceTabs(`
+				- domain: "example.com"
+				  namespaces: ["frontend"]
+				- domain: "*.reserved.example.com"`),
+			},
+		},
+	}, {
+		name:    "domain claims with invalid domain",
+		wantErr: true,
+		config: &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: system.Namespace(),
+				Name:      IstioConfigName,
+			},
+			Data: map[string]string{
+				"domain-claims": `[{"domain": "Example_com"}]`,
+			},
+		},
+	}, {
+		name:    "domain claimed twice",
+		wantErr: true,
+		config: &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: system.Namespace(),
+				Name:      IstioConfigName,
+			},
+			Data: map[string]string{
+				"domain-claims": `[{"domain": "example.com"}, {"domain": "example.com"}]`,
+			},
+		},
+	}, {
+		name:    "domain claims not a list",
+		wantErr: true,
+		config: &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: system.Namespace(),
+				Name:      IstioConfigName,
+			},
+			Data: map[string]string{
+				"domain-claims": "example.com: frontend",
+			},
+		},
 	}, {
 		name: "new format - only local gateways explicitly empty",
 		wantIstio: &Istio{
@@ -651,6 +1114,107 @@ func TestGatewayConfiguration(t *testing.T) {
 	}
 }
 
+func TestParseHTTPSRedirect(t *testing.T) {
+	base := HTTPSRedirect{
+		Code:        307,
+		ExemptPaths: []string{"/healthz"},
+	}
+	tests := []struct {
+		name    string
+		data    map[string]string
+		want    HTTPSRedirect
+		wantErr bool
+	}{{
+		name: "no keys",
+		want: base,
+	}, {
+		name: "overrides",
+		data: map[string]string{
+			"prefix/https-redirect-code": "308",
+			"prefix/https-redirect-port": "8443",
+			// Keys without the prefix are ignored.
+			"https-redirect-exempt-paths": "/ignored",
+		},
+		want: HTTPSRedirect{
+			Code:        308,
+			Port:        8443,
+			ExemptPaths: []string{"/healthz"},
+		},
+	}, {
+		name: "empty exempt paths clear the base",
+		data: map[string]string{
+			"prefix/https-redirect-exempt-paths": "",
+		},
+		want: HTTPSRedirect{
+			Code: 307,
+		},
+	}, {
+		name: "invalid code",
+		data: map[string]string{
+			"prefix/https-redirect-code": "404",
+		},
+		wantErr: true,
+	}, {
+		name: "unparsable port",
+		data: map[string]string{
+			"prefix/https-redirect-port": "https",
+		},
+		wantErr: true,
+	}, {
+		name: "invalid port",
+		data: map[string]string{
+			"prefix/https-redirect-port": "65536",
+		},
+		wantErr: true,
+	}, {
+		name: "relative exempt path",
+		data: map[string]string{
+			"prefix/https-redirect-exempt-paths": "healthz",
+		},
+		wantErr: true,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseHTTPSRedirect(tt.data, "prefix/", base)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseHTTPSRedirect() error = %v, WantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Error("ParseHTTPSRedirect() (-want, +got):", diff)
+			}
+		})
+	}
+}
+
+func TestHTTPSRedirectIsDefault(t *testing.T) {
+	tests := []struct {
+		redirect HTTPSRedirect
+		want     bool
+	}{{
+		redirect: HTTPSRedirect{},
+		want:     true,
+	}, {
+		redirect: HTTPSRedirect{Code: 301, Port: 443},
+		want:     true,
+	}, {
+		redirect: HTTPSRedirect{Code: 308},
+	}, {
+		redirect: HTTPSRedirect{Port: 8443},
+	}, {
+		redirect: HTTPSRedirect{ExemptPaths: []string{"/healthz"}},
+	}}
+
+	for _, tt := range tests {
+		if got := tt.redirect.IsDefault(); got != tt.want {
+			t.Errorf("%+v.IsDefault() = %v, want %v", tt.redirect, got, tt.want)
+		}
+	}
+}
+
 func TestGatewaysEnabled(t *testing.T) {
 	tests := []struct {
 		name string
@@ -695,6 +1259,87 @@ func TestGatewaysEnabled(t *testing.T) {
 	}
 }
 
+func TestGatewayMatchDomain(t *testing.T) {
+	gateway := Gateway{Domains: []string{"*.example.com", "internal.example.com"}}
+	tests := []struct {
+		host string
+		want int
+	}{{
+		host: "foo.example.com",
+		want: len("*.example.com"),
+	}, {
+		host: "internal.example.com",
+		want: len("internal.example.com"),
+	}, {
+		host: "foo.internal.example.com",
+		want: len("internal.example.com"),
+	}, {
+		host: "example.com",
+	}, {
+		host: "fooexample.com",
+	}, {
+		host: "foo.example.org",
+	}}
+
+	for _, tt := range tests {
+		if got := gateway.MatchDomain(tt.host); got != tt.want {
+			t.Errorf("MatchDomain(%q) = %d, want %d", tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestMayClaimHost(t *testing.T) {
+	istio := Istio{DomainClaims: []DomainClaim{{
+		Domain:     "example.com",
+		Namespaces: []string{"frontend"},
+	}, {
+		Domain:     "*.team.example.com",
+		Namespaces: []string{"team"},
+	}, {
+		Domain: "reserved.example.com",
+	}}}
+	tests := []struct {
+		namespace string
+		host      string
+		want      bool
+	}{{
+		namespace: "frontend",
+		host:      "example.com",
+		want:      true,
+	}, {
+		namespace: "frontend",
+		host:      "www.example.com",
+		want:      true,
+	}, {
+		namespace: "team",
+		host:      "www.example.com",
+	}, {
+		namespace: "team",
+		host:      "app.team.example.com",
+		want:      true,
+	}, {
+		namespace: "frontend",
+		host:      "app.team.example.com",
+	}, {
+		namespace: "frontend",
+		host:      "team.example.com",
+		want:      true,
+	}, {
+		namespace: "frontend",
+		host:      "reserved.example.com",
+	}, {
+		namespace: "team",
+		host:      "example.org",
+		want:      true,
+	}}
+
+	for _, tt := range tests {
+		if got := istio.MayClaimHost(tt.namespace, tt.host); got != tt.want {
+			t.Errorf("MayClaimHost(%q, %q) = %v, want %v", tt.namespace, tt.host, got, tt.want)
+		}
+	}
+}
+
 func replaceTabs(s string) string {
 	return strings.ReplaceAll(s, "\t", "    ")
 }
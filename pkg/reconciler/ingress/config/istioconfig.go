@@ -0,0 +1,151 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/net-istio/pkg/apis/istio/v1alpha1"
+)
+
+// NewIstioFromIstioConfig creates an Istio config from the spec of an IstioConfig.
+func NewIstioFromIstioConfig(spec *v1alpha1.IstioConfigSpec) (*Istio, error) {
+	ret := &Istio{
+		IngressGateways: gatewaysFromSpec(spec.ExternalGateways),
+		LocalGateways:   gatewaysFromSpec(spec.LocalGateways),
+		EnableHTTP3:     spec.EnableHTTP3,
+	}
+	if spec.ExternalGateways == nil {
+		ret.IngressGateways = defaultIngressGateways()
+	}
+	if spec.LocalGateways == nil {
+		ret.LocalGateways = defaultLocalGateways()
+	}
+	if redirect := spec.HTTPSRedirect; redirect != nil {
+		ret.HTTPSRedirect = HTTPSRedirect{
+			Code:        redirect.Code,
+			Port:        redirect.Port,
+			ExemptPaths: redirect.ExemptPaths,
+		}
+	}
+	probeHosts, err := parseProbeHosts(spec.ProbeHosts)
+	if err != nil {
+		return nil, err
+	}
+	ret.ProbeHosts = probeHosts
+	if ret.ReadinessStrategy, err = parseReadinessStrategy(spec.ReadinessStrategy); err != nil {
+		return nil, err
+	}
+	if ret.ProbePods, err = parseProbePods(spec.ProbePods); err != nil {
+		return nil, err
+	}
+	if err := validateProbeQuorum(spec.ProbeQuorum); err != nil {
+		return nil, err
+	}
+	ret.ProbeQuorum = spec.ProbeQuorum
+	if ret.ProbeIPFamilies, err = parseProbeIPFamilies(spec.ProbeIPFamilies); err != nil {
+		return nil, err
+	}
+	for _, claim := range spec.DomainClaims {
+		ret.DomainClaims = append(ret.DomainClaims, DomainClaim{
+			Domain:     claim.Domain,
+			Namespaces: claim.Namespaces,
+		})
+	}
+
+	if err := ret.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return ret, nil
+}
+
+// NewIstioConfigSpecFromConfigMap converts the config-istio ConfigMap, in either of its
+// formats, to the spec of an IstioConfig.
+func NewIstioConfigSpecFromConfigMap(configMap *corev1.ConfigMap) (*v1alpha1.IstioConfigSpec, error) {
+	istio, err := NewIstioFromConfigMap(configMap)
+	if err != nil {
+		return nil, err
+	}
+	return istio.IstioConfigSpec(), nil
+}
+
+// IstioConfigSpec returns the spec of an IstioConfig equivalent to this configuration.
+func (i *Istio) IstioConfigSpec() *v1alpha1.IstioConfigSpec {
+	spec := &v1alpha1.IstioConfigSpec{
+		ExternalGateways: gatewaysToSpec(i.IngressGateways),
+		LocalGateways:    gatewaysToSpec(i.LocalGateways),
+		EnableHTTP3:      i.EnableHTTP3,
+	}
+	if redirect := i.HTTPSRedirect; redirect.Code != 0 || redirect.Port != 0 || len(redirect.ExemptPaths) > 0 {
+		spec.HTTPSRedirect = &v1alpha1.HTTPSRedirect{
+			Code:        redirect.Code,
+			Port:        redirect.Port,
+			ExemptPaths: redirect.ExemptPaths,
+		}
+	}
+	switch {
+	case i.ProbeHosts == ProbeAllHosts:
+		spec.ProbeHosts = "all"
+	case i.ProbeHosts > 0:
+		spec.ProbeHosts = strconv.Itoa(i.ProbeHosts)
+	}
+	spec.ReadinessStrategy = string(i.ReadinessStrategy)
+	if i.ProbePods > 0 {
+		spec.ProbePods = strconv.Itoa(i.ProbePods)
+	}
+	spec.ProbeQuorum = i.ProbeQuorum
+	spec.ProbeIPFamilies = string(i.ProbeIPFamilies)
+	for _, claim := range i.DomainClaims {
+		spec.DomainClaims = append(spec.DomainClaims, v1alpha1.DomainClaim{
+			Domain:     claim.Domain,
+			Namespaces: claim.Namespaces,
+		})
+	}
+	return spec.DeepCopy()
+}
+
+func gatewaysFromSpec(gateways []v1alpha1.Gateway) []Gateway {
+	ret := make([]Gateway, 0, len(gateways))
+	for _, gtw := range gateways {
+		ret = append(ret, Gateway{
+			Namespace:         gtw.Namespace,
+			Name:              gtw.Name,
+			ServiceURL:        gtw.Service,
+			LabelSelector:     gtw.LabelSelector.DeepCopy(),
+			NamespaceSelector: gtw.NamespaceSelector.DeepCopy(),
+			Domains:           gtw.Domains,
+		})
+	}
+	return ret
+}
+
+func gatewaysToSpec(gateways []Gateway) []v1alpha1.Gateway {
+	ret := make([]v1alpha1.Gateway, 0, len(gateways))
+	for _, gtw := range gateways {
+		ret = append(ret, v1alpha1.Gateway{
+			Namespace:         gtw.Namespace,
+			Name:              gtw.Name,
+			Service:           gtw.ServiceURL,
+			LabelSelector:     gtw.LabelSelector,
+			NamespaceSelector: gtw.NamespaceSelector,
+			Domains:           gtw.Domains,
+		})
+	}
+	return ret
+}
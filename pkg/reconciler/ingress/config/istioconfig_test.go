@@ -0,0 +1,238 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/net-istio/pkg/apis/istio/v1alpha1"
+	"knative.dev/pkg/system"
+)
+
+func TestNewIstioFromIstioConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    v1alpha1.IstioConfigSpec
+		want    *Istio
+		wantErr bool
+	}{{
+		name: "defaults",
+		want: &Istio{
+			IngressGateways: defaultIngressGateways(),
+			LocalGateways:   defaultLocalGateways(),
+		},
+	}, {
+		name: "mesh-only",
+		spec: v1alpha1.IstioConfigSpec{
+			ExternalGateways: []v1alpha1.Gateway{},
+			LocalGateways:    []v1alpha1.Gateway{},
+		},
+		want: &Istio{
+			IngressGateways: []Gateway{},
+			LocalGateways:   []Gateway{},
+		},
+	}, {
+		name: "gateways and options",
+		spec: v1alpha1.IstioConfigSpec{
+			ExternalGateways: []v1alpha1.Gateway{{
+				Namespace: "namespace",
+				Name:      "gateway",
+				Service:   "istio-gateway.istio-system.svc.cluster.local",
+			}, {
+				Namespace: "namespace",
+				Name:      "internal-gateway",
+				Service:   "istio-internal-gateway.istio-system.svc.cluster.local",
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"tier": "internal"},
+				},
+			}},
+			EnableHTTP3: true,
+			HTTPSRedirect: &v1alpha1.HTTPSRedirect{
+				Code:        308,
+				ExemptPaths: []string{"/healthz"},
+			},
+			ProbeHosts:        "all",
+			ReadinessStrategy: "both",
+			ProbePods:         "3",
+			ProbeQuorum:       60,
+			ProbeIPFamilies:   "all",
+			DomainClaims: []v1alpha1.DomainClaim{{
+				Domain:     "example.com",
+				Namespaces: []string{"frontend"},
+			}},
+		},
+		want: &Istio{
+			IngressGateways: []Gateway{{
+				Namespace:  "namespace",
+				Name:       "gateway",
+				ServiceURL: "istio-gateway.istio-system.svc.cluster.local",
+			}, {
+				Namespace:  "namespace",
+				Name:       "internal-gateway",
+				ServiceURL: "istio-internal-gateway.istio-system.svc.cluster.local",
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"tier": "internal"},
+				},
+			}},
+			LocalGateways: defaultLocalGateways(),
+			EnableHTTP3:   true,
+			HTTPSRedirect: HTTPSRedirect{
+				Code:        308,
+				ExemptPaths: []string{"/healthz"},
+			},
+			ProbeHosts:        ProbeAllHosts,
+			ReadinessStrategy: ReadinessStrategyBoth,
+			ProbePods:         3,
+			ProbeQuorum:       60,
+			ProbeIPFamilies:   ProbeIPFamiliesAll,
+			DomainClaims: []DomainClaim{{
+				Domain:     "example.com",
+				Namespaces: []string{"frontend"},
+			}},
+		},
+	}, {
+		name: "invalid gateway",
+		spec: v1alpha1.IstioConfigSpec{
+			ExternalGateways: []v1alpha1.Gateway{{
+				Namespace: "namespace",
+				Name:      "gateway",
+			}},
+		},
+		wantErr: true,
+	}, {
+		name: "invalid redirect",
+		spec: v1alpha1.IstioConfigSpec{
+			HTTPSRedirect: &v1alpha1.HTTPSRedirect{Code: 200},
+		},
+		wantErr: true,
+	}, {
+		name: "invalid probe hosts",
+		spec: v1alpha1.IstioConfigSpec{
+			ProbeHosts: "some",
+		},
+		wantErr: true,
+	}, {
+		name: "invalid readiness strategy",
+		spec: v1alpha1.IstioConfigSpec{
+			ReadinessStrategy: "never",
+		},
+		wantErr: true,
+	}, {
+		name: "invalid probe pods",
+		spec: v1alpha1.IstioConfigSpec{
+			ProbePods: "0",
+		},
+		wantErr: true,
+	}, {
+		name: "invalid probe quorum",
+		spec: v1alpha1.IstioConfigSpec{
+			ProbeQuorum: 101,
+		},
+		wantErr: true,
+	}, {
+		name: "invalid probe IP families",
+		spec: v1alpha1.IstioConfigSpec{
+			ProbeIPFamilies: "both",
+		},
+		wantErr: true,
+	}, {
+		name: "invalid domain claim",
+		spec: v1alpha1.IstioConfigSpec{
+			DomainClaims: []v1alpha1.DomainClaim{{Domain: ""}},
+		},
+		wantErr: true,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewIstioFromIstioConfig(&tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewIstioFromIstioConfig() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Error("Unexpected istio config (-want, +got):", diff)
+			}
+		})
+	}
+}
+
+func TestIstioConfigSpecFromConfigMap(t *testing.T) {
+	tests := []struct {
+		name string
+		data map[string]string
+	}{{
+		name: "defaults",
+	}, {
+		name: "legacy format",
+		data: map[string]string{
+			"gateway.knative-serving.knative-ingress-gateway":     "istio-ingressgateway.istio-system.svc.cluster.local",
+			"local-gateway.knative-serving.knative-local-gateway": "knative-local-gateway.istio-system.svc.cluster.local",
+			"enable-http3": "true",
+		},
+	}, {
+		name: "new format",
+		data: map[string]string{
+			"external-gateways": replaceTabs(`
+			- namespace: "namespace"
+			  name: "gateway"
+			  service: "istio-gateway.istio-system.svc.cluster.local"
+			- namespace: "namespace"
+			  name: "internal-gateway"
+			  service: "istio-internal-gateway.istio-system.svc.cluster.local"
+			  domains:
+			  - "*.internal.example.com"`),
+			"local-gateways":      "[]",
+			"https-redirect-code": "308",
+			"probe-hosts":         "5",
+			"readiness-strategy":  "config-status",
+			"probe-pods":          "20",
+			"probe-quorum":        "75",
+			"probe-ip-families":   "primary",
+			"domain-claims":       `[{"domain": "*.example.com", "namespaces": ["a", "b"]}]`,
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: system.Namespace(),
+					Name:      IstioConfigName,
+				},
+				Data: tt.data,
+			}
+			want, err := NewIstioFromConfigMap(configMap)
+			if err != nil {
+				t.Fatal("NewIstioFromConfigMap() =", err)
+			}
+			spec, err := NewIstioConfigSpecFromConfigMap(configMap)
+			if err != nil {
+				t.Fatal("NewIstioConfigSpecFromConfigMap() =", err)
+			}
+			got, err := NewIstioFromIstioConfig(spec)
+			if err != nil {
+				t.Fatal("NewIstioFromIstioConfig() =", err)
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Error("Converted configuration differs (-want, +got):", diff)
+			}
+		})
+	}
+}
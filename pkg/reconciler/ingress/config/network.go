@@ -0,0 +1,41 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"knative.dev/networking/pkg/config"
+)
+
+// NetworkWarnings returns the settings of config-network which do not work, or likely not
+// as intended, with this configuration. Each ConfigMap is valid on its own.
+func (i *Istio) NetworkWarnings(network *config.Config) []string {
+	var warnings []string
+	if len(i.IngressGateways) == 0 && len(i.LocalGateways) == 0 &&
+		network.ClusterLocalDomainTLS == config.EncryptionEnabled {
+		warnings = append(warnings, config.ClusterLocalDomainTLSKey+
+			" is enabled in mesh-only mode, where no gateway terminates TLS for cluster-local domains")
+	}
+	if len(i.IngressGateways) == 0 && network.ExternalDomainTLS {
+		warnings = append(warnings, config.ExternalDomainTLSKey+
+			" is enabled without external gateways, the certificates of external domains are never served")
+	}
+	if len(i.LocalGateways) == 0 && network.EnableMeshPodAddressability {
+		warnings = append(warnings, config.EnableMeshPodAddressabilityKey+
+			" is enabled without local gateways, services are only reachable from within the mesh")
+	}
+	return warnings
+}
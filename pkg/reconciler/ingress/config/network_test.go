@@ -0,0 +1,72 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"knative.dev/networking/pkg/config"
+)
+
+func TestNetworkWarnings(t *testing.T) {
+	meshOnly := &Istio{IngressGateways: []Gateway{}, LocalGateways: []Gateway{}}
+
+	tests := []struct {
+		name    string
+		istio   *Istio
+		network *config.Config
+		want    []string
+	}{{
+		name:    "defaults",
+		istio:   &Istio{IngressGateways: defaultIngressGateways(), LocalGateways: defaultLocalGateways()},
+		network: &config.Config{ExternalDomainTLS: true, ClusterLocalDomainTLS: config.EncryptionEnabled, EnableMeshPodAddressability: true},
+	}, {
+		name:    "mesh-only without TLS",
+		istio:   meshOnly,
+		network: &config.Config{ClusterLocalDomainTLS: config.EncryptionDisabled},
+	}, {
+		name:    "mesh-only with cluster-local-domain-tls",
+		istio:   meshOnly,
+		network: &config.Config{ClusterLocalDomainTLS: config.EncryptionEnabled},
+		want: []string{
+			"cluster-local-domain-tls is enabled in mesh-only mode, where no gateway terminates TLS for cluster-local domains",
+		},
+	}, {
+		name:    "external-domain-tls without external gateways",
+		istio:   &Istio{IngressGateways: []Gateway{}, LocalGateways: defaultLocalGateways()},
+		network: &config.Config{ExternalDomainTLS: true},
+		want: []string{
+			"external-domain-tls is enabled without external gateways, the certificates of external domains are never served",
+		},
+	}, {
+		name:    "mesh pod addressability without local gateways",
+		istio:   &Istio{IngressGateways: defaultIngressGateways(), LocalGateways: []Gateway{}},
+		network: &config.Config{EnableMeshPodAddressability: true},
+		want: []string{
+			"enable-mesh-pod-addressability is enabled without local gateways, services are only reachable from within the mesh",
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, tt.istio.NetworkWarnings(tt.network)); diff != "" {
+				t.Error("Unexpected warnings (-want, +got):", diff)
+			}
+		})
+	}
+}
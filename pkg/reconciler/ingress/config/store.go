@@ -18,6 +18,7 @@ package config
 
 import (
 	"context"
+	"sync/atomic"
 
 	"knative.dev/networking/pkg/config"
 	"knative.dev/pkg/configmap"
@@ -46,6 +47,10 @@ func ToContext(ctx context.Context, c *Config) context.Context {
 // +k8s:deepcopy-gen=false
 type Store struct {
 	*configmap.UntypedStore
+
+	// istio is the configuration of the IstioConfig resource, which takes precedence
+	// over the config-istio ConfigMap when set.
+	istio atomic.Pointer[Istio]
 }
 
 // NewStore creates a configmap.UntypedStore based config store.
@@ -78,10 +83,20 @@ func (s *Store) ToContext(ctx context.Context) context.Context {
 	return ToContext(ctx, s.Load())
 }
 
+// SetIstio overrides the configuration of the config-istio ConfigMap with the given one,
+// e.g. from an IstioConfig resource. A nil configuration restores the one of the ConfigMap.
+func (s *Store) SetIstio(istio *Istio) {
+	s.istio.Store(istio)
+}
+
 // Load fetches config from Store.
 func (s *Store) Load() *Config {
+	istio := s.istio.Load()
+	if istio == nil {
+		istio = s.UntypedLoad(IstioConfigName).(*Istio)
+	}
 	return &Config{
-		Istio:   s.UntypedLoad(IstioConfigName).(*Istio).DeepCopy(),
+		Istio:   istio.DeepCopy(),
 		Network: s.UntypedLoad(config.ConfigMapName).(*config.Config).DeepCopy(),
 	}
 }
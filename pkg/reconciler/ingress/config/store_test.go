@@ -68,3 +68,23 @@ func TestStoreImmutableConfig(t *testing.T) {
 		t.Error("Network config is not immuable")
 	}
 }
+
+func TestStoreSetIstio(t *testing.T) {
+	store := NewStore(logtesting.TestLogger(t))
+
+	istioConfig := ConfigMapFromTestFile(t, IstioConfigName)
+	store.OnConfigChanged(istioConfig)
+	store.OnConfigChanged(ConfigMapFromTestFile(t, netconfig.ConfigMapName))
+
+	override := &Istio{IngressGateways: []Gateway{{Namespace: "ns", Name: "override", ServiceURL: "override.ns.svc.cluster.local"}}}
+	store.SetIstio(override)
+	if diff := cmp.Diff(override, store.Load().Istio); diff != "" {
+		t.Error("Unexpected istio config (-want, +got):", diff)
+	}
+
+	store.SetIstio(nil)
+	expectedIstio, _ := NewIstioFromConfigMap(istioConfig)
+	if diff := cmp.Diff(expectedIstio, store.Load().Istio); diff != "" {
+		t.Error("Unexpected istio config (-want, +got):", diff)
+	}
+}
@@ -25,6 +25,27 @@ import (
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DomainClaim) DeepCopyInto(out *DomainClaim) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainClaim.
+func (in *DomainClaim) DeepCopy() *DomainClaim {
+	if in == nil {
+		return nil
+	}
+	out := new(DomainClaim)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Gateway) DeepCopyInto(out *Gateway) {
 	*out = *in
@@ -33,6 +54,16 @@ func (in *Gateway) DeepCopyInto(out *Gateway) {
 		*out = new(v1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Domains != nil {
+		in, out := &in.Domains, &out.Domains
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -46,6 +77,27 @@ func (in *Gateway) DeepCopy() *Gateway {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPSRedirect) DeepCopyInto(out *HTTPSRedirect) {
+	*out = *in
+	if in.ExemptPaths != nil {
+		in, out := &in.ExemptPaths, &out.ExemptPaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPSRedirect.
+func (in *HTTPSRedirect) DeepCopy() *HTTPSRedirect {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPSRedirect)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Istio) DeepCopyInto(out *Istio) {
 	*out = *in
@@ -63,6 +115,14 @@ func (in *Istio) DeepCopyInto(out *Istio) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	in.HTTPSRedirect.DeepCopyInto(&out.HTTPSRedirect)
+	if in.DomainClaims != nil {
+		in, out := &in.DomainClaims, &out.DomainClaims
+		*out = make([]DomainClaim, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
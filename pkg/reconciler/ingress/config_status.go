@@ -0,0 +1,143 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/testing/protocmp"
+	istiometa "istio.io/api/meta/v1alpha1"
+	"istio.io/client-go/pkg/apis/networking/v1beta1"
+	istiolisters "istio.io/client-go/pkg/listers/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/networking/pkg/apis/networking"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+	netconfig "knative.dev/networking/pkg/config"
+	"knative.dev/networking/pkg/status"
+)
+
+// reconciledCondition is the condition istiod reports on the resources it distributed
+// to all the proxies, when config distribution tracking is enabled.
+const reconciledCondition = "Reconciled"
+
+// generatedResources are the VirtualServices and per-Ingress Gateways generated for the
+// Ingress being reconciled.
+type generatedResources struct {
+	virtualServices []*v1beta1.VirtualService
+	gateways        []*v1beta1.Gateway
+}
+
+type generatedResourcesKey struct{}
+
+// withGeneratedResources attaches the resources generated for an Ingress to the context,
+// for the config status to be checked on them.
+func withGeneratedResources(ctx context.Context, vses []*v1beta1.VirtualService, gateways []*v1beta1.Gateway) context.Context {
+	return context.WithValue(ctx, generatedResourcesKey{}, generatedResources{
+		virtualServices: vses,
+		gateways:        gateways,
+	})
+}
+
+// configStatusManager finds Ingresses ready once istiod reports the current generation of
+// the resources generated for them as distributed to all the proxies. Unlike the probers,
+// it never sends requests to the gateways.
+type configStatusManager struct {
+	logger *zap.SugaredLogger
+
+	virtualServiceLister istiolisters.VirtualServiceLister
+	gatewayLister        istiolisters.GatewayLister
+}
+
+var _ status.Manager = (*configStatusManager)(nil)
+
+// IsReady implements status.Manager.
+func (m *configStatusManager) IsReady(ctx context.Context, ing *v1alpha1.Ingress) (bool, error) {
+	generated, ok := ctx.Value(generatedResourcesKey{}).(generatedResources)
+	if !ok {
+		return false, fmt.Errorf("no generated resources to check the config status of Ingress %s/%s", ing.Namespace, ing.Name)
+	}
+
+	for _, desired := range generated.virtualServices {
+		if desired.GetAnnotations()[networking.IngressClassAnnotationKey] != netconfig.IstioIngressClassName {
+			// Those are not created, see reconcileVirtualServices.
+			continue
+		}
+		vs, err := m.virtualServiceLister.VirtualServices(desired.Namespace).Get(desired.Name)
+		if apierrs.IsNotFound(err) {
+			return false, nil
+		} else if err != nil {
+			return false, fmt.Errorf("failed to get VirtualService: %w", err)
+		}
+		// The lister may not have caught up with the update of the VirtualService yet, in
+		// which case its status is about the previous spec.
+		if !cmp.Equal(&vs.Spec, &desired.Spec, protocmp.Transform()) || !isReconciled(vs.Generation, &vs.Status) {
+			m.logger.Debugf("Waiting for VirtualService %s/%s to be distributed", vs.Namespace, vs.Name)
+			return false, nil
+		}
+	}
+
+	for _, desired := range generated.gateways {
+		gateway, err := m.gatewayLister.Gateways(desired.Namespace).Get(desired.Name)
+		if apierrs.IsNotFound(err) {
+			return false, nil
+		} else if err != nil {
+			return false, fmt.Errorf("failed to get Gateway: %w", err)
+		}
+		if !cmp.Equal(&gateway.Spec, &desired.Spec, protocmp.Transform()) || !isReconciled(gateway.Generation, &gateway.Status) {
+			m.logger.Debugf("Waiting for Gateway %s/%s to be distributed", gateway.Namespace, gateway.Name)
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// isReconciled returns true if istiod reports the given generation of a resource as
+// distributed to all the proxies.
+func isReconciled(generation int64, status *istiometa.IstioStatus) bool {
+	for _, cond := range status.GetConditions() {
+		if cond.GetType() != reconciledCondition {
+			continue
+		}
+		observed := cond.GetObservedGeneration()
+		if observed == 0 {
+			observed = status.GetObservedGeneration()
+		}
+		return cond.GetStatus() == "True" && observed == generation
+	}
+	return false
+}
+
+// configStatusChanged returns true if the config status reported by istiod changed
+// between the given versions of a resource.
+func configStatusChanged(oldStatus, newStatus *istiometa.IstioStatus) bool {
+	return !cmp.Equal(oldStatus, newStatus, protocmp.Transform())
+}
+
+// onlyConfigStatusChanged returns true if an update of a resource generated for an Ingress
+// only changed its config status, which only matters to the readiness strategies using it.
+// Other changes may have to be reverted.
+func onlyConfigStatusChanged(oldObj, newObj metav1.Object, oldStatus, newStatus *istiometa.IstioStatus) bool {
+	return oldObj.GetGeneration() == newObj.GetGeneration() &&
+		equality.Semantic.DeepEqual(oldObj.GetLabels(), newObj.GetLabels()) &&
+		equality.Semantic.DeepEqual(oldObj.GetAnnotations(), newObj.GetAnnotations()) &&
+		configStatusChanged(oldStatus, newStatus)
+}
@@ -0,0 +1,320 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+	istiometa "istio.io/api/meta/v1alpha1"
+	istiov1beta1 "istio.io/api/networking/v1beta1"
+	"istio.io/client-go/pkg/apis/networking/v1beta1"
+	istiolisters "istio.io/client-go/pkg/listers/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/net-istio/pkg/reconciler/ingress/config"
+	"knative.dev/networking/pkg/apis/networking"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+	netconfig "knative.dev/networking/pkg/config"
+	fakestatusmanager "knative.dev/networking/pkg/testing/status"
+)
+
+func reconciledStatus(generation int64) *istiometa.IstioStatus {
+	return &istiometa.IstioStatus{
+		Conditions: []*istiometa.IstioCondition{{
+			Type:    reconciledCondition,
+			Status:  "True",
+			Message: "3/3 proxies up to date.",
+		}},
+		ObservedGeneration: generation,
+	}
+}
+
+func TestConfigStatusManager(t *testing.T) {
+	desiredVS := &v1beta1.VirtualService{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "default",
+			Name:        "whatever-ingress",
+			Annotations: map[string]string{networking.IngressClassAnnotationKey: netconfig.IstioIngressClassName},
+		},
+		Spec: istiov1beta1.VirtualService{Hosts: []string{"foo.bar.com"}},
+	}
+	desiredGateway := &v1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "istio-system", Name: "whatever-gateway"},
+		Spec: istiov1beta1.Gateway{
+			Servers: []*istiov1beta1.Server{{Hosts: []string{"foo.bar.com"}}},
+		},
+	}
+	vs := func(generation int64, status *istiometa.IstioStatus, hosts ...string) *v1beta1.VirtualService {
+		vs := desiredVS.DeepCopy()
+		vs.Generation = generation
+		vs.Status.Conditions = status.GetConditions()
+		vs.Status.ObservedGeneration = status.GetObservedGeneration()
+		if len(hosts) > 0 {
+			vs.Spec.Hosts = hosts
+		}
+		return vs
+	}
+	gateway := func(generation int64, status *istiometa.IstioStatus) *v1beta1.Gateway {
+		gateway := desiredGateway.DeepCopy()
+		gateway.Generation = generation
+		gateway.Status.Conditions = status.GetConditions()
+		gateway.Status.ObservedGeneration = status.GetObservedGeneration()
+		return gateway
+	}
+
+	tests := []struct {
+		name     string
+		vses     []*v1beta1.VirtualService
+		gateways []*v1beta1.Gateway
+		want     bool
+	}{{
+		name:     "all reconciled",
+		vses:     []*v1beta1.VirtualService{vs(2, reconciledStatus(2))},
+		gateways: []*v1beta1.Gateway{gateway(1, reconciledStatus(1))},
+		want:     true,
+	}, {
+		name:     "VirtualService not created yet",
+		gateways: []*v1beta1.Gateway{gateway(1, reconciledStatus(1))},
+	}, {
+		name:     "VirtualService not reconciled",
+		vses:     []*v1beta1.VirtualService{vs(2, nil)},
+		gateways: []*v1beta1.Gateway{gateway(1, reconciledStatus(1))},
+	}, {
+		name:     "previous generation of the VirtualService reconciled",
+		vses:     []*v1beta1.VirtualService{vs(2, reconciledStatus(1))},
+		gateways: []*v1beta1.Gateway{gateway(1, reconciledStatus(1))},
+	}, {
+		name:     "update of the VirtualService not in the lister yet",
+		vses:     []*v1beta1.VirtualService{vs(1, reconciledStatus(1), "old.bar.com")},
+		gateways: []*v1beta1.Gateway{gateway(1, reconciledStatus(1))},
+	}, {
+		name: "Gateway not reconciled",
+		vses: []*v1beta1.VirtualService{vs(2, reconciledStatus(2))},
+		gateways: []*v1beta1.Gateway{gateway(3, &istiometa.IstioStatus{
+			Conditions: []*istiometa.IstioCondition{{
+				Type:    reconciledCondition,
+				Status:  "False",
+				Message: "2/3 proxies up to date.",
+			}},
+			ObservedGeneration: 3,
+		})},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			vsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			for _, vs := range test.vses {
+				vsIndexer.Add(vs)
+			}
+			gatewayIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			for _, gateway := range test.gateways {
+				gatewayIndexer.Add(gateway)
+			}
+			manager := &configStatusManager{
+				logger:               zaptest.NewLogger(t).Sugar(),
+				virtualServiceLister: istiolisters.NewVirtualServiceLister(vsIndexer),
+				gatewayLister:        istiolisters.NewGatewayLister(gatewayIndexer),
+			}
+
+			// VirtualServices without the Istio ingress class are not created, so not checked.
+			foreignVS := desiredVS.DeepCopy()
+			foreignVS.Name = "foreign"
+			foreignVS.Annotations = nil
+
+			ctx := withGeneratedResources(context.Background(),
+				[]*v1beta1.VirtualService{desiredVS, foreignVS}, []*v1beta1.Gateway{desiredGateway})
+			ready, err := manager.IsReady(ctx, &v1alpha1.Ingress{})
+			if err != nil {
+				t.Fatal("IsReady() =", err)
+			}
+			if ready != test.want {
+				t.Errorf("IsReady() = %t, want %t", ready, test.want)
+			}
+		})
+	}
+
+	manager := &configStatusManager{}
+	if _, err := manager.IsReady(context.Background(), &v1alpha1.Ingress{}); err == nil {
+		t.Error("IsReady() without generated resources = nil, wanted an error")
+	}
+}
+
+func TestIsReconciled(t *testing.T) {
+	tests := []struct {
+		name   string
+		status *istiometa.IstioStatus
+		want   bool
+	}{{
+		name: "no status",
+	}, {
+		name:   "reconciled",
+		status: reconciledStatus(2),
+		want:   true,
+	}, {
+		name:   "previous generation reconciled",
+		status: reconciledStatus(1),
+	}, {
+		name: "generation of the condition",
+		status: &istiometa.IstioStatus{
+			Conditions: []*istiometa.IstioCondition{{
+				Type:               reconciledCondition,
+				Status:             "True",
+				ObservedGeneration: 2,
+			}},
+			ObservedGeneration: 1,
+		},
+		want: true,
+	}, {
+		name: "other condition",
+		status: &istiometa.IstioStatus{
+			Conditions: []*istiometa.IstioCondition{{
+				Type:   "Accepted",
+				Status: "True",
+			}},
+			ObservedGeneration: 2,
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := isReconciled(2, test.status); got != test.want {
+				t.Errorf("isReconciled() = %t, want %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestOnlyConfigStatusChanged(t *testing.T) {
+	vs := &v1beta1.VirtualService{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "vs",
+			Namespace:  "default",
+			Generation: 2,
+			Labels:     map[string]string{networking.IngressLabelKey: "ingress"},
+		},
+		Status: *reconciledStatus(1),
+	}
+
+	tests := []struct {
+		name   string
+		update func(*v1beta1.VirtualService)
+		want   bool
+	}{{
+		name:   "resync",
+		update: func(*v1beta1.VirtualService) {},
+	}, {
+		name: "config status",
+		update: func(vs *v1beta1.VirtualService) {
+			vs.Status = *reconciledStatus(2)
+		},
+		want: true,
+	}, {
+		name: "spec and config status",
+		update: func(vs *v1beta1.VirtualService) {
+			vs.Generation++
+			vs.Status = *reconciledStatus(2)
+		},
+	}, {
+		name: "labels and config status",
+		update: func(vs *v1beta1.VirtualService) {
+			vs.Labels = nil
+			vs.Status = *reconciledStatus(2)
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			updated := vs.DeepCopy()
+			test.update(updated)
+			if got := onlyConfigStatusChanged(vs, updated, &vs.Status, &updated.Status); got != test.want {
+				t.Errorf("onlyConfigStatusChanged() = %t, want %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestIngressStatusManagerConfigStatus(t *testing.T) {
+	tests := []struct {
+		name         string
+		strategy     config.ReadinessStrategy
+		meshOnly     bool
+		configStatus bool
+		probe        bool
+		want         bool
+	}{{
+		name:         "probe",
+		configStatus: false,
+		probe:        true,
+		want:         true,
+	}, {
+		name:         "config status",
+		strategy:     config.ReadinessStrategyConfigStatus,
+		configStatus: true,
+		probe:        false,
+		want:         true,
+	}, {
+		name:         "both, not distributed",
+		strategy:     config.ReadinessStrategyBoth,
+		configStatus: false,
+		probe:        true,
+	}, {
+		name:         "both, not probed",
+		strategy:     config.ReadinessStrategyBoth,
+		configStatus: true,
+		probe:        false,
+	}, {
+		name:         "both",
+		strategy:     config.ReadinessStrategyBoth,
+		configStatus: true,
+		probe:        true,
+		want:         true,
+	}, {
+		name:         "both, mesh-only",
+		strategy:     config.ReadinessStrategyBoth,
+		meshOnly:     true,
+		configStatus: true,
+		probe:        false,
+		want:         true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			manager := &ingressStatusManager{
+				http: &fakestatusmanager.FakeStatusManager{
+					FakeIsReady: func(context.Context, *v1alpha1.Ingress) (bool, error) { return test.probe, nil },
+				},
+				configStatus: &fakestatusmanager.FakeStatusManager{
+					FakeIsReady: func(context.Context, *v1alpha1.Ingress) (bool, error) { return test.configStatus, nil },
+				},
+			}
+			istio := &config.Istio{
+				IngressGateways:   []config.Gateway{{Name: "gateway", Namespace: "istio-system"}},
+				ReadinessStrategy: test.strategy,
+			}
+			if test.meshOnly {
+				istio.IngressGateways = nil
+			}
+			ctx := config.ToContext(context.Background(), &config.Config{Istio: istio})
+
+			if ready, err := manager.IsReady(ctx, &v1alpha1.Ingress{}); err != nil || ready != test.want {
+				t.Errorf("IsReady() = %t, %v, want %t", ready, err, test.want)
+			}
+		})
+	}
+}
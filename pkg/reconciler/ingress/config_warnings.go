@@ -0,0 +1,64 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/net-istio/pkg/reconciler/ingress/config"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/system"
+)
+
+// configWarnings reports the settings of config-network which do not work with config-istio
+// as Warning events on config-istio. Each set of warnings is reported once.
+type configWarnings struct {
+	mu   sync.Mutex
+	last string
+}
+
+func (w *configWarnings) report(ctx context.Context) {
+	cfg := config.FromContext(ctx)
+	warnings := cfg.Istio.NetworkWarnings(cfg.Network)
+
+	key := strings.Join(warnings, "\n")
+	w.mu.Lock()
+	if key == w.last {
+		w.mu.Unlock()
+		return
+	}
+	w.last = key
+	w.mu.Unlock()
+
+	istioConfigRef := &corev1.ObjectReference{
+		APIVersion: "v1",
+		Kind:       "ConfigMap",
+		Namespace:  system.Namespace(),
+		Name:       config.IstioConfigName,
+	}
+	recorder := controller.GetEventRecorder(ctx)
+	for _, warning := range warnings {
+		logging.FromContext(ctx).Warn("Inconsistent configuration: ", warning)
+		if recorder != nil {
+			recorder.Event(istioConfigRef, corev1.EventTypeWarning, "InconsistentConfig", warning)
+		}
+	}
+}
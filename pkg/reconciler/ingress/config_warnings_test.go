@@ -0,0 +1,57 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/client-go/tools/record"
+	"knative.dev/net-istio/pkg/reconciler/ingress/config"
+	netconfig "knative.dev/networking/pkg/config"
+	"knative.dev/pkg/controller"
+)
+
+func TestConfigWarningsReportedOnce(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	ctx := controller.WithEventRecorder(context.Background(), recorder)
+
+	meshOnly := &config.Istio{IngressGateways: []config.Gateway{}, LocalGateways: []config.Gateway{}}
+	withTLS := config.ToContext(ctx, &config.Config{
+		Istio:   meshOnly,
+		Network: &netconfig.Config{ExternalDomainTLS: true},
+	})
+
+	var w configWarnings
+	w.report(withTLS)
+	w.report(withTLS)
+
+	want := "Warning InconsistentConfig external-domain-tls is enabled without external gateways, the certificates of external domains are never served"
+	if got := <-recorder.Events; got != want {
+		t.Errorf("Event = %q, want %q", got, want)
+	}
+	if len(recorder.Events) != 0 {
+		t.Errorf("Got %d more events, the same warnings should only be reported once", len(recorder.Events))
+	}
+
+	// Fixing and breaking the configuration again reports the warning again.
+	w.report(config.ToContext(ctx, &config.Config{Istio: meshOnly, Network: &netconfig.Config{}}))
+	w.report(withTLS)
+	if len(recorder.Events) != 1 {
+		t.Errorf("Got %d events, want 1", len(recorder.Events))
+	}
+}
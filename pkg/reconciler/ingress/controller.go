@@ -19,11 +19,14 @@ package ingress
 import (
 	"context"
 
+	"go.opentelemetry.io/otel"
 	"go.uber.org/zap"
 	v1 "k8s.io/client-go/informers/core/v1"
+	istioconfiginformer "knative.dev/net-istio/pkg/client/injection/informers/istio/v1alpha1/istioconfig"
 	istioclient "knative.dev/net-istio/pkg/client/istio/injection/client"
 	gatewayinformer "knative.dev/net-istio/pkg/client/istio/injection/informers/networking/v1beta1/gateway"
 	virtualserviceinformer "knative.dev/net-istio/pkg/client/istio/injection/informers/networking/v1beta1/virtualservice"
+	endpointsliceinformer "knative.dev/net-istio/pkg/client/kube/injection/informers/discovery/v1/endpointslice"
 	"knative.dev/net-istio/pkg/reconciler/ingress/config"
 	"knative.dev/networking/pkg/apis/networking"
 	"knative.dev/networking/pkg/apis/networking/v1alpha1"
@@ -32,8 +35,7 @@ import (
 	netconfig "knative.dev/networking/pkg/config"
 	"knative.dev/networking/pkg/status"
 	kubeclient "knative.dev/pkg/client/injection/kube/client"
-	endpointsinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/endpoints"
-	podinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/pod"
+	namespaceinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/namespace"
 	secretfilteredinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/secret/filtered"
 	serviceinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/service"
 	filteredFactory "knative.dev/pkg/client/injection/kube/informers/factory/filtered"
@@ -42,9 +44,12 @@ import (
 	"knative.dev/pkg/logging"
 	"knative.dev/pkg/logging/logkey"
 	"knative.dev/pkg/reconciler"
+	"knative.dev/pkg/system"
 
 	v1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/cache"
 )
@@ -85,17 +90,29 @@ func newControllerWithOptions(
 	secretInformer := getSecretInformer(ctx)
 	serviceInformer := serviceinformer.Get(ctx)
 	ingressInformer := ingressinformer.Get(ctx)
+	namespaceInformer := namespaceinformer.Get(ctx)
+	istioConfigInformer := istioconfiginformer.Get(ctx)
+
+	if err := virtualServiceInformer.Informer().AddIndexers(virtualServiceHostIndexers); err != nil {
+		logger.Fatalw("Failed to index the VirtualServices by host", zap.Error(err))
+	}
 
 	c := &Reconciler{
-		kubeclient:           kubeclient.Get(ctx),
-		istioClientSet:       istioclient.Get(ctx),
-		virtualServiceLister: virtualServiceInformer.Lister(),
-		gatewayLister:        gatewayInformer.Lister(),
-		secretLister:         secretInformer.Lister(),
-		svcLister:            serviceInformer.Lister(),
+		kubeclient:            kubeclient.Get(ctx),
+		istioClientSet:        istioclient.Get(ctx),
+		virtualServiceLister:  virtualServiceInformer.Lister(),
+		virtualServiceIndexer: virtualServiceInformer.Informer().GetIndexer(),
+		gatewayLister:         gatewayInformer.Lister(),
+		secretLister:          secretInformer.Lister(),
+		svcLister:             serviceInformer.Lister(),
+		namespaceLister:       namespaceInformer.Lister(),
+		ingressLister:         ingressInformer.Lister(),
+		metrics:               newMetrics(otel.GetMeterProvider()),
+		tracer:                otel.Tracer(scopeName),
 	}
 	myFilterFunc := reconciler.AnnotationFilterFunc(networking.IngressClassAnnotationKey, netconfig.IstioIngressClassName, true)
 
+	var configStore *config.Store
 	impl := ingressreconciler.NewImpl(ctx, c, netconfig.IstioIngressClassName, func(impl *controller.Impl) controller.Options {
 		configsToResync := []interface{}{
 			&config.Istio{},
@@ -104,7 +121,7 @@ func newControllerWithOptions(
 		resyncIngressesOnConfigChange := configmap.TypeFilter(configsToResync...)(func(string, interface{}) {
 			impl.FilteredGlobalResync(myFilterFunc, ingressInformer.Informer())
 		})
-		configStore := config.NewStore(logger.Named("config-store"), resyncIngressesOnConfigChange)
+		configStore = config.NewStore(logger.Named("config-store"), resyncIngressesOnConfigChange)
 		configStore.WatchConfigs(cmw)
 		return controller.Options{
 			ConfigStore:       configStore,
@@ -117,33 +134,140 @@ func newControllerWithOptions(
 		Handler:    controller.HandleAll(impl.Enqueue),
 	})
 
+	// The IstioConfig named like the config-istio ConfigMap takes precedence over it.
+	istioConfigInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.FilterWithNameAndNamespace(system.Namespace(), config.IstioConfigName),
+		Handler: controller.HandleAll(func(interface{}) {
+			istioConfig, err := istioConfigInformer.Lister().IstioConfigs(system.Namespace()).Get(config.IstioConfigName)
+			if apierrs.IsNotFound(err) {
+				configStore.SetIstio(nil)
+			} else if err != nil {
+				logger.Errorw("Failed to get the IstioConfig", zap.Error(err))
+				return
+			} else {
+				istio, err := config.NewIstioFromIstioConfig(&istioConfig.Spec)
+				if err != nil {
+					// The previous configuration is kept, the error is reported in the IstioConfig status.
+					logger.Errorw("Ignoring invalid IstioConfig", zap.Error(err))
+					return
+				}
+				configStore.SetIstio(istio)
+			}
+			impl.FilteredGlobalResync(myFilterFunc, ingressInformer.Informer())
+		}),
+	})
+
+	// Gateways may select Ingresses by the labels of their namespace.
+	namespaceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldNs, newNs := oldObj.(*corev1.Namespace), newObj.(*corev1.Namespace)
+			if equality.Semantic.DeepEqual(oldNs.Labels, newNs.Labels) {
+				return
+			}
+			impl.FilteredGlobalResync(func(obj interface{}) bool {
+				return myFilterFunc(obj) && obj.(*v1alpha1.Ingress).Namespace == newNs.Name
+			}, ingressInformer.Informer())
+		},
+	})
+
+	// The status of the Ingresses reports the addresses of the load balancers of the gateways.
+	serviceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldSvc, newSvc := oldObj.(*corev1.Service), newObj.(*corev1.Service)
+			if !loadBalancerChanged(oldSvc, newSvc) || !isGatewayService(newSvc, configStore.Load().Istio) {
+				return
+			}
+			impl.FilteredGlobalResync(myFilterFunc, ingressInformer.Informer())
+		},
+	})
+
+	// The config status of the generated resources is only waited for by some readiness
+	// strategies, see configStatusManager.
+	usesConfigStatus := func() bool {
+		istio := configStore.Load().Istio
+		return istio != nil && istio.ReadinessStrategy.UsesConfigStatus()
+	}
 	virtualServiceInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
 		FilterFunc: controller.FilterController(&v1alpha1.Ingress{}),
-		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc: impl.EnqueueControllerOf,
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				oldVS, newVS := oldObj.(*v1beta1.VirtualService), newObj.(*v1beta1.VirtualService)
+				if onlyConfigStatusChanged(oldVS, newVS, &oldVS.Status, &newVS.Status) && !usesConfigStatus() {
+					return
+				}
+				impl.EnqueueControllerOf(newObj)
+			},
+			DeleteFunc: impl.EnqueueControllerOf,
+		},
+	})
+	gatewayInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.FilterController(&v1alpha1.Ingress{}),
+		Handler: cache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				oldGateway, newGateway := oldObj.(*v1beta1.Gateway), newObj.(*v1beta1.Gateway)
+				if usesConfigStatus() && configStatusChanged(&oldGateway.Status, &newGateway.Status) {
+					impl.EnqueueControllerOf(newObj)
+				}
+			},
+		},
 	})
 
-	endpointsInformer := endpointsinformer.Get(ctx)
-	podInformer := podinformer.Get(ctx)
+	endpointSliceInformer := endpointsliceinformer.Get(ctx)
 	resyncOnIngressReady := func(ing *v1alpha1.Ingress) {
 		impl.EnqueueKey(types.NamespacedName{Namespace: ing.GetNamespace(), Name: ing.GetName()})
 	}
+	probeTargetLister := NewProbeTargetLister(
+		logger.Named("probe-lister"),
+		gatewayInformer.Lister(),
+		endpointSliceInformer.Lister(),
+		serviceInformer.Lister(),
+		secretInformer.Lister())
 	statusProber := status.NewProber(
 		logger.Named("status-manager"),
-		NewProbeTargetLister(
-			logger.Named("probe-lister"),
-			gatewayInformer.Lister(),
-			endpointsInformer.Lister(),
-			serviceInformer.Lister()),
+		probeTargetLister,
+		resyncOnIngressReady)
+	passthroughProber := newPassthroughProber(
+		logger.Named("passthrough-prober"),
+		probeTargetLister.(passthroughTargetLister),
+		resyncOnIngressReady)
+	hostProber := newHostProber(
+		logger.Named("host-prober"),
+		probeTargetLister.(hostTargetLister),
 		resyncOnIngressReady)
-	c.statusManager = statusProber
+	statusManager := newMeasuredStatusManager(&ingressStatusManager{
+		http:        statusProber,
+		passthrough: passthroughProber,
+		hosts:       hostProber,
+		configStatus: &configStatusManager{
+			logger:               logger.Named("config-status"),
+			virtualServiceLister: virtualServiceInformer.Lister(),
+			gatewayLister:        gatewayInformer.Lister(),
+		},
+	}, c.metrics)
+	c.statusManager = statusManager
 	statusProber.Start(ctx.Done())
 
-	podInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		// Cancel probing when a Pod is deleted
-		DeleteFunc: statusProber.CancelPodProbing,
+	// Cancel probing of the Pods of the gateways when their endpoint starts terminating
+	// or goes away.
+	cancelDepartedPodProbing := func(oldObj, newObj interface{}) {
+		for _, pod := range departedPods(endpointSliceInformer.Lister(), oldObj, newObj) {
+			statusProber.CancelPodProbing(pod)
+			hostProber.CancelPodProbing(pod)
+		}
+	}
+	endpointSliceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			cancelDepartedPodProbing(nil, obj)
+		},
+		UpdateFunc: cancelDepartedPodProbing,
+		DeleteFunc: func(obj interface{}) {
+			cancelDepartedPodProbing(obj, nil)
+		},
 	})
 
 	c.tracker = impl.Tracker
+	c.enqueueIngress = impl.EnqueueKey
 
 	secretInformer.Informer().AddEventHandler(controller.HandleAll(
 		controller.EnsureTypeMeta(
@@ -159,14 +283,30 @@ func newControllerWithOptions(
 		),
 	))
 
+	// Ingresses track the VirtualServices holding the hosts they lost, see findHostConflicts.
+	virtualServiceInformer.Informer().AddEventHandler(controller.HandleAll(
+		controller.EnsureTypeMeta(
+			c.tracker.OnChanged,
+			v1beta1.SchemeGroupVersion.WithKind("VirtualService"),
+		),
+	))
+
 	ingressInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		// Cancel probing when a Ingress is deleted
 		DeleteFunc: combineFunc(
 			statusProber.CancelIngressProbing,
+			passthroughProber.CancelIngressProbing,
+			hostProber.CancelIngressProbing,
+			statusManager.cancelProbing,
 			c.tracker.OnDeletedObserver,
 		),
 	})
 
+	if err := c.metrics.observeResources(virtualServiceInformer.Informer(), gatewayInformer.Informer(), secretInformer.Informer(),
+		gatewayInformer.Lister(), func() *config.Istio { return configStore.Load().Istio }); err != nil {
+		logger.Errorw("Failed to register the metrics of the managed resources", zap.Error(err))
+	}
+
 	for _, opt := range opts {
 		opt(c)
 	}
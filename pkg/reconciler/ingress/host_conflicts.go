@@ -0,0 +1,233 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"istio.io/client-go/pkg/apis/networking/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/net-istio/pkg/reconciler/ingress/config"
+	"knative.dev/net-istio/pkg/reconciler/ingress/resources"
+	"knative.dev/networking/pkg/apis/networking"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+	"knative.dev/pkg/controller"
+)
+
+// hostConflictReason is the reason of the LoadBalancerReady condition of the Ingresses
+// with hosts that are not routed because of a host conflict.
+const hostConflictReason = "HostConflict"
+
+// virtualServiceHostIndex is the name of the index of the VirtualServices generated for
+// Ingresses by host.
+const virtualServiceHostIndex = "host"
+
+// virtualServiceHostIndexers index the VirtualServices to find host conflicts without
+// listing all of them on every reconciliation.
+var virtualServiceHostIndexers = cache.Indexers{virtualServiceHostIndex: indexVirtualServiceHosts}
+
+// indexVirtualServiceHosts returns the hosts of the VirtualServices generated for Ingresses.
+func indexVirtualServiceHosts(obj interface{}) ([]string, error) {
+	vs, ok := obj.(*v1beta1.VirtualService)
+	if !ok {
+		return nil, nil
+	}
+	if _, ok := vs.Labels[networking.IngressLabelKey]; !ok {
+		return nil, nil
+	}
+	return vs.Spec.GetHosts(), nil
+}
+
+// hostConflict is a host of an Ingress that is not routed.
+type hostConflict struct {
+	Host string
+	// Owner is the Ingress that claimed the host first, or empty if the domain claims
+	// do not allow the namespace of the Ingress to claim the host.
+	Owner types.NamespacedName
+}
+
+// findHostConflicts returns the hosts of the given Ingress that must not be routed, sorted by
+// host. Istio merges the VirtualServices of a host in an unspecified order, so a host claimed
+// by the Ingresses of several namespaces is only routed for the Ingress that claimed it first,
+// i.e. the oldest one. The VirtualServices holding the lost hosts are tracked, so that the
+// Ingress is reconciled again once they release them, and the Ingresses that claimed the hosts
+// of the given Ingress later are enqueued to release them.
+func (r *Reconciler) findHostConflicts(ctx context.Context, ing *v1alpha1.Ingress) ([]hostConflict, error) {
+	istio := config.FromContext(ctx).Istio
+
+	owners := make(map[string]types.NamespacedName)
+	hosts := sets.New[string]()
+	for _, rule := range ing.Spec.Rules {
+		for _, host := range rule.Hosts {
+			if istio.MayClaimHost(ing.Namespace, host) {
+				hosts.Insert(host)
+			} else {
+				owners[host] = types.NamespacedName{}
+			}
+		}
+	}
+
+	seen := sets.New[types.NamespacedName]()
+	var vses []*v1beta1.VirtualService
+	for _, host := range sets.List(hosts) {
+		objs, err := r.virtualServiceIndexer.ByIndex(virtualServiceHostIndex, host)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the VirtualServices of host %s: %w", host, err)
+		}
+		for _, obj := range objs {
+			vs := obj.(*v1beta1.VirtualService)
+			if key := (types.NamespacedName{Namespace: vs.Namespace, Name: vs.Name}); !seen.Has(key) {
+				seen.Insert(key)
+				vses = append(vses, vs)
+			}
+		}
+	}
+	// Sort the VirtualServices to report the same owner for a host on every reconciliation.
+	sort.Slice(vses, func(i, j int) bool {
+		if vses[i].Namespace != vses[j].Namespace {
+			return vses[i].Namespace < vses[j].Namespace
+		}
+		return vses[i].Name < vses[j].Name
+	})
+
+	enqueued := sets.New[types.NamespacedName]()
+	for _, vs := range vses {
+		// The Ingresses of a namespace are trusted to share their hosts.
+		if vs.Namespace == ing.Namespace {
+			continue
+		}
+		ref := metav1.GetControllerOf(vs)
+		if ref == nil || ref.Kind != "Ingress" {
+			continue
+		}
+		shared := hosts.Intersection(sets.New(vs.Spec.GetHosts()...))
+		if shared.Len() == 0 {
+			continue
+		}
+
+		other, err := r.ingressLister.Ingresses(vs.Namespace).Get(ref.Name)
+		if apierrs.IsNotFound(err) {
+			// The VirtualService is about to be garbage collected.
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to get Ingress %s/%s: %w", vs.Namespace, ref.Name, err)
+		}
+		if other.UID != ref.UID {
+			continue
+		}
+
+		owner := types.NamespacedName{Namespace: other.Namespace, Name: other.Name}
+		if !claimedBefore(other, ing) {
+			if !enqueued.Has(owner) && r.enqueueIngress != nil {
+				enqueued.Insert(owner)
+				r.enqueueIngress(owner)
+			}
+			continue
+		}
+		if err := r.tracker.TrackReference(resources.VirtualServiceRef(vs), ing); err != nil {
+			return nil, err
+		}
+		for host := range shared {
+			if _, ok := owners[host]; !ok {
+				owners[host] = owner
+			}
+		}
+	}
+
+	conflicts := make([]hostConflict, 0, len(owners))
+	for host, owner := range owners {
+		conflicts = append(conflicts, hostConflict{Host: host, Owner: owner})
+	}
+	slices.SortFunc(conflicts, func(a, b hostConflict) int {
+		return strings.Compare(a.Host, b.Host)
+	})
+	return conflicts, nil
+}
+
+// claimedBefore returns true if the Ingress a claimed its hosts before the Ingress b.
+func claimedBefore(a, b *v1alpha1.Ingress) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	return a.Name < b.Name
+}
+
+// withoutHosts returns a copy of the given Ingress without the conflicting hosts, to generate
+// the resources of the Ingress from. Rules and TLS entries left without hosts are removed, so
+// that no server is programmed and no certificate is copied for the conflicting hosts.
+func withoutHosts(ing *v1alpha1.Ingress, conflicts []hostConflict) *v1alpha1.Ingress {
+	if len(conflicts) == 0 {
+		return ing
+	}
+	excluded := sets.New[string]()
+	for _, conflict := range conflicts {
+		excluded.Insert(conflict.Host)
+	}
+
+	ing = ing.DeepCopy()
+	rules := make([]v1alpha1.IngressRule, 0, len(ing.Spec.Rules))
+	for _, rule := range ing.Spec.Rules {
+		rule.Hosts = slices.DeleteFunc(rule.Hosts, excluded.Has)
+		if len(rule.Hosts) > 0 {
+			rules = append(rules, rule)
+		}
+	}
+	ing.Spec.Rules = rules
+
+	tls := make([]v1alpha1.IngressTLS, 0, len(ing.Spec.TLS))
+	for _, t := range ing.Spec.TLS {
+		t.Hosts = slices.DeleteFunc(t.Hosts, excluded.Has)
+		if len(t.Hosts) > 0 {
+			tls = append(tls, t)
+		}
+	}
+	ing.Spec.TLS = tls
+	return ing
+}
+
+// markHostConflicts fails the LoadBalancerReady condition of the given Ingress with the hosts
+// that are not routed, and reports them with a Warning event when they change.
+func markHostConflicts(ctx context.Context, ing *v1alpha1.Ingress, conflicts []hostConflict) {
+	descriptions := make([]string, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		if conflict.Owner.Name == "" {
+			descriptions = append(descriptions, fmt.Sprintf("%s may not be claimed from namespace %s", conflict.Host, ing.Namespace))
+		} else {
+			descriptions = append(descriptions, fmt.Sprintf("%s is claimed by Ingress %s", conflict.Host, conflict.Owner))
+		}
+	}
+	message := "Hosts not routed: " + strings.Join(descriptions, "; ")
+
+	cond := ing.Status.GetCondition(v1alpha1.IngressConditionLoadBalancerReady)
+	if recorder := controller.GetEventRecorder(ctx); recorder != nil &&
+		(cond == nil || cond.Reason != hostConflictReason || cond.Message != message) {
+		recorder.Event(ing, corev1.EventTypeWarning, hostConflictReason, message)
+	}
+	ing.Status.MarkLoadBalancerFailed(hostConflictReason, message)
+}
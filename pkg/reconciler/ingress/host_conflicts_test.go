@@ -0,0 +1,313 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"istio.io/client-go/pkg/apis/networking/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/net-istio/pkg/reconciler/ingress/config"
+	"knative.dev/net-istio/pkg/reconciler/ingress/resources"
+	"knative.dev/networking/pkg/apis/networking"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+	netconfig "knative.dev/networking/pkg/config"
+	"knative.dev/pkg/controller"
+
+	. "knative.dev/net-istio/pkg/reconciler/testing"
+	. "knative.dev/pkg/reconciler/testing"
+)
+
+var conflictTime = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func conflictIngress(namespace, name string, created time.Time, hosts ...string) *v1alpha1.Ingress {
+	return &v1alpha1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         namespace,
+			Name:              name,
+			UID:               types.UID(namespace + "-" + name),
+			CreationTimestamp: metav1.NewTime(created),
+			Annotations: map[string]string{
+				networking.IngressClassAnnotationKey: netconfig.IstioIngressClassName,
+			},
+		},
+		Spec: v1alpha1.IngressSpec{
+			Rules: []v1alpha1.IngressRule{{
+				Hosts:      hosts,
+				Visibility: v1alpha1.IngressVisibilityExternalIP,
+				HTTP: &v1alpha1.HTTPIngressRuleValue{
+					Paths: []v1alpha1.HTTPIngressPath{{
+						Splits: []v1alpha1.IngressBackendSplit{{
+							IngressBackend: v1alpha1.IngressBackend{
+								ServiceNamespace: namespace,
+								ServiceName:      name,
+								ServicePort:      intstr.FromInt(80),
+							},
+							Percent: 100,
+						}},
+					}},
+				},
+			}},
+		},
+	}
+}
+
+// conflictVirtualServices returns the VirtualServices of the given Ingress.
+func conflictVirtualServices(t *testing.T, ing *v1alpha1.Ingress) []runtime.Object {
+	t.Helper()
+	gateways := map[v1alpha1.IngressVisibility]sets.Set[string]{
+		v1alpha1.IngressVisibilityExternalIP:   sets.New("knative-serving/knative-ingress-gateway"),
+		v1alpha1.IngressVisibilityClusterLocal: sets.New("knative-serving/knative-local-gateway"),
+	}
+	vses, err := resources.MakeVirtualServices(ing, gateways)
+	if err != nil {
+		t.Fatal("MakeVirtualServices() =", err)
+	}
+	objs := make([]runtime.Object, 0, len(vses))
+	for _, vs := range vses {
+		objs = append(objs, vs)
+	}
+	return objs
+}
+
+// conflictObjects returns the given Ingresses along with their VirtualServices.
+func conflictObjects(t *testing.T, ings ...*v1alpha1.Ingress) []runtime.Object {
+	t.Helper()
+	var objs []runtime.Object
+	for _, ing := range ings {
+		objs = append(objs, ing)
+		objs = append(objs, conflictVirtualServices(t, ing)...)
+	}
+	return objs
+}
+
+// virtualServiceHostIndexer returns the indexer of the VirtualServices of the given listers,
+// indexed by host as the informer of the controller.
+func virtualServiceHostIndexer(listers *Listers) cache.Indexer {
+	indexer := listers.IndexerFor(&v1beta1.VirtualService{})
+	if err := indexer.AddIndexers(virtualServiceHostIndexers); err != nil {
+		panic(err)
+	}
+	return indexer
+}
+
+func TestFindHostConflicts(t *testing.T) {
+	ing := conflictIngress("team-b", "route", conflictTime, "foo.example.com", "bar.example.com")
+	earlier := conflictTime.Add(-time.Hour)
+	later := conflictTime.Add(time.Hour)
+	recreated := conflictIngress("team-a", "other", later, "foo.example.com")
+	recreated.UID = "recreated"
+
+	tests := []struct {
+		name         string
+		claims       []config.DomainClaim
+		objs         []runtime.Object
+		want         []hostConflict
+		wantTracked  []string
+		wantEnqueued []types.NamespacedName
+	}{{
+		name: "no other Ingress",
+		objs: conflictObjects(t, ing),
+	}, {
+		name: "host claimed earlier in the same namespace",
+		objs: conflictObjects(t, ing, conflictIngress("team-b", "other", earlier, "foo.example.com")),
+	}, {
+		name: "host claimed earlier in another namespace",
+		objs: conflictObjects(t, ing, conflictIngress("team-a", "other", earlier, "foo.example.com")),
+		want: []hostConflict{{
+			Host:  "foo.example.com",
+			Owner: types.NamespacedName{Namespace: "team-a", Name: "other"},
+		}},
+		wantTracked: []string{"team-a/other-ingress"},
+	}, {
+		name: "host claimed at the same time in another namespace",
+		objs: conflictObjects(t, ing, conflictIngress("team-a", "other", conflictTime, "bar.example.com")),
+		want: []hostConflict{{
+			Host:  "bar.example.com",
+			Owner: types.NamespacedName{Namespace: "team-a", Name: "other"},
+		}},
+		wantTracked: []string{"team-a/other-ingress"},
+	}, {
+		name:         "host claimed later in another namespace",
+		objs:         conflictObjects(t, ing, conflictIngress("team-a", "other", later, "foo.example.com")),
+		wantEnqueued: []types.NamespacedName{{Namespace: "team-a", Name: "other"}},
+	}, {
+		name: "Ingress of the VirtualService is gone",
+		objs: append(conflictObjects(t, ing),
+			conflictVirtualServices(t, conflictIngress("team-a", "other", earlier, "foo.example.com"))...),
+	}, {
+		name: "Ingress of the VirtualService was recreated",
+		objs: append(append(conflictObjects(t, ing), recreated),
+			conflictVirtualServices(t, conflictIngress("team-a", "other", earlier, "foo.example.com"))...),
+	}, {
+		name: "host claimed by the domain claims",
+		claims: []config.DomainClaim{{
+			Domain:     "foo.example.com",
+			Namespaces: []string{"team-a"},
+		}},
+		objs: conflictObjects(t, ing),
+		want: []hostConflict{{Host: "foo.example.com"}},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listers := NewListers(tt.objs)
+			tr := &NullTracker{}
+			var enqueued []types.NamespacedName
+			r := &Reconciler{
+				virtualServiceLister:  listers.GetVirtualServiceLister(),
+				virtualServiceIndexer: virtualServiceHostIndexer(&listers),
+				ingressLister:         listers.GetIngressLister(),
+				tracker:               tr,
+				enqueueIngress: func(key types.NamespacedName) {
+					enqueued = append(enqueued, key)
+				},
+			}
+
+			got, err := r.findHostConflicts(probeContext(&config.Istio{DomainClaims: tt.claims}), ing)
+			if err != nil {
+				t.Fatal("findHostConflicts() =", err)
+			}
+			if diff := cmp.Diff(tt.want, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Error("Unexpected conflicts (-want, +got):", diff)
+			}
+
+			var tracked []string
+			for _, ref := range tr.References() {
+				tracked = append(tracked, ref.Namespace+"/"+ref.Name)
+			}
+			if diff := cmp.Diff(tt.wantTracked, tracked); diff != "" {
+				t.Error("Unexpected tracked VirtualServices (-want, +got):", diff)
+			}
+			if diff := cmp.Diff(tt.wantEnqueued, enqueued); diff != "" {
+				t.Error("Unexpected enqueued Ingresses (-want, +got):", diff)
+			}
+		})
+	}
+}
+
+func TestWithoutHosts(t *testing.T) {
+	ing := conflictIngress("team-b", "route", conflictTime, "foo.example.com", "bar.example.com")
+	ing.Spec.Rules = append(ing.Spec.Rules, v1alpha1.IngressRule{
+		Hosts:      []string{"baz.example.com"},
+		Visibility: v1alpha1.IngressVisibilityExternalIP,
+		HTTP:       ing.Spec.Rules[0].HTTP,
+	})
+	ing.Spec.TLS = []v1alpha1.IngressTLS{{
+		Hosts:      []string{"foo.example.com", "bar.example.com"},
+		SecretName: "foo-bar",
+	}, {
+		Hosts:      []string{"baz.example.com"},
+		SecretName: "baz",
+	}}
+
+	if got := withoutHosts(ing, nil); got != ing {
+		t.Error("withoutHosts() copied the Ingress without conflicts")
+	}
+
+	got := withoutHosts(ing, []hostConflict{{Host: "foo.example.com"}, {Host: "baz.example.com"}})
+	if diff := cmp.Diff([]v1alpha1.IngressRule{{
+		Hosts:      []string{"bar.example.com"},
+		Visibility: v1alpha1.IngressVisibilityExternalIP,
+		HTTP:       ing.Spec.Rules[0].HTTP,
+	}}, got.Spec.Rules); diff != "" {
+		t.Error("Unexpected rules (-want, +got):", diff)
+	}
+	if diff := cmp.Diff([]v1alpha1.IngressTLS{{
+		Hosts:      []string{"bar.example.com"},
+		SecretName: "foo-bar",
+	}}, got.Spec.TLS); diff != "" {
+		t.Error("Unexpected TLS (-want, +got):", diff)
+	}
+	if len(ing.Spec.Rules) != 2 || len(ing.Spec.Rules[0].Hosts) != 2 || len(ing.Spec.TLS[0].Hosts) != 2 {
+		t.Error("withoutHosts() modified the given Ingress")
+	}
+}
+
+func TestMarkHostConflicts(t *testing.T) {
+	ing := conflictIngress("team-b", "route", conflictTime, "foo.example.com", "bar.example.com")
+	ing.Status.InitializeConditions()
+	recorder := record.NewFakeRecorder(2)
+	ctx := controller.WithEventRecorder(context.Background(), recorder)
+
+	conflicts := []hostConflict{{
+		Host: "bar.example.com",
+	}, {
+		Host:  "foo.example.com",
+		Owner: types.NamespacedName{Namespace: "team-a", Name: "other"},
+	}}
+	markHostConflicts(ctx, ing, conflicts)
+	markHostConflicts(ctx, ing, conflicts)
+
+	want := "Hosts not routed: bar.example.com may not be claimed from namespace team-b; foo.example.com is claimed by Ingress team-a/other"
+	cond := ing.Status.GetCondition(v1alpha1.IngressConditionLoadBalancerReady)
+	if cond.Status != corev1.ConditionFalse || cond.Reason != hostConflictReason || cond.Message != want {
+		t.Errorf("LoadBalancerReady = %#v, want false with message %q", cond, want)
+	}
+
+	// The conflicts are only reported once.
+	close(recorder.Events)
+	var events []string
+	for event := range recorder.Events {
+		events = append(events, event)
+	}
+	if wantEvents := []string{"Warning HostConflict " + want}; !cmp.Equal(wantEvents, events) {
+		t.Errorf("Events = %q, want %q", events, wantEvents)
+	}
+}
+
+func TestClaimedBefore(t *testing.T) {
+	a := conflictIngress("team-a", "route", conflictTime)
+	b := conflictIngress("team-b", "route", conflictTime)
+	old := conflictIngress("team-z", "route", conflictTime.Add(-time.Second))
+
+	if !claimedBefore(a, b) || claimedBefore(b, a) {
+		t.Error("Ingresses created at the same time are not ordered by namespace")
+	}
+	if !claimedBefore(old, a) || claimedBefore(a, old) {
+		t.Error("Ingresses are not ordered by creation time")
+	}
+	if claimedBefore(a, a) {
+		t.Error("An Ingress claimed its hosts before itself")
+	}
+}
+
+func TestIndexVirtualServiceHosts(t *testing.T) {
+	ing := conflictIngress("team-a", "route", conflictTime, "foo.example.com")
+	vs := conflictVirtualServices(t, ing)[0].(*v1beta1.VirtualService)
+	if got, err := indexVirtualServiceHosts(vs); err != nil || !cmp.Equal(got, vs.Spec.Hosts) {
+		t.Errorf("indexVirtualServiceHosts() = %v, %v, want %v", got, err, vs.Spec.Hosts)
+	}
+
+	// The VirtualServices not generated for Ingresses cannot conflict with them.
+	vs = vs.DeepCopy()
+	delete(vs.Labels, networking.IngressLabelKey)
+	if got, err := indexVirtualServiceHosts(vs); err != nil || len(got) != 0 {
+		t.Errorf("indexVirtualServiceHosts() = %v, %v, want no hosts", got, err)
+	}
+}
@@ -0,0 +1,748 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"knative.dev/net-istio/pkg/reconciler/ingress/config"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+	nethttp "knative.dev/networking/pkg/http"
+	"knative.dev/networking/pkg/http/header"
+	"knative.dev/networking/pkg/ingress"
+	"knative.dev/networking/pkg/prober"
+	"knative.dev/networking/pkg/status"
+	"knative.dev/pkg/kmeta"
+)
+
+const (
+	// hostProbeConcurrency defines how many probe requests can be issued simultaneously.
+	hostProbeConcurrency = 15
+	// hostProbeTimeout defines the maximum amount of time a probe request can take.
+	hostProbeTimeout = 1 * time.Second
+	// hostProbeFailureThreshold is the number of consecutive failed probes after which a
+	// host is reported as failing. Probes are expected to fail for a little while until
+	// the gateways received the configuration of the Ingress.
+	hostProbeFailureThreshold = 5
+	// maxReportedHosts is the maximum number of failing hosts listed in the status of an
+	// Ingress.
+	maxReportedHosts = 3
+
+	// hostsNotReady is the reason of the LoadBalancerReady condition of Ingresses with
+	// failing hosts.
+	hostsNotReady = "HostsNotReady"
+	// partiallyProbed is the reason of the LoadBalancerReady condition of Ingresses found
+	// ready before all the gateway pods serve them.
+	partiallyProbed = "PartiallyProbed"
+)
+
+// hostProbeBackoff defines the delays between retries of failed probes.
+var hostProbeBackoff = wait.Backoff{
+	Duration: 200 * time.Millisecond,
+	Factor:   2,
+	Cap:      30 * time.Second,
+	Steps:    math.MaxInt32,
+}
+
+// hostFailure is a host of an Ingress which is not served by some gateway pods yet.
+type hostFailure struct {
+	// Host is the probed host.
+	Host string
+	// Pods is the number of gateway pods failing the probes of the host.
+	Pods int
+	// Err is the last error of the probes of the host.
+	Err string
+}
+
+// hostFailureReporter is implemented by the status managers tracking the hosts of
+// Ingresses which fail their probes.
+type hostFailureReporter interface {
+	// FailingHosts returns the hosts of the given Ingress failing their probes, sorted.
+	FailingHosts(ctx context.Context, ing *v1alpha1.Ingress) []hostFailure
+}
+
+// probeCoverage is how many gateway pods serve an Ingress.
+type probeCoverage struct {
+	// Ready is the number of gateway pods passing all the probes of the Ingress.
+	Ready int
+	// Required is the number of gateway pods required to pass the probes of the Ingress
+	// before it is marked ready.
+	Required int
+	// Total is the number of gateway pods probed for the Ingress.
+	Total int
+}
+
+// probeCoverageReporter is implemented by the status managers which may find Ingresses
+// ready before all the gateway pods serve them.
+type probeCoverageReporter interface {
+	// ProbeCoverage returns how many gateway pods serve the given Ingress, and false if
+	// its readiness does not depend on a sample or a quorum of the gateway pods.
+	ProbeCoverage(ctx context.Context, ing *v1alpha1.Ingress) (probeCoverage, bool)
+}
+
+// tlsCertificate is the certificate a gateway is expected to serve for some hosts.
+type tlsCertificate struct {
+	// Secret is the Secret holding the certificate, as namespace/name.
+	Secret string
+	// Fingerprint is the SHA-256 fingerprint of the leaf certificate.
+	Fingerprint [sha256.Size]byte
+}
+
+// newTLSCertificate returns the certificate held by the given TLS Secret.
+func newTLSCertificate(secret *corev1.Secret) (*tlsCertificate, error) {
+	block, _ := pem.Decode(secret.Data[corev1.TLSCertKey])
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("no PEM encoded certificate in key %q", corev1.TLSCertKey)
+	}
+	return &tlsCertificate{
+		Secret:      secret.Namespace + "/" + secret.Name,
+		Fingerprint: sha256.Sum256(block.Bytes),
+	}, nil
+}
+
+// verify checks that the certificate is the one served over the given connection.
+func (c *tlsCertificate) verify(cs tls.ConnectionState) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("no certificate served")
+	}
+	if sha256.Sum256(cs.PeerCertificates[0].Raw) != c.Fingerprint {
+		return fmt.Errorf("served certificate does not match Secret %s", c.Secret)
+	}
+	return nil
+}
+
+type certSecretsKey struct{}
+
+// withCertSecrets attaches the Secrets copied for the certificates of an Ingress to the
+// context, so that its HTTPS servers are verified against the certificates being
+// reconciled even before the informers caught up with the update of the copies.
+func withCertSecrets(ctx context.Context, secrets []*corev1.Secret) context.Context {
+	return context.WithValue(ctx, certSecretsKey{}, secrets)
+}
+
+// certSecretFromContext returns the Secret with the given namespace and name attached to
+// the context with withCertSecrets, or nil.
+func certSecretFromContext(ctx context.Context, namespace, name string) *corev1.Secret {
+	secrets, _ := ctx.Value(certSecretsKey{}).([]*corev1.Secret)
+	for _, secret := range secrets {
+		if secret.Namespace == namespace && secret.Name == name {
+			return secret
+		}
+	}
+	return nil
+}
+
+// certificateFingerprints returns the fingerprints of the certificates verified by the given
+// targets, sorted, to tell apart the probing states of different certificates.
+func certificateFingerprints(targets []tlsProbeTarget) string {
+	fingerprints := sets.New[string]()
+	for _, target := range targets {
+		if target.Certificate != nil {
+			fingerprints.Insert(hex.EncodeToString(target.Certificate.Fingerprint[:]))
+		}
+	}
+	return strings.Join(sets.List(fingerprints), ",")
+}
+
+// tlsProbeTarget is a target probing HTTPS servers along with the certificate they serve.
+type tlsProbeTarget struct {
+	status.ProbeTarget
+	// Certificate is the certificate expected to be served, nil if it is not verified.
+	Certificate *tlsCertificate
+}
+
+// hostTargetLister lists the targets probed by the host prober.
+type hostTargetLister interface {
+	status.ProbeTargetLister
+	// ListTLSProbeTargets returns the targets to probe the HTTPS servers of an Ingress.
+	ListTLSProbeTargets(ctx context.Context, ing *v1alpha1.Ingress) ([]tlsProbeTarget, error)
+}
+
+// hostProber checks if the gateway pods serve the probed hosts of Ingresses. It sends the
+// same probes as the prober of knative.dev/networking, but keeps track of the hosts which
+// keep failing them, so that they can be reported in the status of the Ingresses. The
+// HTTPS servers of Ingresses with TLS are probed as well, verifying that they serve the
+// certificate of their Secret, e.g. after it was rotated.
+//
+// Depending on the configuration, only a sample of the pods of each gateway is probed, or
+// only a quorum of them is required to pass the probes, before an Ingress is marked ready.
+// The other pods are probed in the background afterwards.
+type hostProber struct {
+	logger *zap.SugaredLogger
+
+	targetLister hostTargetLister
+	// callback is called once an Ingress is ready, whenever its failing hosts change, and
+	// once all the gateway pods serve an Ingress found ready before.
+	callback func(*v1alpha1.Ingress)
+
+	// probe sends a probe request for u to addr, and returns an error unless the gateway
+	// serves the version of the Ingress with the given hash, and the given certificate if
+	// not nil.
+	probe func(ctx context.Context, addr string, u *url.URL, hash string, certificate *tlsCertificate) error
+	// backoff defines the delays between retries of failed probes.
+	backoff wait.Backoff
+
+	// mu guards ingressStates
+	mu            sync.Mutex
+	ingressStates map[types.NamespacedName]*hostProbeState
+}
+
+// hostProbeState represents the probing state of an Ingress.
+type hostProbeState struct {
+	hash string
+	// certificates holds the fingerprints of the certificates verified by the probes, so
+	// that the Ingress is probed again once they are rotated.
+	certificates string
+	ready        bool
+	cancel       func()
+
+	// pods holds the cancellation of the probes of every pod IP.
+	pods map[string]func()
+	// failures holds the last error of the failing probes of every host, by pod address.
+	failures map[string]map[string]string
+
+	// targets holds the progress of the probes of every target.
+	targets []*targetProgress
+	// required is the number of gateway pods required to pass the probes, for reporting.
+	required int
+	// partial is true if the Ingress may be found ready before all the gateway pods
+	// pass its probes.
+	partial bool
+	// readyCh is closed once the Ingress is ready.
+	readyCh chan struct{}
+}
+
+// targetProgress represents the progress of the probes of a target.
+type targetProgress struct {
+	// sampled holds the pod IPs probed before the Ingress is marked ready.
+	sampled sets.Set[string]
+	// required is the number of sampled pods which must pass their probes.
+	required int
+	// pending holds the number of probes of every pod IP which did not pass yet.
+	pending map[string]int
+}
+
+// ready returns true if enough sampled pods passed their probes.
+func (t *targetProgress) ready() bool {
+	passed := 0
+	for ip := range t.sampled {
+		if t.pending[ip] == 0 {
+			passed++
+		}
+	}
+	return passed >= t.required
+}
+
+// allReady returns true if all the targets are ready.
+func (s *hostProbeState) allReady() bool {
+	for _, target := range s.targets {
+		if !target.ready() {
+			return false
+		}
+	}
+	return true
+}
+
+// coverage returns how many gateway pods pass all their probes. A pod belonging to several
+// targets must pass the probes of all of them.
+func (s *hostProbeState) coverage() probeCoverage {
+	pods := make(map[string]bool)
+	for _, target := range s.targets {
+		for ip, pending := range target.pending {
+			if passed, ok := pods[ip]; !ok || passed {
+				pods[ip] = pending == 0
+			}
+		}
+	}
+	ret := probeCoverage{Required: s.required, Total: len(pods)}
+	for _, passed := range pods {
+		if passed {
+			ret.Ready++
+		}
+	}
+	return ret
+}
+
+func newHostProber(
+	logger *zap.SugaredLogger,
+	targetLister hostTargetLister,
+	callback func(*v1alpha1.Ingress),
+) *hostProber {
+	return &hostProber{
+		logger:        logger,
+		targetLister:  targetLister,
+		callback:      callback,
+		probe:         httpProbe,
+		backoff:       hostProbeBackoff,
+		ingressStates: make(map[types.NamespacedName]*hostProbeState),
+	}
+}
+
+// IsReady checks if the gateway pods serve the probed hosts of the provided Ingress. If
+// the Ingress has not been probed yet, probing starts in the background and the callback
+// is called once the probes of the sampled pods, or a quorum of them, succeeded. The Ingress
+// is probed again when it changes, or when the certificates of its HTTPS servers change.
+func (p *hostProber) IsReady(ctx context.Context, ing *v1alpha1.Ingress) (bool, error) {
+	key := types.NamespacedName{Namespace: ing.Namespace, Name: ing.Name}
+
+	bytes, err := ingress.ComputeHash(ing)
+	if err != nil {
+		return false, fmt.Errorf("failed to compute the hash of the Ingress: %w", err)
+	}
+	hash := hex.EncodeToString(bytes[:])
+
+	targets, err := p.listTargets(ctx, ing)
+	if err != nil {
+		return false, err
+	}
+	certificates := certificateFingerprints(targets)
+
+	if ready, ok := func() (bool, bool) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if state, ok := p.ingressStates[key]; ok {
+			if state.hash == hash && state.certificates == certificates {
+				return state.ready, true
+			}
+			// Cancel the probing of the outdated version or certificates.
+			state.cancel()
+			delete(p.ingressStates, key)
+		}
+		return false, false
+	}(); ok {
+		return ready, nil
+	}
+
+	probeCtx, cancel := context.WithCancel(context.Background())
+	state := &hostProbeState{
+		hash:         hash,
+		certificates: certificates,
+		cancel:       cancel,
+		pods:         make(map[string]func()),
+		failures:     make(map[string]map[string]string),
+		readyCh:      make(chan struct{}),
+	}
+	istio := config.FromContext(ctx).Istio
+	podContexts := make(map[string]context.Context)
+	sampled := sets.New[string]()
+	for _, target := range targets {
+		progress := &targetProgress{
+			sampled: samplePods(key, target.PodIPs, istio.ProbePods),
+			pending: make(map[string]int, target.PodIPs.Len()),
+		}
+		if len(target.URLs) > 0 {
+			progress.required = quorumSize(progress.sampled.Len(), istio.ProbeQuorum)
+			sampled = sampled.Union(progress.sampled)
+		}
+		state.partial = state.partial || progress.sampled.Len() < target.PodIPs.Len() || progress.required < progress.sampled.Len()
+		state.targets = append(state.targets, progress)
+
+		for ip := range target.PodIPs {
+			progress.pending[ip] = len(target.URLs)
+			if _, ok := podContexts[ip]; !ok {
+				podContexts[ip], state.pods[ip] = context.WithCancel(probeCtx)
+			}
+		}
+	}
+	state.required = quorumSize(sampled.Len(), istio.ProbeQuorum)
+	ready := state.allReady()
+	state.ready = ready
+
+	p.mu.Lock()
+	p.ingressStates[key] = state
+	p.mu.Unlock()
+
+	if !ready {
+		go p.probeAll(probeCtx, podContexts, ing, state, targets)
+	}
+	return ready, nil
+}
+
+// samplePods returns the given number of pod IPs to probe for the Ingress with the given
+// key, or all of them if n is zero. The sample is deterministic, but differs between
+// Ingresses so that their probes spread over the gateway pods.
+func samplePods(key types.NamespacedName, ips sets.Set[string], n int) sets.Set[string] {
+	if n <= 0 || n >= ips.Len() {
+		return ips
+	}
+	rank := func(ip string) uint64 {
+		h := fnv.New64a()
+		h.Write([]byte(key.String() + "/" + ip))
+		return h.Sum64()
+	}
+	sorted := sets.List(ips)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return rank(sorted[i]) < rank(sorted[j])
+	})
+	return sets.New(sorted[:n]...)
+}
+
+// quorumSize returns the number of pods required out of the given number of probed pods,
+// for the given percentage. The zero percentage requires all of them.
+func quorumSize(pods, quorum int) int {
+	if quorum <= 0 || quorum >= 100 {
+		return pods
+	}
+	return (pods*quorum + 99) / 100
+}
+
+// listTargets returns the targets to probe for the given Ingress, including the HTTPS servers
+// of Ingresses with TLS.
+func (p *hostProber) listTargets(ctx context.Context, ing *v1alpha1.Ingress) ([]tlsProbeTarget, error) {
+	httpTargets, err := p.targetLister.ListProbeTargets(ctx, ing)
+	if err != nil {
+		return nil, err
+	}
+	targets := make([]tlsProbeTarget, 0, len(httpTargets))
+	for _, target := range httpTargets {
+		targets = append(targets, tlsProbeTarget{ProbeTarget: target})
+	}
+	if len(ing.Spec.TLS) == 0 {
+		return targets, nil
+	}
+
+	tlsTargets, err := p.targetLister.ListTLSProbeTargets(ctx, ing)
+	if err != nil {
+		return nil, err
+	}
+	return append(targets, tlsTargets...), nil
+}
+
+// FailingHosts implements hostFailureReporter.
+func (p *hostProber) FailingHosts(_ context.Context, ing *v1alpha1.Ingress) []hostFailure {
+	key := types.NamespacedName{Namespace: ing.Namespace, Name: ing.Name}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	state, ok := p.ingressStates[key]
+	if !ok {
+		return nil
+	}
+
+	ret := make([]hostFailure, 0, len(state.failures))
+	for host, pods := range state.failures {
+		// Report the error of the first pod for a stable message.
+		addrs := sets.List(sets.KeySet(pods))
+		ret = append(ret, hostFailure{Host: host, Pods: len(pods), Err: pods[addrs[0]]})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Host < ret[j].Host
+	})
+	return ret
+}
+
+// ProbeCoverage implements probeCoverageReporter.
+func (p *hostProber) ProbeCoverage(_ context.Context, ing *v1alpha1.Ingress) (probeCoverage, bool) {
+	key := types.NamespacedName{Namespace: ing.Namespace, Name: ing.Name}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	state, ok := p.ingressStates[key]
+	if !ok || !state.partial {
+		return probeCoverage{}, false
+	}
+	return state.coverage(), true
+}
+
+// CancelIngressProbing cancels probing of the provided Ingress.
+func (p *hostProber) CancelIngressProbing(obj interface{}) {
+	acc, err := kmeta.DeletionHandlingAccessor(obj)
+	if err != nil {
+		return
+	}
+
+	key := types.NamespacedName{Namespace: acc.GetNamespace(), Name: acc.GetName()}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if state, ok := p.ingressStates[key]; ok {
+		state.cancel()
+		delete(p.ingressStates, key)
+	}
+}
+
+// CancelPodProbing cancels probing of the provided Pod. Its probes are considered
+// successful, as a deleted gateway pod does not serve any traffic anymore.
+func (p *hostProber) CancelPodProbing(obj interface{}) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, state := range p.ingressStates {
+		if cancel, ok := state.pods[pod.Status.PodIP]; ok {
+			cancel()
+		}
+	}
+}
+
+// probeAll probes the sampled pods of the targets, calls the callback once enough of them
+// passed their probes, and then probes the other pods in the background.
+func (p *hostProber) probeAll(ctx context.Context, podContexts map[string]context.Context, ing *v1alpha1.Ingress, state *hostProbeState, targets []tlsProbeTarget) {
+	sem := make(chan struct{}, hostProbeConcurrency)
+	var wg sync.WaitGroup
+	probeTarget := func(i int, ips sets.Set[string]) {
+		target := targets[i]
+		for ip := range ips {
+			for _, u := range target.URLs {
+				podCtx := podContexts[ip]
+				addr := net.JoinHostPort(ip, target.PodPort)
+				certificate := target.Certificate
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					attempts := 0
+					// The error is only ever the cancellation of podCtx, in which case the
+					// pod went away and its probes are considered successful.
+					_ = wait.ExponentialBackoffWithContext(podCtx, p.backoff, func(ctx context.Context) (bool, error) {
+						select {
+						case sem <- struct{}{}:
+						case <-ctx.Done():
+							return false, ctx.Err()
+						}
+						defer func() { <-sem }()
+
+						if err := p.probe(ctx, addr, u, state.hash, certificate); err != nil {
+							p.logger.Errorf("Probing of %s failed, IP: %s: %v", u, addr, err)
+							if attempts++; attempts >= hostProbeFailureThreshold {
+								p.setFailure(ctx, ing, state, u.Hostname(), addr, err)
+							}
+							return false, nil
+						}
+						return true, nil
+					})
+					p.setFailure(ctx, ing, state, u.Hostname(), addr, nil)
+					p.setPassed(state, i, ip)
+				}()
+			}
+		}
+	}
+
+	for i := range targets {
+		probeTarget(i, state.targets[i].sampled)
+	}
+	select {
+	case <-state.readyCh:
+	case <-ctx.Done():
+	}
+	if ctx.Err() != nil {
+		return
+	}
+	p.callback(ing)
+
+	if !state.partial {
+		return
+	}
+	// Sweep the pods left out of the sample, and wait for the remaining probes to update
+	// the coverage in the status of the Ingress once all the pods serve it.
+	for i, target := range targets {
+		probeTarget(i, target.PodIPs.Difference(state.targets[i].sampled))
+	}
+	wg.Wait()
+	if ctx.Err() == nil {
+		p.callback(ing)
+	}
+}
+
+// setPassed records that a probe of the given target passed on the pod with the given IP,
+// and marks the Ingress ready once enough pods passed their probes.
+func (p *hostProber) setPassed(state *hostProbeState, target int, ip string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	state.targets[target].pending[ip]--
+	if !state.ready && state.allReady() {
+		state.ready = true
+		close(state.readyCh)
+	}
+}
+
+// setFailure records the error of the probes of host on the gateway pod with the given
+// address, or clears it if err is nil. The callback is called when the failing hosts of
+// the Ingress change, so that its status gets updated.
+func (p *hostProber) setFailure(ctx context.Context, ing *v1alpha1.Ingress, state *hostProbeState, host, addr string, err error) {
+	p.mu.Lock()
+	before := len(state.failures)
+	if err != nil {
+		if state.failures[host] == nil {
+			state.failures[host] = make(map[string]string)
+		}
+		state.failures[host][addr] = err.Error()
+	} else if pods, ok := state.failures[host]; ok {
+		delete(pods, addr)
+		if len(pods) == 0 {
+			delete(state.failures, host)
+		}
+	}
+	changed := len(state.failures) != before
+	p.mu.Unlock()
+
+	if changed && ctx.Err() == nil {
+		p.callback(ing)
+	}
+}
+
+// httpProbe sends a probe request for u to the gateway pod with the given address, and
+// verifies the response like the prober of knative.dev/networking. For HTTPS requests, the
+// served certificate is verified to be the given one, if any.
+func httpProbe(ctx context.Context, addr string, u *url.URL, hash string, certificate *tlsCertificate) error {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		//nolint:gosec
+		// We only want to know that the Gateway is configured, not that the configuration is valid.
+		// Therefore, the certificate is compared to the configured one instead of being validated.
+		InsecureSkipVerify: true,
+	}
+	if certificate != nil {
+		transport.TLSClientConfig.VerifyConnection = certificate.verify
+	}
+	// Disable keep-alives to prevent connection leak since a new transport is created per probe.
+	transport.DisableKeepAlives = true
+	transport.DialContext = func(ctx context.Context, network, _ string) (net.Conn, error) {
+		// The URL carries the probed host, so that it is used as SNI and Host header, while
+		// the connection goes to the gateway pod.
+		return (&net.Dialer{}).DialContext(ctx, network, addr)
+	}
+
+	probeURL := *u
+	probeURL.Path = path.Join(probeURL.Path, nethttp.HealthCheckPath)
+
+	ctx, cancel := context.WithTimeout(ctx, hostProbeTimeout)
+	defer cancel()
+	ok, err := prober.Do(
+		ctx,
+		transport,
+		probeURL.String(),
+		prober.WithHeader(header.UserAgentKey, header.IngressReadinessUserAgent),
+		prober.WithHeader(header.ProbeKey, header.ProbeValue),
+		prober.WithHeader(header.HashKey, header.HashValueOverride),
+		hashVerifier(hash))
+	if err == nil && !ok {
+		err = errors.New("probe failed")
+	}
+	return err
+}
+
+// hashVerifier verifies the response of a probe request like the prober of
+// knative.dev/networking: a 200 carrying the expected hash succeeds, a 404 or 503 fails
+// as the gateway does not serve the host yet, and any other response is assumed to
+// succeed since no information can be extracted from it.
+func hashVerifier(hash string) prober.Verifier {
+	return func(r *http.Response, _ []byte) (bool, error) {
+		switch r.StatusCode {
+		case http.StatusOK:
+			if got := r.Header.Get(header.HashKey); got != "" && got != hash {
+				return false, fmt.Errorf("unexpected hash: want %q, got %q", hash, got)
+			}
+			return true, nil
+		case http.StatusNotFound, http.StatusServiceUnavailable:
+			return false, fmt.Errorf("unexpected status code: want %v, got %v", http.StatusOK, r.StatusCode)
+		default:
+			return true, nil
+		}
+	}
+}
+
+// failingHostsMessage returns the message of the LoadBalancerReady condition of an Ingress
+// with the given failing hosts.
+func failingHostsMessage(failures []hostFailure) string {
+	var b strings.Builder
+	b.WriteString("Waiting for load balancer to be ready, failing hosts: ")
+	for i, failure := range failures {
+		if i == maxReportedHosts {
+			fmt.Fprintf(&b, " and %d more", len(failures)-i)
+			break
+		}
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		pods := "pods"
+		if failure.Pods == 1 {
+			pods = "pod"
+		}
+		fmt.Fprintf(&b, "%s (%d %s: %s)", failure.Host, failure.Pods, pods, failure.Err)
+	}
+	return b.String()
+}
+
+// coverageMessage returns the part of the message of the LoadBalancerReady condition of
+// a not ready Ingress stating how many gateway pods serve it.
+func coverageMessage(coverage probeCoverage) string {
+	return fmt.Sprintf("%d of %d gateway pods ready, %d required", coverage.Ready, coverage.Total, coverage.Required)
+}
+
+// markLoadBalancerNotReady marks the load balancer of the given Ingress as not ready,
+// listing the hosts failing their probes and how many gateway pods serve the Ingress if
+// the status manager tracks them.
+func (r *Reconciler) markLoadBalancerNotReady(ctx context.Context, ing *v1alpha1.Ingress) {
+	reason, message := "", ""
+	if reporter, ok := r.statusManager.(hostFailureReporter); ok {
+		if failures := reporter.FailingHosts(ctx, ing); len(failures) > 0 {
+			reason, message = hostsNotReady, failingHostsMessage(failures)
+		}
+	}
+	if reporter, ok := r.statusManager.(probeCoverageReporter); ok {
+		if coverage, ok := reporter.ProbeCoverage(ctx, ing); ok {
+			if reason == "" {
+				// The reason and message of Ingress.Status.MarkLoadBalancerNotReady.
+				reason, message = "Uninitialized", "Waiting for load balancer to be ready"
+			}
+			message += "; " + coverageMessage(coverage)
+		}
+	}
+	if reason == "" {
+		ing.Status.MarkLoadBalancerNotReady()
+		return
+	}
+	ing.GetConditionSet().Manage(&ing.Status).MarkUnknown(v1alpha1.IngressConditionLoadBalancerReady, reason, message)
+}
+
+// markLoadBalancerCoverage states in the LoadBalancerReady condition of the given ready
+// Ingress that only some of the gateway pods serve it, while the others are probed in the
+// background.
+func (r *Reconciler) markLoadBalancerCoverage(ctx context.Context, ing *v1alpha1.Ingress) {
+	reporter, ok := r.statusManager.(probeCoverageReporter)
+	if !ok {
+		return
+	}
+	if coverage, ok := reporter.ProbeCoverage(ctx, ing); ok && coverage.Ready < coverage.Total {
+		ing.GetConditionSet().Manage(&ing.Status).MarkTrueWithReason(v1alpha1.IngressConditionLoadBalancerReady,
+			partiallyProbed, "Ready on %d of %d gateway pods, the others are probed in the background",
+			coverage.Ready, coverage.Total)
+	}
+}
+
+// Make sure the lister used by the controller can list the targets of the host prober.
+var _ hostTargetLister = (*gatewayPodTargetLister)(nil)
@@ -0,0 +1,795 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/pem"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"knative.dev/net-istio/pkg/reconciler/ingress/config"
+	"knative.dev/net-istio/pkg/reconciler/ingress/resources"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+	"knative.dev/networking/pkg/http/header"
+	"knative.dev/networking/pkg/status"
+	fakestatusmanager "knative.dev/networking/pkg/testing/status"
+)
+
+type fakeProbeTargetLister struct {
+	targets    []status.ProbeTarget
+	tlsTargets []tlsProbeTarget
+	fails      bool
+}
+
+func (l *fakeProbeTargetLister) ListProbeTargets(context.Context, *v1alpha1.Ingress) ([]status.ProbeTarget, error) {
+	if l.fails {
+		return nil, errors.New("failed to list targets")
+	}
+	return l.targets, nil
+}
+
+func (l *fakeProbeTargetLister) ListTLSProbeTargets(context.Context, *v1alpha1.Ingress) ([]tlsProbeTarget, error) {
+	return l.tlsTargets, nil
+}
+
+func httpTarget(hosts ...string) status.ProbeTarget {
+	target := status.ProbeTarget{
+		PodIPs:  sets.New("1.1.1.1", "2.2.2.2"),
+		PodPort: "8080",
+		Port:    "80",
+	}
+	for _, host := range hosts {
+		target.URLs = append(target.URLs, &url.URL{Scheme: "http", Host: host + ":80"})
+	}
+	return target
+}
+
+func hostsIngress(hosts ...string) *v1alpha1.Ingress {
+	return &v1alpha1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "whatever"},
+		Spec: v1alpha1.IngressSpec{
+			Rules: []v1alpha1.IngressRule{{
+				Hosts:      hosts,
+				Visibility: v1alpha1.IngressVisibilityExternalIP,
+			}},
+		},
+	}
+}
+
+// probeContext returns a context carrying the given configuration.
+func probeContext(istio *config.Istio) context.Context {
+	return config.ToContext(context.Background(), &config.Config{Istio: istio})
+}
+
+// fastHostProbeBackoff retries the probes right away.
+var fastHostProbeBackoff = wait.Backoff{Duration: time.Millisecond, Factor: 1, Steps: hostProbeBackoff.Steps}
+
+func TestHostProberNoTargets(t *testing.T) {
+	prober := newHostProber(zaptest.NewLogger(t).Sugar(), &fakeProbeTargetLister{},
+		func(*v1alpha1.Ingress) { t.Error("Unexpected callback") })
+
+	ready, err := prober.IsReady(probeContext(&config.Istio{}), hostsIngress("foo.bar.com"))
+	if err != nil || !ready {
+		t.Errorf("IsReady() = %v, %v, want true, nil", ready, err)
+	}
+}
+
+func TestHostProberListError(t *testing.T) {
+	prober := newHostProber(zaptest.NewLogger(t).Sugar(), &fakeProbeTargetLister{fails: true},
+		func(*v1alpha1.Ingress) { t.Error("Unexpected callback") })
+
+	if _, err := prober.IsReady(probeContext(&config.Istio{}), hostsIngress("foo.bar.com")); err == nil {
+		t.Error("IsReady() = nil, wanted an error")
+	}
+}
+
+func TestHostProberFailingHosts(t *testing.T) {
+	callbacks := make(chan struct{}, 100)
+	prober := newHostProber(zaptest.NewLogger(t).Sugar(),
+		&fakeProbeTargetLister{targets: []status.ProbeTarget{httpTarget("foo.bar.com", "baz.bar.com")}},
+		func(*v1alpha1.Ingress) { callbacks <- struct{}{} })
+	prober.backoff = fastHostProbeBackoff
+
+	var mu sync.Mutex
+	served := false
+	probed := sets.New[string]()
+	prober.probe = func(_ context.Context, addr string, u *url.URL, _ string, _ *tlsCertificate) error {
+		mu.Lock()
+		defer mu.Unlock()
+		probed.Insert(addr + "/" + u.Hostname())
+		// baz.bar.com is not served by the second pod until told otherwise.
+		if u.Hostname() == "baz.bar.com" && addr == "2.2.2.2:8080" && !served {
+			return errors.New("unexpected status code: want 200, got 404")
+		}
+		return nil
+	}
+
+	ing := hostsIngress("foo.bar.com", "baz.bar.com")
+	if ready, err := prober.IsReady(probeContext(&config.Istio{}), ing); err != nil || ready {
+		t.Fatalf("IsReady() = %v, %v, want false, nil", ready, err)
+	}
+
+	// The callback is called once the host is reported as failing.
+	waitForCallback(t, callbacks)
+	want := []hostFailure{{Host: "baz.bar.com", Pods: 1, Err: "unexpected status code: want 200, got 404"}}
+	if got := prober.FailingHosts(context.Background(), ing); !cmp.Equal(got, want) {
+		t.Error("FailingHosts (-want, +got):", cmp.Diff(want, got))
+	}
+	if ready, err := prober.IsReady(probeContext(&config.Istio{}), ing); err != nil || ready {
+		t.Errorf("IsReady() = %v, %v, want false, nil", ready, err)
+	}
+
+	mu.Lock()
+	served = true
+	mu.Unlock()
+
+	// The callback is called once the host recovered, and once the Ingress is ready.
+	waitForCallback(t, callbacks)
+	waitForCallback(t, callbacks)
+	if got := prober.FailingHosts(context.Background(), ing); len(got) != 0 {
+		t.Errorf("FailingHosts = %v, want none", got)
+	}
+	if ready, err := prober.IsReady(probeContext(&config.Istio{}), ing); err != nil || !ready {
+		t.Errorf("IsReady() = %v, %v, want true, nil", ready, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	wantProbed := sets.New(
+		"1.1.1.1:8080/foo.bar.com", "1.1.1.1:8080/baz.bar.com",
+		"2.2.2.2:8080/foo.bar.com", "2.2.2.2:8080/baz.bar.com")
+	if !probed.Equal(wantProbed) {
+		t.Errorf("Probed %v, want %v", sets.List(probed), sets.List(wantProbed))
+	}
+}
+
+func TestHostProberCancelPodProbing(t *testing.T) {
+	callbacks := make(chan struct{}, 100)
+	prober := newHostProber(zaptest.NewLogger(t).Sugar(),
+		&fakeProbeTargetLister{targets: []status.ProbeTarget{httpTarget("foo.bar.com")}},
+		func(*v1alpha1.Ingress) { callbacks <- struct{}{} })
+	prober.backoff = fastHostProbeBackoff
+	prober.probe = func(_ context.Context, addr string, _ *url.URL, _ string, _ *tlsCertificate) error {
+		if addr == "2.2.2.2:8080" {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+
+	ing := hostsIngress("foo.bar.com")
+	if ready, err := prober.IsReady(probeContext(&config.Istio{}), ing); err != nil || ready {
+		t.Fatalf("IsReady() = %v, %v, want false, nil", ready, err)
+	}
+	waitForCallback(t, callbacks)
+	if got := prober.FailingHosts(context.Background(), ing); len(got) != 1 {
+		t.Fatalf("FailingHosts = %v, want foo.bar.com", got)
+	}
+
+	// The deleted pod is not probed anymore.
+	prober.CancelPodProbing(&corev1.Pod{Status: corev1.PodStatus{PodIP: "2.2.2.2"}})
+	waitForCallback(t, callbacks)
+	waitForCallback(t, callbacks)
+	if ready, err := prober.IsReady(probeContext(&config.Istio{}), ing); err != nil || !ready {
+		t.Errorf("IsReady() = %v, %v, want true, nil", ready, err)
+	}
+}
+
+func TestHostProberCancellation(t *testing.T) {
+	// The cancelled probes may still log after the test completed.
+	prober := newHostProber(zap.NewNop().Sugar(),
+		&fakeProbeTargetLister{targets: []status.ProbeTarget{httpTarget("foo.bar.com")}},
+		func(*v1alpha1.Ingress) {})
+	probes := make(chan struct{}, 100)
+	prober.probe = func(context.Context, string, *url.URL, string, *tlsCertificate) error {
+		select {
+		case probes <- struct{}{}:
+		default:
+		}
+		return errors.New("no route to host")
+	}
+
+	ing := hostsIngress("foo.bar.com")
+	if ready, err := prober.IsReady(probeContext(&config.Istio{}), ing); err != nil || ready {
+		t.Fatalf("IsReady() = %v, %v, want false, nil", ready, err)
+	}
+	<-probes
+
+	// A new version of the Ingress restarts probing.
+	updated := hostsIngress("baz.bar.com")
+	if ready, err := prober.IsReady(probeContext(&config.Istio{}), updated); err != nil || ready {
+		t.Fatalf("IsReady() = %v, %v, want false, nil", ready, err)
+	}
+
+	prober.CancelIngressProbing(updated)
+	prober.mu.Lock()
+	defer prober.mu.Unlock()
+	if len(prober.ingressStates) != 0 {
+		t.Errorf("ingressStates = %v, want empty", prober.ingressStates)
+	}
+}
+
+func TestHostProberIPv6(t *testing.T) {
+	callbacks := make(chan struct{}, 100)
+	target := status.ProbeTarget{
+		PodIPs:  sets.New("2001:db8::1"),
+		PodPort: "8080",
+		Port:    "80",
+		URLs:    []*url.URL{{Scheme: "http", Host: "foo.bar.com:80"}},
+	}
+	prober := newHostProber(zaptest.NewLogger(t).Sugar(),
+		&fakeProbeTargetLister{targets: []status.ProbeTarget{target}},
+		func(*v1alpha1.Ingress) { callbacks <- struct{}{} })
+	probed := make(chan string, 1)
+	prober.probe = func(_ context.Context, addr string, _ *url.URL, _ string, _ *tlsCertificate) error {
+		probed <- addr
+		return nil
+	}
+
+	if _, err := prober.IsReady(probeContext(&config.Istio{}), hostsIngress("foo.bar.com")); err != nil {
+		t.Fatal("IsReady() =", err)
+	}
+	waitForCallback(t, callbacks)
+	if got, want := <-probed, "[2001:db8::1]:8080"; got != want {
+		t.Errorf("Probed address = %q, want %q", got, want)
+	}
+}
+
+func TestHostProberSample(t *testing.T) {
+	callbacks := make(chan struct{}, 100)
+	target := status.ProbeTarget{
+		PodIPs:  sets.New("1.1.1.1", "2.2.2.2", "3.3.3.3", "4.4.4.4"),
+		PodPort: "8080",
+		Port:    "80",
+		URLs:    []*url.URL{{Scheme: "http", Host: "foo.bar.com:80"}},
+	}
+	prober := newHostProber(zaptest.NewLogger(t).Sugar(),
+		&fakeProbeTargetLister{targets: []status.ProbeTarget{target}},
+		func(*v1alpha1.Ingress) { callbacks <- struct{}{} })
+	prober.backoff = fastHostProbeBackoff
+
+	ing := hostsIngress("foo.bar.com")
+	sampled := samplePods(types.NamespacedName{Namespace: ing.Namespace, Name: ing.Name}, target.PodIPs, 2)
+
+	// The pods left out of the sample do not serve the Ingress until told otherwise.
+	var mu sync.Mutex
+	swept := false
+	prober.probe = func(_ context.Context, addr string, _ *url.URL, _ string, _ *tlsCertificate) error {
+		mu.Lock()
+		defer mu.Unlock()
+		ip, _, _ := net.SplitHostPort(addr)
+		if !sampled.Has(ip) && !swept {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+
+	ctx := probeContext(&config.Istio{ProbePods: 2})
+	if ready, err := prober.IsReady(ctx, ing); err != nil || ready {
+		t.Fatalf("IsReady() = %v, %v, want false, nil", ready, err)
+	}
+	waitForCallback(t, callbacks)
+	if ready, err := prober.IsReady(ctx, ing); err != nil || !ready {
+		t.Errorf("IsReady() = %v, %v, want true, nil", ready, err)
+	}
+	want := probeCoverage{Ready: 2, Required: 2, Total: 4}
+	if got, ok := prober.ProbeCoverage(ctx, ing); !ok || got != want {
+		t.Errorf("ProbeCoverage() = %v, %t, want %v, true", got, ok, want)
+	}
+
+	mu.Lock()
+	swept = true
+	mu.Unlock()
+
+	// The callback is called again once the background sweep completed, possibly after
+	// the failing hosts were reported.
+	want = probeCoverage{Ready: 4, Required: 2, Total: 4}
+	if err := wait.PollUntilContextTimeout(context.Background(), 10*time.Millisecond, 5*time.Second, true, func(context.Context) (bool, error) {
+		got, _ := prober.ProbeCoverage(ctx, ing)
+		return got == want, nil
+	}); err != nil {
+		got, _ := prober.ProbeCoverage(ctx, ing)
+		t.Errorf("ProbeCoverage() = %v, want %v", got, want)
+	}
+}
+
+func TestHostProberQuorum(t *testing.T) {
+	callbacks := make(chan struct{}, 100)
+	target := status.ProbeTarget{
+		PodIPs:  sets.New("1.1.1.1", "2.2.2.2", "3.3.3.3", "4.4.4.4"),
+		PodPort: "8080",
+		Port:    "80",
+		URLs:    []*url.URL{{Scheme: "http", Host: "foo.bar.com:80"}},
+	}
+	// The cancelled probes may still log after the test completed.
+	prober := newHostProber(zap.NewNop().Sugar(),
+		&fakeProbeTargetLister{targets: []status.ProbeTarget{target}},
+		func(*v1alpha1.Ingress) { callbacks <- struct{}{} })
+	prober.backoff = fastHostProbeBackoff
+	prober.probe = func(ctx context.Context, addr string, _ *url.URL, _ string, _ *tlsCertificate) error {
+		// The last pod does not answer until the probing is cancelled.
+		if addr == "4.4.4.4:8080" {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return nil
+	}
+
+	ing := hostsIngress("foo.bar.com")
+	ctx := probeContext(&config.Istio{ProbeQuorum: 75})
+	if ready, err := prober.IsReady(ctx, ing); err != nil || ready {
+		t.Fatalf("IsReady() = %v, %v, want false, nil", ready, err)
+	}
+	waitForCallback(t, callbacks)
+	if ready, err := prober.IsReady(ctx, ing); err != nil || !ready {
+		t.Errorf("IsReady() = %v, %v, want true, nil", ready, err)
+	}
+	want := probeCoverage{Ready: 3, Required: 3, Total: 4}
+	if got, ok := prober.ProbeCoverage(ctx, ing); !ok || got != want {
+		t.Errorf("ProbeCoverage() = %v, %t, want %v, true", got, ok, want)
+	}
+	prober.CancelIngressProbing(ing)
+}
+
+func TestHostProberFullCoverage(t *testing.T) {
+	prober := newHostProber(zaptest.NewLogger(t).Sugar(),
+		&fakeProbeTargetLister{targets: []status.ProbeTarget{httpTarget("foo.bar.com")}},
+		func(*v1alpha1.Ingress) {})
+	prober.probe = func(context.Context, string, *url.URL, string, *tlsCertificate) error {
+		return errors.New("connection refused")
+	}
+
+	// Probing a sample larger than the gateway, with a full quorum, is not partial.
+	ing := hostsIngress("foo.bar.com")
+	ctx := probeContext(&config.Istio{ProbePods: 5, ProbeQuorum: 100})
+	if _, err := prober.IsReady(ctx, ing); err != nil {
+		t.Fatal("IsReady() =", err)
+	}
+	if got, ok := prober.ProbeCoverage(ctx, ing); ok {
+		t.Errorf("ProbeCoverage() = %v, want none", got)
+	}
+	prober.CancelIngressProbing(ing)
+}
+
+func TestSamplePods(t *testing.T) {
+	ips := sets.New("1.1.1.1", "2.2.2.2", "3.3.3.3", "4.4.4.4", "5.5.5.5", "6.6.6.6")
+	foo := types.NamespacedName{Namespace: "default", Name: "foo"}
+
+	if got := samplePods(foo, ips, 0); !got.Equal(ips) {
+		t.Errorf("samplePods(0) = %v, want all", sets.List(got))
+	}
+	if got := samplePods(foo, ips, 10); !got.Equal(ips) {
+		t.Errorf("samplePods(10) = %v, want all", sets.List(got))
+	}
+
+	got := samplePods(foo, ips, 3)
+	if got.Len() != 3 || !ips.IsSuperset(got) {
+		t.Fatalf("samplePods(3) = %v, want 3 of %v", sets.List(got), sets.List(ips))
+	}
+	if again := samplePods(foo, sets.New(sets.List(ips)...), 3); !again.Equal(got) {
+		t.Errorf("samplePods(3) = %v, then %v, want a stable sample", sets.List(got), sets.List(again))
+	}
+
+	// The samples of different Ingresses spread over the pods.
+	sampled := sets.New[string]()
+	for _, name := range []string{"foo", "bar", "baz", "qux", "quux"} {
+		sampled = sampled.Union(samplePods(types.NamespacedName{Namespace: "default", Name: name}, ips, 3))
+	}
+	if sampled.Len() == 3 {
+		t.Errorf("Samples of all the Ingresses = %v, want them to differ", sets.List(sampled))
+	}
+}
+
+func TestQuorumSize(t *testing.T) {
+	tests := []struct {
+		pods, quorum, want int
+	}{
+		{pods: 10, quorum: 0, want: 10},
+		{pods: 10, quorum: 100, want: 10},
+		{pods: 10, quorum: 50, want: 5},
+		{pods: 10, quorum: 51, want: 6},
+		{pods: 3, quorum: 1, want: 1},
+		{pods: 0, quorum: 50, want: 0},
+	}
+	for _, test := range tests {
+		if got := quorumSize(test.pods, test.quorum); got != test.want {
+			t.Errorf("quorumSize(%d, %d) = %d, want %d", test.pods, test.quorum, got, test.want)
+		}
+	}
+}
+
+func waitForCallback(t *testing.T, callbacks chan struct{}) {
+	t.Helper()
+	select {
+	case <-callbacks:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the callback")
+	}
+}
+
+func TestHTTPProbe(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		hash    string
+		wantErr bool
+	}{{
+		name:   "expected hash",
+		status: http.StatusOK,
+		hash:   "hash",
+	}, {
+		name:    "other hash",
+		status:  http.StatusOK,
+		hash:    "outdated",
+		wantErr: true,
+	}, {
+		name:   "no hash",
+		status: http.StatusOK,
+	}, {
+		name:    "not found",
+		status:  http.StatusNotFound,
+		wantErr: true,
+	}, {
+		name:    "unavailable",
+		status:  http.StatusServiceUnavailable,
+		wantErr: true,
+	}, {
+		name:   "redirect",
+		status: http.StatusFound,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Host != "foo.bar.com" {
+					t.Errorf("Host = %q, want foo.bar.com", r.Host)
+				}
+				if got := r.Header.Get(header.ProbeKey); got != header.ProbeValue {
+					t.Errorf("%s header = %q, want %q", header.ProbeKey, got, header.ProbeValue)
+				}
+				if test.hash != "" {
+					w.Header().Set(header.HashKey, test.hash)
+				}
+				w.WriteHeader(test.status)
+			}))
+			defer server.Close()
+
+			addr := strings.TrimPrefix(server.URL, "http://")
+			err := httpProbe(context.Background(), addr, &url.URL{Scheme: "http", Host: "foo.bar.com"}, "hash", nil)
+			if (err != nil) != test.wantErr {
+				t.Errorf("httpProbe() = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestHostProberTLSTargets(t *testing.T) {
+	certificate := &tlsCertificate{Secret: "istio-system/secret"}
+	tlsTarget := tlsProbeTarget{
+		ProbeTarget: status.ProbeTarget{
+			PodIPs:  sets.New("1.1.1.1"),
+			PodPort: "8443",
+			Port:    "443",
+			URLs:    []*url.URL{{Scheme: "https", Host: "foo.bar.com:443"}},
+		},
+		Certificate: certificate,
+	}
+	lister := &fakeProbeTargetLister{
+		targets:    []status.ProbeTarget{httpTarget("foo.bar.com")},
+		tlsTargets: []tlsProbeTarget{tlsTarget},
+	}
+
+	tests := []struct {
+		name string
+		tls  []v1alpha1.IngressTLS
+		want []string
+	}{{
+		name: "without TLS",
+		want: []string{"http://foo.bar.com:80", "http://foo.bar.com:80"},
+	}, {
+		name: "with TLS",
+		tls:  []v1alpha1.IngressTLS{{Hosts: []string{"foo.bar.com"}, SecretName: "secret"}},
+		want: []string{"http://foo.bar.com:80", "http://foo.bar.com:80", "https://foo.bar.com:443"},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			readyCh := make(chan struct{}, 1)
+			prober := newHostProber(zaptest.NewLogger(t).Sugar(), lister,
+				func(*v1alpha1.Ingress) { readyCh <- struct{}{} })
+
+			var mu sync.Mutex
+			var probed []string
+			prober.probe = func(_ context.Context, _ string, u *url.URL, _ string, cert *tlsCertificate) error {
+				mu.Lock()
+				defer mu.Unlock()
+				probed = append(probed, u.String())
+				if want := u.Scheme == "https"; (cert == certificate) != want {
+					t.Errorf("Probe of %s verifies certificate %v", u, cert)
+				}
+				return nil
+			}
+
+			ing := hostsIngress("foo.bar.com")
+			ing.Spec.TLS = test.tls
+			if _, err := prober.IsReady(probeContext(&config.Istio{}), ing); err != nil {
+				t.Fatal("IsReady() =", err)
+			}
+			waitForCallback(t, readyCh)
+
+			mu.Lock()
+			defer mu.Unlock()
+			sort.Strings(probed)
+			if !cmp.Equal(probed, test.want) {
+				t.Error("Probed URLs (-want, +got):", cmp.Diff(test.want, probed))
+			}
+		})
+	}
+}
+
+func TestHostProberCertificateRotation(t *testing.T) {
+	tlsTarget := func(certificate *tlsCertificate) []tlsProbeTarget {
+		return []tlsProbeTarget{{
+			ProbeTarget: status.ProbeTarget{
+				PodIPs:  sets.New("1.1.1.1"),
+				PodPort: "8443",
+				Port:    "443",
+				URLs:    []*url.URL{{Scheme: "https", Host: "foo.bar.com:443"}},
+			},
+			Certificate: certificate,
+		}}
+	}
+	original := &tlsCertificate{Secret: "istio-system/secret", Fingerprint: [sha256.Size]byte{1}}
+	rotated := &tlsCertificate{Secret: "istio-system/secret", Fingerprint: [sha256.Size]byte{2}}
+	lister := &fakeProbeTargetLister{tlsTargets: tlsTarget(original)}
+
+	readyCh := make(chan struct{}, 1)
+	prober := newHostProber(zaptest.NewLogger(t).Sugar(), lister,
+		func(*v1alpha1.Ingress) { readyCh <- struct{}{} })
+	prober.backoff = fastHostProbeBackoff
+	var mu sync.Mutex
+	var verified []*tlsCertificate
+	prober.probe = func(_ context.Context, _ string, _ *url.URL, _ string, cert *tlsCertificate) error {
+		mu.Lock()
+		defer mu.Unlock()
+		verified = append(verified, cert)
+		return nil
+	}
+
+	ing := hostsIngress("foo.bar.com")
+	ing.Spec.TLS = []v1alpha1.IngressTLS{{Hosts: []string{"foo.bar.com"}, SecretName: "secret"}}
+	ctx := probeContext(&config.Istio{})
+	if ready, err := prober.IsReady(ctx, ing); err != nil || ready {
+		t.Fatalf("IsReady() = %v, %v, want false, nil", ready, err)
+	}
+	waitForCallback(t, readyCh)
+	if ready, err := prober.IsReady(ctx, ing); err != nil || !ready {
+		t.Fatalf("IsReady() = %v, %v, want true, nil", ready, err)
+	}
+
+	// The same Ingress is probed again once its certificate is rotated.
+	lister.tlsTargets = tlsTarget(rotated)
+	if ready, err := prober.IsReady(ctx, ing); err != nil || ready {
+		t.Fatalf("IsReady() after the rotation = %v, %v, want false, nil", ready, err)
+	}
+	waitForCallback(t, readyCh)
+	if ready, err := prober.IsReady(ctx, ing); err != nil || !ready {
+		t.Fatalf("IsReady() after the rotation = %v, %v, want true, nil", ready, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if want := []*tlsCertificate{original, rotated}; !cmp.Equal(verified, want) {
+		t.Error("Verified certificates (-want, +got):", cmp.Diff(want, verified))
+	}
+}
+
+func TestHTTPProbeCertificate(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(header.HashKey, "hash")
+	}))
+	defer server.Close()
+	addr := strings.TrimPrefix(server.URL, "https://")
+	u := &url.URL{Scheme: "https", Host: "foo.bar.com"}
+
+	served := &tlsCertificate{
+		Secret:      "istio-system/served",
+		Fingerprint: sha256.Sum256(server.Certificate().Raw),
+	}
+	if err := httpProbe(context.Background(), addr, u, "hash", served); err != nil {
+		t.Error("httpProbe() =", err)
+	}
+
+	rotated := &tlsCertificate{Secret: "istio-system/rotated"}
+	err := httpProbe(context.Background(), addr, u, "hash", rotated)
+	if err == nil || !strings.Contains(err.Error(), "served certificate does not match Secret istio-system/rotated") {
+		t.Errorf("httpProbe() = %v, wanted a certificate mismatch", err)
+	}
+}
+
+func TestNewTLSCertificate(t *testing.T) {
+	secret, err := resources.GenerateCertificate([]string{"foo.bar.com"}, "secret", "istio-system")
+	if err != nil {
+		t.Fatal("GenerateCertificate() =", err)
+	}
+	certificate, err := newTLSCertificate(secret)
+	if err != nil {
+		t.Fatal("newTLSCertificate() =", err)
+	}
+	block, _ := pem.Decode(secret.Data[corev1.TLSCertKey])
+	want := &tlsCertificate{Secret: "istio-system/secret", Fingerprint: sha256.Sum256(block.Bytes)}
+	if !cmp.Equal(certificate, want) {
+		t.Error("newTLSCertificate (-want, +got):", cmp.Diff(want, certificate))
+	}
+
+	if _, err := newTLSCertificate(&corev1.Secret{}); err == nil {
+		t.Error("newTLSCertificate() = nil, wanted an error for a Secret without certificate")
+	}
+}
+
+func TestFailingHostsMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures []hostFailure
+		want     string
+	}{{
+		name:     "single host",
+		failures: []hostFailure{{Host: "foo.bar.com", Pods: 1, Err: "connection refused"}},
+		want:     "Waiting for load balancer to be ready, failing hosts: foo.bar.com (1 pod: connection refused)",
+	}, {
+		name: "too many hosts",
+		failures: []hostFailure{
+			{Host: "a.bar.com", Pods: 2, Err: "404"},
+			{Host: "b.bar.com", Pods: 1, Err: "404"},
+			{Host: "c.bar.com", Pods: 1, Err: "404"},
+			{Host: "d.bar.com", Pods: 1, Err: "404"},
+			{Host: "e.bar.com", Pods: 1, Err: "404"},
+		},
+		want: "Waiting for load balancer to be ready, failing hosts: a.bar.com (2 pods: 404), " +
+			"b.bar.com (1 pod: 404), c.bar.com (1 pod: 404) and 2 more",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := failingHostsMessage(test.failures); got != test.want {
+				t.Errorf("failingHostsMessage() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+type fakeHostStatusManager struct {
+	fakestatusmanager.FakeStatusManager
+	failures []hostFailure
+	coverage probeCoverage
+}
+
+func (m *fakeHostStatusManager) FailingHosts(context.Context, *v1alpha1.Ingress) []hostFailure {
+	return m.failures
+}
+
+func (m *fakeHostStatusManager) ProbeCoverage(context.Context, *v1alpha1.Ingress) (probeCoverage, bool) {
+	return m.coverage, m.coverage != probeCoverage{}
+}
+
+func TestIngressStatusManagerProbeHosts(t *testing.T) {
+	failures := []hostFailure{{Host: "foo.bar.com", Pods: 1, Err: "404"}}
+	manager := &ingressStatusManager{
+		http: &fakestatusmanager.FakeStatusManager{
+			FakeIsReady: func(context.Context, *v1alpha1.Ingress) (bool, error) { return false, nil },
+		},
+		hosts: &fakeHostStatusManager{
+			FakeStatusManager: fakestatusmanager.FakeStatusManager{
+				FakeIsReady: func(context.Context, *v1alpha1.Ingress) (bool, error) { return true, nil },
+			},
+			failures: failures,
+		},
+	}
+	ing := hostsIngress("foo.bar.com")
+
+	single := config.ToContext(context.Background(), &config.Config{Istio: &config.Istio{}})
+	if ready, _ := manager.IsReady(single, ing); ready {
+		t.Error("Ingress was not checked by the HTTP prober")
+	}
+	if got := manager.FailingHosts(single, ing); len(got) != 0 {
+		t.Errorf("FailingHosts = %v, want none", got)
+	}
+
+	all := config.ToContext(context.Background(), &config.Config{Istio: &config.Istio{ProbeHosts: config.ProbeAllHosts}})
+	if ready, _ := manager.IsReady(all, ing); !ready {
+		t.Error("Ingress was not checked by the host prober")
+	}
+	if got := manager.FailingHosts(all, ing); !cmp.Equal(got, failures) {
+		t.Error("FailingHosts (-want, +got):", cmp.Diff(failures, got))
+	}
+
+	for _, istio := range []*config.Istio{{ProbePods: 3}, {ProbeQuorum: 50}} {
+		if ready, _ := manager.IsReady(probeContext(istio), ing); !ready {
+			t.Errorf("Ingress was not checked by the host prober with %+v", istio)
+		}
+	}
+	if ready, _ := manager.IsReady(probeContext(&config.Istio{ProbeQuorum: 100}), ing); ready {
+		t.Error("Ingress was not checked by the HTTP prober with a full quorum")
+	}
+}
+
+func TestMarkLoadBalancerNotReady(t *testing.T) {
+	ing := hostsIngress("foo.bar.com")
+	ing.Status.InitializeConditions()
+	r := &Reconciler{statusManager: &fakeHostStatusManager{
+		failures: []hostFailure{{Host: "foo.bar.com", Pods: 1, Err: "connection refused"}},
+	}}
+	r.markLoadBalancerNotReady(context.Background(), ing)
+
+	cond := ing.Status.GetCondition(v1alpha1.IngressConditionLoadBalancerReady)
+	if cond.Status != corev1.ConditionUnknown || cond.Reason != hostsNotReady ||
+		!strings.Contains(cond.Message, "foo.bar.com (1 pod: connection refused)") {
+		t.Errorf("LoadBalancerReady = %#v, want unknown with the failing hosts", cond)
+	}
+
+	r.statusManager = &fakeHostStatusManager{}
+	r.markLoadBalancerNotReady(context.Background(), ing)
+	if cond := ing.Status.GetCondition(v1alpha1.IngressConditionLoadBalancerReady); cond.Reason != "Uninitialized" {
+		t.Errorf("LoadBalancerReady reason = %q, want Uninitialized", cond.Reason)
+	}
+
+	r.statusManager = &fakeHostStatusManager{coverage: probeCoverage{Ready: 1, Required: 3, Total: 10}}
+	r.markLoadBalancerNotReady(context.Background(), ing)
+	cond = ing.Status.GetCondition(v1alpha1.IngressConditionLoadBalancerReady)
+	if want := "Waiting for load balancer to be ready; 1 of 10 gateway pods ready, 3 required"; cond.Reason != "Uninitialized" || cond.Message != want {
+		t.Errorf("LoadBalancerReady = %#v, want message %q", cond, want)
+	}
+}
+
+func TestMarkLoadBalancerCoverage(t *testing.T) {
+	ing := hostsIngress("foo.bar.com")
+	ing.Status.InitializeConditions()
+	ing.Status.MarkLoadBalancerReady(nil, nil)
+	r := &Reconciler{statusManager: &fakeHostStatusManager{coverage: probeCoverage{Ready: 3, Required: 3, Total: 10}}}
+	r.markLoadBalancerCoverage(context.Background(), ing)
+
+	cond := ing.Status.GetCondition(v1alpha1.IngressConditionLoadBalancerReady)
+	if want := "Ready on 3 of 10 gateway pods, the others are probed in the background"; cond.Status != corev1.ConditionTrue ||
+		cond.Reason != partiallyProbed || cond.Message != want {
+		t.Errorf("LoadBalancerReady = %#v, want true with message %q", cond, want)
+	}
+
+	// Once all the pods serve the Ingress, the condition is left alone.
+	ing.Status.MarkLoadBalancerReady(nil, nil)
+	r.statusManager = &fakeHostStatusManager{coverage: probeCoverage{Ready: 10, Required: 3, Total: 10}}
+	r.markLoadBalancerCoverage(context.Background(), ing)
+	if cond := ing.Status.GetCondition(v1alpha1.IngressConditionLoadBalancerReady); cond.Reason != "" {
+		t.Errorf("LoadBalancerReady reason = %q, want none", cond.Reason)
+	}
+}
@@ -17,11 +17,15 @@ limitations under the License.
 package ingress
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"slices"
 	"sort"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/testing/protocmp"
 	istiov1beta1 "istio.io/api/networking/v1beta1"
@@ -39,6 +43,7 @@ import (
 	"knative.dev/networking/pkg/apis/networking"
 	"knative.dev/networking/pkg/apis/networking/v1alpha1"
 	ingressreconciler "knative.dev/networking/pkg/client/injection/reconciler/networking/v1alpha1/ingress"
+	networkinglisters "knative.dev/networking/pkg/client/listers/networking/v1alpha1"
 	netconfig "knative.dev/networking/pkg/config"
 	"knative.dev/networking/pkg/status"
 	"knative.dev/pkg/controller"
@@ -48,16 +53,25 @@ import (
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes"
 	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
 )
 
 const (
 	virtualServiceNotReconciled = "ReconcileVirtualServiceFailed"
 	notReconciledReason         = "ReconcileIngressFailed"
 	notReconciledMessage        = "Ingress reconciliation failed"
+
+	// http3PortNotExposedReason is the reason of the LoadBalancerReady condition of Ingresses
+	// advertising HTTP/3 on gateways whose Service does not expose the HTTP/3 port.
+	http3PortNotExposedReason = "HTTP3PortNotExposed"
+	// http3RecheckDelay is the delay after which an Ingress advertising HTTP/3 on gateways
+	// not exposing the HTTP/3 port is reconciled again, as the gateway Services are not watched.
+	http3RecheckDelay = 30 * time.Second
 )
 
 // Reconciler implements the control loop for the Ingress resources.
@@ -69,10 +83,23 @@ type Reconciler struct {
 	gatewayLister        istiolisters.GatewayLister
 	secretLister         corev1listers.SecretLister
 	svcLister            corev1listers.ServiceLister
+	namespaceLister      corev1listers.NamespaceLister
+	ingressLister        networkinglisters.IngressLister
+
+	// virtualServiceIndexer indexes the VirtualServices by host, see virtualServiceHostIndexers.
+	virtualServiceIndexer cache.Indexer
 
 	tracker tracker.Interface
 
+	// enqueueIngress enqueues the Ingresses that have to release hosts claimed by another one.
+	enqueueIngress func(types.NamespacedName)
+
 	statusManager status.Manager
+
+	configWarnings configWarnings
+
+	metrics *metrics
+	tracer  trace.Tracer
 }
 
 var (
@@ -88,7 +115,14 @@ var (
 func (r *Reconciler) ReconcileKind(ctx context.Context, ingress *v1alpha1.Ingress) pkgreconciler.Event {
 	logger := logging.FromContext(ctx)
 
-	reconcileErr := r.reconcileIngress(ctx, ingress)
+	r.configWarnings.report(ctx)
+
+	ctx, span := r.startSpan(ctx, spanReconcile, ingress)
+	defer span.End()
+	reconcileErr := recordSpanError(span, r.reconcileIngress(ctx, ingress))
+	if ok, _ := controller.IsRequeueKey(reconcileErr); ok {
+		return reconcileErr
+	}
 	if reconcileErr != nil {
 		logger.Errorw("Failed to reconcile Ingress: ", zap.Error(reconcileErr))
 		ingress.Status.MarkIngressNotReady(notReconciledReason, notReconciledMessage)
@@ -116,6 +150,11 @@ func (r *Reconciler) reconcileIngress(ctx context.Context, ing *v1alpha1.Ingress
 		return r.reconcileMeshOnlyIngress(ctx, ing)
 	}
 
+	ctx, err := r.withNamespaceLabels(ctx, ing)
+	if err != nil {
+		return err
+	}
+
 	defaultGateways, err := resources.GatewaysFromContext(ctx, ing)
 	if err != nil {
 		return err
@@ -130,35 +169,61 @@ func (r *Reconciler) reconcileIngress(ctx context.Context, ing *v1alpha1.Ingress
 		gatewayNames[v1alpha1.IngressVisibilityClusterLocal].Insert(gateway.QualifiedName())
 	}
 
+	// The hosts claimed by other Ingresses first are neither served nor routed for this one,
+	// so the secrets, servers and VirtualServices are made from the Ingress without them.
+	conflicts, err := r.findHostConflicts(ctx, ing)
+	if err != nil {
+		return err
+	}
+	desired := withoutHosts(ing, conflicts)
+
+	tlsStart := time.Now()
+	var tlsReconciled bool
+	// The certificates are verified by the probes, which are sent again once they are rotated.
+	var (
+		certSecrets  []*corev1.Secret
+		certsRotated bool
+	)
 	externalIngressGateways := []*v1beta1.Gateway{}
-	if shouldReconcileExternalDomainTLS(ing) {
-		originSecrets, err := resources.GetSecrets(ing, v1alpha1.IngressVisibilityExternalIP, r.secretLister)
-		if err != nil {
-			return err
-		}
-		nonWildcardSecrets, wildcardSecrets, err := resources.CategorizeSecrets(originSecrets)
-		if err != nil {
-			return err
-		}
-		targetNonwildcardSecrets, err := resources.MakeSecrets(ctx, nonWildcardSecrets, ing)
-		if err != nil {
-			return err
-		}
-		targetWildcardSecrets, err := resources.MakeWildcardSecrets(ctx, wildcardSecrets, ing)
-		if err != nil {
+	wildcardGateways := []*v1beta1.Gateway{}
+	if shouldReconcileExternalDomainTLS(desired) && !shouldReconcileTLSPassthrough(desired) {
+		tlsReconciled = true
+		externalIngressTLS := desired.GetIngressTLSForVisibility(v1alpha1.IngressVisibilityExternalIP)
+		var nonWildcardSecrets, wildcardSecrets map[string]*corev1.Secret
+		if err := r.traceStage(ctx, spanSecrets, ing, func(ctx context.Context) error {
+			originSecrets, err := resources.GetSecrets(desired, v1alpha1.IngressVisibilityExternalIP, r.secretLister)
+			if err != nil {
+				return err
+			}
+			if nonWildcardSecrets, wildcardSecrets, err = resources.CategorizeSecrets(originSecrets); err != nil {
+				return err
+			}
+			targetNonwildcardSecrets, err := resources.MakeSecrets(ctx, nonWildcardSecrets, desired)
+			if err != nil {
+				return err
+			}
+			targetWildcardSecrets, err := resources.MakeWildcardSecrets(ctx, wildcardSecrets, desired)
+			if err != nil {
+				return err
+			}
+			targetSecrets := make([]*corev1.Secret, 0, len(targetNonwildcardSecrets)+len(targetWildcardSecrets))
+			targetSecrets = append(targetSecrets, targetNonwildcardSecrets...)
+			targetSecrets = append(targetSecrets, targetWildcardSecrets...)
+			certSecrets = append(certSecrets, targetSecrets...)
+			rotated, err := r.reconcileCertSecrets(ctx, ing, targetSecrets)
+			certsRotated = certsRotated || rotated
 			return err
-		}
-		targetSecrets := make([]*corev1.Secret, 0, len(targetNonwildcardSecrets)+len(targetWildcardSecrets))
-		targetSecrets = append(targetSecrets, targetNonwildcardSecrets...)
-		targetSecrets = append(targetSecrets, targetWildcardSecrets...)
-		if err := r.reconcileCertSecrets(ctx, ing, targetSecrets); err != nil {
+		}); err != nil {
 			return err
 		}
 
-		nonWildcardIngressTLS := resources.GetNonWildcardIngressTLS(ing.GetIngressTLSForVisibility(v1alpha1.IngressVisibilityExternalIP), nonWildcardSecrets)
-		externalIngressGateways, err = resources.MakeIngressTLSGateways(ctx, ing, v1alpha1.IngressVisibilityExternalIP,
-			nonWildcardIngressTLS, nonWildcardSecrets, r.svcLister)
-		if err != nil {
+		if err := r.traceStage(ctx, spanGateways, ing, func(ctx context.Context) error {
+			nonWildcardIngressTLS := resources.GetNonWildcardIngressTLS(externalIngressTLS, nonWildcardSecrets)
+			var err error
+			externalIngressGateways, err = resources.MakeIngressTLSGateways(ctx, desired, v1alpha1.IngressVisibilityExternalIP,
+				nonWildcardIngressTLS, nonWildcardSecrets, r.svcLister)
+			return err
+		}); err != nil {
 			return err
 		}
 
@@ -167,85 +232,208 @@ func (r *Reconciler) reconcileIngress(ctx context.Context, ing *v1alpha1.Ingress
 		// same wildcard host. We need to handle wildcard certificate specially because Istio does
 		// not fully support multiple TLS Servers (or Gateways) share the same certificate.
 		// https://istio.io/docs/ops/common-problems/network-issues/
-		desiredWildcardGateways, err := resources.MakeWildcardTLSGateways(ctx, ing, wildcardSecrets, r.svcLister)
-		if err != nil {
-			return err
-		}
-		if err := r.reconcileWildcardGateways(ctx, desiredWildcardGateways, ing); err != nil {
+		if err := r.traceStage(ctx, spanWildcardGateways, ing, func(ctx context.Context) error {
+			desiredWildcardGateways, err := resources.MakeWildcardTLSGateways(ctx, desired, wildcardSecrets, r.svcLister)
+			if err != nil {
+				return err
+			}
+			if wildcardGateways, err = resources.RestrictGatewaysToHosts(ctx, desired, desiredWildcardGateways); err != nil {
+				return err
+			}
+			return r.reconcileWildcardGateways(ctx, wildcardGateways, ing)
+		}); err != nil {
 			return err
 		}
-		gatewayNames[v1alpha1.IngressVisibilityExternalIP].Insert(resources.GetQualifiedGatewayNames(desiredWildcardGateways)...)
+		gatewayNames[v1alpha1.IngressVisibilityExternalIP].Insert(resources.GetQualifiedGatewayNames(wildcardGateways)...)
 	}
 
 	clusterLocalIngressGateways := []*v1beta1.Gateway{}
-	if cfg.Network.ClusterLocalDomainTLS == netconfig.EncryptionEnabled && shouldReconcileClusterLocalDomainTLS(ing) {
-		originSecrets, err := resources.GetSecrets(ing, v1alpha1.IngressVisibilityClusterLocal, r.secretLister)
-		if err != nil {
+	if cfg.Network.ClusterLocalDomainTLS == netconfig.EncryptionEnabled && shouldReconcileClusterLocalDomainTLS(desired) {
+		tlsReconciled = true
+		var originSecrets map[string]*corev1.Secret
+		if err := r.traceStage(ctx, spanSecrets, ing, func(ctx context.Context) error {
+			var err error
+			if originSecrets, err = resources.GetSecrets(desired, v1alpha1.IngressVisibilityClusterLocal, r.secretLister); err != nil {
+				return err
+			}
+			targetSecrets, err := resources.MakeSecrets(ctx, originSecrets, desired)
+			if err != nil {
+				return err
+			}
+			certSecrets = append(certSecrets, targetSecrets...)
+			rotated, err := r.reconcileCertSecrets(ctx, ing, targetSecrets)
+			certsRotated = certsRotated || rotated
 			return err
-		}
-		targetSecrets, err := resources.MakeSecrets(ctx, originSecrets, ing)
-		if err != nil {
+		}); err != nil {
 			return err
 		}
-		if err = r.reconcileCertSecrets(ctx, ing, targetSecrets); err != nil {
+		if err := r.traceStage(ctx, spanGateways, ing, func(ctx context.Context) error {
+			var err error
+			clusterLocalIngressGateways, err = resources.MakeIngressTLSGateways(ctx, desired, v1alpha1.IngressVisibilityClusterLocal,
+				desired.GetIngressTLSForVisibility(v1alpha1.IngressVisibilityClusterLocal), originSecrets, r.svcLister)
+			return err
+		}); err != nil {
 			return err
 		}
-		clusterLocalIngressGateways, err = resources.MakeIngressTLSGateways(ctx, ing, v1alpha1.IngressVisibilityClusterLocal,
-			ing.GetIngressTLSForVisibility(v1alpha1.IngressVisibilityClusterLocal), originSecrets, r.svcLister)
-		if err != nil {
+	}
+	if tlsReconciled {
+		r.metrics.recordPhase(ctx, phaseTLS, time.Since(tlsStart))
+	}
+
+	gatewaysStart := time.Now()
+	routeRedirect, err := routeHTTPSRedirect(ctx, desired)
+	if err != nil {
+		return err
+	}
+	if shouldReconcileTLSPassthrough(desired) {
+		// The VirtualServices route the TLS traffic to this port of the backends.
+		if _, err := resources.TLSPassthroughPort(desired); err != nil {
 			return err
 		}
 	}
 
-	if shouldReconcileHTTPServer(ing) {
-		httpServer := resources.MakeHTTPServer(ing.Spec.HTTPOption, getPublicHosts(ing))
-		if len(externalIngressGateways) == 0 {
-			var err error
-			if externalIngressGateways, err = resources.MakeExternalIngressGateways(ctx, ing, []*istiov1beta1.Server{httpServer}, r.svcLister); err != nil {
+	if err := r.traceStage(ctx, spanGateways, ing, func(ctx context.Context) error {
+		var err error
+		if shouldReconcileTLSPassthrough(desired) {
+			// The workloads terminate TLS themselves, so the public hosts are only served by a
+			// passthrough server and routed by SNI.
+			passthroughServer := resources.MakePassthroughServer(desired, getPublicHosts(desired))
+			if externalIngressGateways, err = resources.MakeExternalIngressGateways(ctx, desired, []*istiov1beta1.Server{passthroughServer}, r.svcLister); err != nil {
 				return err
 			}
+		} else if shouldReconcileHTTPServer(desired) {
+			httpOption := desired.Spec.HTTPOption
+			if routeRedirect != nil {
+				// The VirtualService routes redirect to HTTPS instead of the server.
+				httpOption = v1alpha1.HTTPOptionEnabled
+			}
+			httpServer := resources.MakeHTTPServer(httpOption, getPublicHosts(desired))
+			if len(externalIngressGateways) == 0 {
+				if externalIngressGateways, err = resources.MakeExternalIngressGateways(ctx, desired, []*istiov1beta1.Server{httpServer}, r.svcLister); err != nil {
+					return err
+				}
+			} else {
+				// add HTTP Server into ingressGateways.
+				for i := range externalIngressGateways {
+					externalIngressGateways[i].Spec.Servers = append(externalIngressGateways[i].Spec.Servers, httpServer)
+				}
+			}
 		} else {
-			// add HTTP Server into ingressGateways.
-			for i := range externalIngressGateways {
-				externalIngressGateways[i].Spec.Servers = append(externalIngressGateways[i].Spec.Servers, httpServer)
+			// Otherwise, we fall back to the default global Gateways for HTTP behavior.
+			// We need this for the backward compatibility.
+			defaultGlobalHTTPGateways := defaultGateways[v1alpha1.IngressVisibilityExternalIP]
+
+			for _, gateway := range defaultGlobalHTTPGateways {
+				gatewayNames[v1alpha1.IngressVisibilityExternalIP].Insert(gateway.QualifiedName())
 			}
 		}
-	} else {
-		// Otherwise, we fall back to the default global Gateways for HTTP behavior.
-		// We need this for the backward compatibility.
-		defaultGlobalHTTPGateways := defaultGateways[v1alpha1.IngressVisibilityExternalIP]
 
-		for _, gateway := range defaultGlobalHTTPGateways {
-			gatewayNames[v1alpha1.IngressVisibilityExternalIP].Insert(gateway.QualifiedName())
+		// When hosts are placed on gateways by domain, each gateway service only serves some of them.
+		if externalIngressGateways, err = resources.RestrictGatewaysToHosts(ctx, desired, externalIngressGateways); err != nil {
+			return err
 		}
-	}
+		if err := r.reconcileIngressGateways(ctx, externalIngressGateways); err != nil {
+			return err
+		}
+		gatewayNames[v1alpha1.IngressVisibilityExternalIP].Insert(resources.GetQualifiedGatewayNames(externalIngressGateways)...)
 
-	if err := r.reconcileIngressGateways(ctx, externalIngressGateways); err != nil {
+		if err := r.reconcileIngressGateways(ctx, clusterLocalIngressGateways); err != nil {
+			return err
+		}
+		gatewayNames[v1alpha1.IngressVisibilityClusterLocal].Insert(resources.GetQualifiedGatewayNames(clusterLocalIngressGateways)...)
+		return nil
+	}); err != nil {
 		return err
 	}
-	gatewayNames[v1alpha1.IngressVisibilityExternalIP].Insert(resources.GetQualifiedGatewayNames(externalIngressGateways)...)
+	r.metrics.recordPhase(ctx, phaseGateways, time.Since(gatewaysStart))
 
-	if err := r.reconcileIngressGateways(ctx, clusterLocalIngressGateways); err != nil {
+	virtualServicesStart := time.Now()
+	gatewayHosts, err := resources.ExternalGatewayHosts(ctx, desired, append(externalIngressGateways, wildcardGateways...))
+	if err != nil {
 		return err
 	}
-	gatewayNames[v1alpha1.IngressVisibilityClusterLocal].Insert(resources.GetQualifiedGatewayNames(clusterLocalIngressGateways)...)
+	makeVirtualServices := func(gateways map[v1alpha1.IngressVisibility]sets.Set[string]) ([]*v1beta1.VirtualService, error) {
+		vses, err := resources.MakeVirtualServices(desired, gateways)
+		if err != nil {
+			return nil, err
+		}
+		for _, vs := range vses {
+			resources.RestrictToGatewayHosts(vs, gatewayHosts)
+		}
+		if shouldAdvertiseHTTP3(ctx, desired) {
+			// Istio serves HTTP/3 next to the HTTPS servers of the external gateways, so clients
+			// only need to be told about it.
+			tlsHosts := sets.New[string]()
+			for _, tls := range desired.GetIngressTLSForVisibility(v1alpha1.IngressVisibilityExternalIP) {
+				tlsHosts.Insert(tls.Hosts...)
+			}
+			for _, vs := range vses {
+				resources.AdvertiseHTTP3(vs, gatewayNames[v1alpha1.IngressVisibilityExternalIP], tlsHosts)
+			}
+		}
+		if routeRedirect != nil {
+			for _, vs := range vses {
+				resources.RedirectToHTTPS(vs, *routeRedirect, gateways[v1alpha1.IngressVisibilityExternalIP])
+			}
+		}
+		return vses, nil
+	}
 
-	vses, err := resources.MakeVirtualServices(ing, gatewayNames)
+	// An Ingress moving to other Gateways, e.g. after the Gateways in config-istio changed,
+	// keeps being routed through and served by the Gateways it leaves until it was probed
+	// Ready on the new ones.
+	programmed := gatewayNames[v1alpha1.IngressVisibilityClusterLocal].Union(gatewayNames[v1alpha1.IngressVisibilityExternalIP])
+	leavingGateways, migrating := r.migrateGateways(ctx, ing, programmed)
+	routedGateways, err := r.withLeavingGateways(desired, gatewayNames, leavingGateways)
+	if err != nil {
+		return err
+	}
+	vses, err := makeVirtualServices(routedGateways)
 	if err != nil {
 		return err
 	}
 
 	logger.Info("Creating/Updating VirtualServices")
-	if err := r.reconcileVirtualServices(ctx, ing, vses); err != nil {
+	if err := r.traceStage(ctx, spanVirtualServices, ing, func(ctx context.Context) error {
+		return r.reconcileVirtualServices(ctx, ing, vses)
+	}); err != nil {
 		ing.Status.MarkLoadBalancerFailed(virtualServiceNotReconciled, err.Error())
 		return err
 	}
+	r.metrics.recordPhase(ctx, phaseVirtualServices, time.Since(virtualServicesStart))
+
+	// Remove the per-Ingress Gateways that are no longer needed, e.g. after TLS passthrough was disabled.
+	// This happens after the VirtualServices stopped referencing them, so the ones the Ingress is
+	// leaving are kept until it is migrated.
+	keptGateways := sets.New[string]()
+	for _, gws := range [][]*v1beta1.Gateway{externalIngressGateways, clusterLocalIngressGateways} {
+		for _, gw := range gws {
+			keptGateways.Insert(gw.Name)
+		}
+	}
+	if err := r.cleanupIngressGateways(ctx, ing, keptGateways.Union(ingressGatewayNames(ing, leavingGateways))); err != nil {
+		return err
+	}
+
+	// Remember where the Ingress is programmed, including the Gateways it is leaving.
+	setProgrammedGateways(ing, programmed.Union(leavingGateways))
 
 	// Update status
 	ing.Status.MarkNetworkConfigured()
 
+	// The Ingress cannot become ready without routing all its hosts.
+	if len(conflicts) > 0 {
+		markHostConflicts(ctx, ing, conflicts)
+		logger.Info("Ingress synced with host conflicts")
+		return nil
+	}
+
+	// The config status readiness strategy checks the resources generated for the Ingress.
+	ctx = withGeneratedResources(ctx, vses, slices.Concat(externalIngressGateways, clusterLocalIngressGateways))
+	ctx = withCertSecrets(ctx, certSecrets)
+
 	var ready bool
-	if ing.IsReady() {
+	if ing.IsReady() && !migrating && !certsRotated {
 		// When the kingress has already been marked Ready for this generation,
 		// then it must have been successfully probed.  The status manager has
 		// caching built-in, which makes this exception unnecessary for the case
@@ -255,26 +443,56 @@ func (r *Reconciler) reconcileIngress(ctx context.Context, ing *v1alpha1.Ingress
 		// As this is an optimization, we don't worry about the ObservedGeneration
 		// skew we might see when the resource is actually in flux, we simply care
 		// about the steady state.
+		// An Ingress moving to other Gateways is probed again though, until it
+		// was probed Ready on them, and so is an Ingress whose certificates
+		// were rotated, to verify that the gateways serve the new ones.
 		logger.Debug("Kingress is ready, skipping probe.")
 		ready = true
 	} else {
-		readyStatus, err := r.statusManager.IsReady(ctx, ing)
+		probingStart := time.Now()
+		probingCtx, span := r.startSpan(ctx, spanProbing, ing)
+		readyStatus, err := r.statusManager.IsReady(probingCtx, ing)
+		span.SetAttributes(readyAttr.With(readyStatus))
+		recordSpanError(span, err)
+		span.End()
+		r.metrics.recordPhase(ctx, phaseProbing, time.Since(probingStart))
+		if markHTTP3PortNotExposed(ing, err) {
+			logger.Warnw("Ingress advertises HTTP/3 on gateways not exposing the HTTP/3 port", zap.Error(err))
+			return controller.NewRequeueAfter(http3RecheckDelay)
+		}
 		if err != nil {
 			return fmt.Errorf("failed to probe Ingress %s/%s: %w", ing.GetNamespace(), ing.GetName(), err)
 		}
 		ready = readyStatus
 	}
 
-	if ready {
-		publicGatewayURL := gatewayServiceURL(defaultGateways[v1alpha1.IngressVisibilityExternalIP])
-		publicLbs := getLBStatus(publicGatewayURL)
-
-		privateGatewayURL := gatewayServiceURL(defaultGateways[v1alpha1.IngressVisibilityClusterLocal])
-		privateLbs := getLBStatus(privateGatewayURL)
+	if ready && leavingGateways.Len() > 0 {
+		// The Ingress is served by its new Gateways, so it can be removed from the ones it left.
+		logger.Infof("Removing the Ingress from the Gateways %v it left", sets.List(leavingGateways))
+		vses, err := makeVirtualServices(gatewayNames)
+		if err != nil {
+			return err
+		}
+		if err := r.reconcileVirtualServices(ctx, ing, vses); err != nil {
+			ing.Status.MarkLoadBalancerFailed(virtualServiceNotReconciled, err.Error())
+			return err
+		}
+		if err := r.cleanupIngressGateways(ctx, ing, keptGateways); err != nil {
+			return err
+		}
+		if err := r.cleanupGatewayServers(ctx, ing, leavingGateways); err != nil {
+			return err
+		}
+		setProgrammedGateways(ing, programmed)
+	}
 
+	if ready {
+		publicLbs := r.getLBStatus(ctx, defaultGateways[v1alpha1.IngressVisibilityExternalIP])
+		privateLbs := r.getLBStatus(ctx, defaultGateways[v1alpha1.IngressVisibilityClusterLocal])
 		ing.Status.MarkLoadBalancerReady(publicLbs, privateLbs)
+		r.markLoadBalancerCoverage(ctx, ing)
 	} else {
-		ing.Status.MarkLoadBalancerNotReady()
+		r.markLoadBalancerNotReady(ctx, ing)
 	}
 
 	// TODO(zhiminx): Mark Route status to indicate that Gateway is configured.
@@ -285,7 +503,8 @@ func (r *Reconciler) reconcileIngress(ctx context.Context, ing *v1alpha1.Ingress
 // reconcileMeshOnlyIngress handles Ingress reconciliation when gateways are
 // disabled. It creates mesh VirtualServices, cleans up any leftover gateway
 // resources from a previous gateway-enabled configuration, and marks the
-// ingress as ready without probing.
+// ingress as ready without probing, once the VirtualServices are distributed
+// if the readiness strategy uses the config status.
 func (r *Reconciler) reconcileMeshOnlyIngress(ctx context.Context, ing *v1alpha1.Ingress) error {
 	logger := logging.FromContext(ctx)
 	logger.Info("Gateways disabled, reconciling mesh-only ingress")
@@ -295,21 +514,27 @@ func (r *Reconciler) reconcileMeshOnlyIngress(ctx context.Context, ing *v1alpha1
 		v1alpha1.IngressVisibilityExternalIP:   sets.New[string](),
 	}
 
-	vses, err := resources.MakeVirtualServices(ing, emptyGateways)
+	conflicts, err := r.findHostConflicts(ctx, ing)
+	if err != nil {
+		return err
+	}
+	vses, err := resources.MakeVirtualServices(withoutHosts(ing, conflicts), emptyGateways)
 	if err != nil {
 		return err
 	}
 
 	logger.Info("Creating/Updating mesh VirtualServices")
 	// This also deletes any old ingress VirtualServices that referenced gateways.
-	if err := r.reconcileVirtualServices(ctx, ing, vses); err != nil {
+	if err := r.traceStage(ctx, spanVirtualServices, ing, func(ctx context.Context) error {
+		return r.reconcileVirtualServices(ctx, ing, vses)
+	}); err != nil {
 		ing.Status.MarkLoadBalancerFailed(virtualServiceNotReconciled, err.Error())
 		return err
 	}
 
 	// Clean up any per-ingress Gateways that were created for TLS when
 	// gateways were previously enabled.
-	if err := r.cleanupIngressGateways(ctx, ing); err != nil {
+	if err := r.cleanupIngressGateways(ctx, ing, sets.New[string]()); err != nil {
 		return err
 	}
 
@@ -319,8 +544,38 @@ func (r *Reconciler) reconcileMeshOnlyIngress(ctx context.Context, ing *v1alpha1
 		return err
 	}
 
+	// Clean up the servers of the Ingress on the Gateways it was programmed on.
+	if previous, ok := programmedGateways(ing); ok {
+		if err := r.cleanupGatewayServers(ctx, ing, previous); err != nil {
+			return err
+		}
+	}
+	// Record that the Ingress is not programmed on any Gateway, so that it is probed
+	// again once gateways are enabled.
+	setProgrammedGateways(ing, sets.New[string]())
+
 	ing.Status.MarkNetworkConfigured()
 
+	if len(conflicts) > 0 {
+		markHostConflicts(ctx, ing, conflicts)
+		logger.Info("Mesh-only ingress synced with host conflicts")
+		return nil
+	}
+
+	// There is nothing to probe, but the mesh VirtualService can be waited for until the
+	// sidecars received it.
+	if config.FromContext(ctx).Istio.ReadinessStrategy.UsesConfigStatus() {
+		ready, err := r.statusManager.IsReady(withGeneratedResources(ctx, vses, nil), ing)
+		if err != nil {
+			return fmt.Errorf("failed to check the config status of Ingress %s/%s: %w", ing.GetNamespace(), ing.GetName(), err)
+		}
+		if !ready {
+			ing.Status.MarkLoadBalancerNotReady()
+			logger.Info("Waiting for the mesh VirtualServices to be distributed")
+			return nil
+		}
+	}
+
 	meshOnlyLbs := []v1alpha1.LoadBalancerIngressStatus{{MeshOnly: true}}
 	ing.Status.MarkLoadBalancerReady(meshOnlyLbs, meshOnlyLbs)
 
@@ -329,9 +584,10 @@ func (r *Reconciler) reconcileMeshOnlyIngress(ctx context.Context, ing *v1alpha1
 }
 
 // cleanupIngressGateways deletes any per-ingress Istio Gateways owned by the
-// given Ingress. These are created during TLS reconciliation and must be
-// removed when switching to mesh-only mode.
-func (r *Reconciler) cleanupIngressGateways(ctx context.Context, ing *v1alpha1.Ingress) error {
+// given Ingress, except the ones named in kept. These are created during TLS
+// reconciliation and must be removed when they are no longer needed, e.g. when
+// switching to mesh-only mode.
+func (r *Reconciler) cleanupIngressGateways(ctx context.Context, ing *v1alpha1.Ingress, kept sets.Set[string]) error {
 	logger := logging.FromContext(ctx)
 
 	gateways, err := r.gatewayLister.Gateways(ing.GetNamespace()).List(
@@ -341,7 +597,7 @@ func (r *Reconciler) cleanupIngressGateways(ctx context.Context, ing *v1alpha1.I
 	}
 
 	for _, gw := range gateways {
-		if !metav1.IsControlledBy(gw, ing) {
+		if kept.Has(gw.Name) || !metav1.IsControlledBy(gw, ing) {
 			continue
 		}
 		logger.Infof("Deleting leftover Gateway %s/%s", gw.Namespace, gw.Name)
@@ -389,17 +645,27 @@ func getPublicHosts(ing *v1alpha1.Ingress) []string {
 	return sets.List(hosts)
 }
 
-func (r *Reconciler) reconcileCertSecrets(ctx context.Context, ing *v1alpha1.Ingress, desiredSecrets []*corev1.Secret) error {
+// reconcileCertSecrets reconciles the Secrets copied for the certificates of the given Ingress,
+// and returns true if the certificate of an existing copy changed, e.g. after a rotation.
+func (r *Reconciler) reconcileCertSecrets(ctx context.Context, ing *v1alpha1.Ingress, desiredSecrets []*corev1.Secret) (bool, error) {
+	rotated := false
 	for _, certSecret := range desiredSecrets {
 		// We track the origin and desired secrets so that desired secrets could be synced accordingly when the origin TLS certificate
 		// secret is refreshed.
 		r.tracker.TrackReference(resources.SecretRef(certSecret.Namespace, certSecret.Name), ing)
 		r.tracker.TrackReference(resources.ExtractOriginSecretRef(certSecret), ing)
+		if existing, err := r.secretLister.Secrets(certSecret.Namespace).Get(certSecret.Name); err == nil &&
+			!bytes.Equal(existing.Data[corev1.TLSCertKey], certSecret.Data[corev1.TLSCertKey]) {
+			rotated = true
+		}
 		if _, err := coreaccessor.ReconcileSecret(ctx, nil, certSecret, r); err != nil {
-			return err
+			if kaccessor.IsNotOwned(err) {
+				r.metrics.recordNotOwned(ctx, "Secret")
+			}
+			return rotated, err
 		}
 	}
-	return nil
+	return rotated, nil
 }
 
 func (r *Reconciler) reconcileWildcardGateways(ctx context.Context, gateways []*v1beta1.Gateway, ing *v1alpha1.Ingress) error {
@@ -453,13 +719,16 @@ func (r *Reconciler) reconcileVirtualServices(ctx context.Context, ing *v1alpha1
 		if _, err := istioaccessor.ReconcileVirtualService(ctx, ing, d, r); err != nil {
 			if kaccessor.IsNotOwned(err) {
 				ing.Status.MarkResourceNotOwned("VirtualService", d.Name)
+				r.metrics.recordNotOwned(ctx, "VirtualService")
 			}
 			return err
 		}
 		kept.Insert(d.Name)
 	}
 
-	// Now, remove the extra ones.
+	// Now, remove the extra ones. A VirtualService may match both selectors, so the
+	// deleted ones are remembered as the listers still return them.
+	deleted := sets.New[string]()
 	selectors := map[string]string{
 		networking.IngressLabelKey: ing.GetName(),                            // VS created from 0.12 on
 		resources.RouteLabelKey:    ing.GetLabels()[resources.RouteLabelKey], // VS created before 0.12
@@ -478,7 +747,7 @@ func (r *Reconciler) reconcileVirtualServices(ctx context.Context, ing *v1alpha1
 
 		for _, vs := range vses {
 			n, ns := vs.Name, vs.Namespace
-			if kept.Has(n) {
+			if kept.Has(n) || deleted.Has(n) {
 				continue
 			}
 			if !metav1.IsControlledBy(vs, ing) {
@@ -488,6 +757,7 @@ func (r *Reconciler) reconcileVirtualServices(ctx context.Context, ing *v1alpha1
 			if err = r.istioClientSet.NetworkingV1beta1().VirtualServices(ns).Delete(ctx, n, metav1.DeleteOptions{}); err != nil {
 				return fmt.Errorf("failed to delete VirtualService: %w", err)
 			}
+			deleted.Insert(n)
 		}
 	}
 	return nil
@@ -497,11 +767,23 @@ func (r *Reconciler) FinalizeKind(ctx context.Context, ing *v1alpha1.Ingress) pk
 	logger := logging.FromContext(ctx)
 	istiocfg := config.FromContext(ctx).Istio
 
+	// The Gateways the Ingress was programmed on may not be configured anymore.
+	if previous, ok := programmedGateways(ing); ok {
+		if err := r.cleanupGatewayServers(ctx, ing, previous); err != nil {
+			return err
+		}
+	}
+
 	if !istiocfg.GatewaysEnabled() {
 		logger.Info("Gateways disabled, skipping Gateway Server cleanup")
 		return nil
 	}
 
+	ctx, err := r.withNamespaceLabels(ctx, ing)
+	if err != nil {
+		return err
+	}
+
 	logger.Info("Cleaning up Gateway Servers")
 	for _, gws := range [][]config.Gateway{istiocfg.IngressGateways, istiocfg.LocalGateways} {
 		for _, gw := range gws {
@@ -544,6 +826,22 @@ func (r *Reconciler) cleanupCertificateSecrets(ctx context.Context, ing *v1alpha
 	return errors.NewAggregate(errs)
 }
 
+// withNamespaceLabels attaches the labels of the namespace of the Ingress to the context
+// when gateways select Ingresses by namespace.
+func (r *Reconciler) withNamespaceLabels(ctx context.Context, ing *v1alpha1.Ingress) (context.Context, error) {
+	if !config.FromContext(ctx).Istio.UsesNamespaceSelectors() {
+		return ctx, nil
+	}
+	ns, err := r.namespaceLister.Get(ing.GetNamespace())
+	if apierrs.IsNotFound(err) {
+		// The namespace is being deleted along with the Ingress.
+		return ctx, nil
+	} else if err != nil {
+		return ctx, fmt.Errorf("failed to get namespace %s: %w", ing.GetNamespace(), err)
+	}
+	return resources.WithNamespaceLabels(ctx, ns.Labels), nil
+}
+
 func (r *Reconciler) reconcileIngressServers(ctx context.Context, ing *v1alpha1.Ingress, gw config.Gateway, desired []*istiov1beta1.Server) error {
 	gateway, err := r.gatewayLister.Gateways(gw.Namespace).Get(gw.Name)
 	if err != nil {
@@ -590,34 +888,37 @@ func (r *Reconciler) GetVirtualServiceLister() istiolisters.VirtualServiceLister
 	return r.virtualServiceLister
 }
 
-func gatewayServiceURL(gateways []config.Gateway) string {
-	if len(gateways) == 0 {
-		return ""
-	}
+func shouldReconcileExternalDomainTLS(ing *v1alpha1.Ingress) bool {
+	return isIngressPublic(ing) && len(ing.GetIngressTLSForVisibility(v1alpha1.IngressVisibilityExternalIP)) > 0
+}
 
-	return gateways[0].ServiceURL
+func shouldReconcileClusterLocalDomainTLS(ing *v1alpha1.Ingress) bool {
+	return len(ing.GetIngressTLSForVisibility(v1alpha1.IngressVisibilityClusterLocal)) > 0
 }
 
-// getLBStatus gets the LB Status.
-func getLBStatus(gatewayServiceURL string) []v1alpha1.LoadBalancerIngressStatus {
-	// The Ingress isn't load-balanced by any particular
-	// Service, but through a Service mesh.
-	if gatewayServiceURL == "" {
-		return []v1alpha1.LoadBalancerIngressStatus{
-			{MeshOnly: true},
-		}
-	}
-	return []v1alpha1.LoadBalancerIngressStatus{
-		{DomainInternal: gatewayServiceURL},
-	}
+func shouldReconcileTLSPassthrough(ing *v1alpha1.Ingress) bool {
+	return isIngressPublic(ing) && resources.IsTLSPassthrough(ing)
 }
 
-func shouldReconcileExternalDomainTLS(ing *v1alpha1.Ingress) bool {
-	return isIngressPublic(ing) && len(ing.GetIngressTLSForVisibility(v1alpha1.IngressVisibilityExternalIP)) > 0
+func shouldAdvertiseHTTP3(ctx context.Context, ing *v1alpha1.Ingress) bool {
+	return config.FromContext(ctx).Istio.EnableHTTP3 && shouldReconcileExternalDomainTLS(ing) && !shouldReconcileTLSPassthrough(ing)
 }
 
-func shouldReconcileClusterLocalDomainTLS(ing *v1alpha1.Ingress) bool {
-	return len(ing.GetIngressTLSForVisibility(v1alpha1.IngressVisibilityClusterLocal)) > 0
+// routeHTTPSRedirect returns the HTTPS redirect to be done by the VirtualService routes of
+// the given Ingress, or nil if the Ingress is not redirected or if the `httpsRedirect` flag of
+// the Gateway server is enough to redirect it.
+func routeHTTPSRedirect(ctx context.Context, ing *v1alpha1.Ingress) (*config.HTTPSRedirect, error) {
+	if ing.Spec.HTTPOption != v1alpha1.HTTPOptionRedirected || !isIngressPublic(ing) || resources.IsTLSPassthrough(ing) {
+		return nil, nil
+	}
+	redirect, err := resources.HTTPSRedirectFromContext(ctx, ing)
+	if err != nil {
+		return nil, err
+	}
+	if redirect.IsDefault() {
+		return nil, nil
+	}
+	return &redirect, nil
 }
 
 func shouldReconcileHTTPServer(ing *v1alpha1.Ingress) bool {
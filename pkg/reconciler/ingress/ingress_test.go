@@ -25,10 +25,14 @@ import (
 	"time"
 
 	// Inject our fakes
+	istioconfigv1alpha1 "knative.dev/net-istio/pkg/apis/istio/v1alpha1"
+	fakeistioconfigclient "knative.dev/net-istio/pkg/client/injection/client/fake"
+	_ "knative.dev/net-istio/pkg/client/injection/informers/istio/v1alpha1/istioconfig/fake"
 	istioclient "knative.dev/net-istio/pkg/client/istio/injection/client"
 	fakeistioclient "knative.dev/net-istio/pkg/client/istio/injection/client/fake"
 	_ "knative.dev/net-istio/pkg/client/istio/injection/informers/networking/v1beta1/gateway/fake"
 	_ "knative.dev/net-istio/pkg/client/istio/injection/informers/networking/v1beta1/virtualservice/fake"
+	_ "knative.dev/net-istio/pkg/client/kube/injection/informers/discovery/v1/endpointslice/fake"
 	fakenetworkingclient "knative.dev/networking/pkg/client/injection/client/fake"
 	fakeingressclient "knative.dev/networking/pkg/client/injection/informers/networking/v1alpha1/ingress/fake"
 	"knative.dev/networking/pkg/ingress"
@@ -36,8 +40,7 @@ import (
 	fakestatusmanager "knative.dev/networking/pkg/testing/status"
 	kubeclient "knative.dev/pkg/client/injection/kube/client"
 	fakekubeclient "knative.dev/pkg/client/injection/kube/client/fake"
-	_ "knative.dev/pkg/client/injection/kube/informers/core/v1/endpoints/fake"
-	_ "knative.dev/pkg/client/injection/kube/informers/core/v1/pod/fake"
+	_ "knative.dev/pkg/client/injection/kube/informers/core/v1/namespace/fake"
 	_ "knative.dev/pkg/client/injection/kube/informers/core/v1/secret/filtered/fake"
 	_ "knative.dev/pkg/client/injection/kube/informers/core/v1/service/fake"
 	filteredFactory "knative.dev/pkg/client/injection/kube/informers/factory/filtered"
@@ -48,6 +51,7 @@ import (
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/testing/protocmp"
 
+	istiometa "istio.io/api/meta/v1alpha1"
 	istiov1beta1 "istio.io/api/networking/v1beta1"
 	"istio.io/client-go/pkg/apis/networking/v1beta1"
 
@@ -100,9 +104,10 @@ var (
 )
 
 var (
-	nonWildcardCert, _ = resources.GenerateCertificate([]string{"host-1.example.com"}, "secret0", "istio-system")
-	wildcardCert, _    = resources.GenerateCertificate([]string{"*.example.com"}, "secret0", "istio-system")
-	selector           = map[string]string{
+	nonWildcardCert, _   = resources.GenerateCertificate([]string{"host-1.example.com"}, "secret0", "istio-system")
+	wildcardCert, _      = resources.GenerateCertificate([]string{"*.example.com"}, "secret0", "istio-system")
+	mixedWildcardCert, _ = resources.GenerateCertificate([]string{"host-tls.example.com", "*.test.example.com"}, "secret0", "istio-system")
+	selector             = map[string]string{
 		"istio": "ingress",
 	}
 	gwLabels = map[string]string{
@@ -405,11 +410,12 @@ func TestReconcile(t *testing.T) {
 			Name: "reconcile-virtualservice-extra",
 		}},
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
-			Object: ingressWithStatus("reconcile-virtualservice",
+			Object: withProgrammedGateways(ingressWithStatus("reconcile-virtualservice",
 				v1alpha1.IngressStatus{
 					PublicLoadBalancer: &v1alpha1.LoadBalancerStatus{
 						Ingress: []v1alpha1.LoadBalancerIngressStatus{
 							{DomainInternal: pkgnet.GetServiceHostname("test-ingressgateway", "istio-system")},
+							{DomainInternal: pkgnet.GetServiceHostname("istio-ingressgateway", "istio-system")},
 						},
 					},
 					PrivateLoadBalancer: &v1alpha1.LoadBalancerStatus{
@@ -433,7 +439,7 @@ func TestReconcile(t *testing.T) {
 						}},
 					},
 				},
-			),
+			), "knative-testing/knative-ingress-gateway", "knative-testing/knative-test-gateway"),
 		}},
 		WantEvents: []string{
 			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", "reconcile-virtualservice"),
@@ -451,21 +457,86 @@ func TestReconcile(t *testing.T) {
 		Name: "if ingress is already ready, we shouldn't call statusManager.IsReady",
 		Key:  "test-ns/ingress-ready",
 		Objects: []runtime.Object{
-			basicReconciledIngress("ingress-ready"),
+			withProgrammedGateways(basicReconciledIngress("ingress-ready"),
+				"knative-testing/knative-ingress-gateway", "knative-testing/knative-test-gateway"),
 			resources.MakeMeshVirtualService(insertProbe(ing("ingress-ready")), makeGatewayMap([]string{"knative-testing/knative-test-gateway", "knative-testing/" + config.KnativeIngressGateway}, nil)),
 			resources.MakeIngressVirtualService(insertProbe(ing("ingress-ready")), makeGatewayMap([]string{"knative-testing/knative-test-gateway", "knative-testing/" + config.KnativeIngressGateway}, nil)),
 		},
 		PostConditions: []func(*testing.T, *TableRow){proberCalledTimes(0)},
 		CmpOpts:        defaultCmpOptsList,
+	}, {
+		Name: "host claimed by an Ingress of another namespace is not routed",
+		Key:  "test-ns/host-conflict",
+		Objects: []runtime.Object{
+			withProgrammedGateways(basicReconciledIngress("host-conflict"),
+				"knative-testing/knative-ingress-gateway", "knative-testing/knative-test-gateway"),
+			resources.MakeMeshVirtualService(insertProbe(ing("host-conflict")), makeGatewayMap([]string{"knative-testing/knative-test-gateway", "knative-testing/" + config.KnativeIngressGateway}, nil)),
+			resources.MakeIngressVirtualService(insertProbe(ing("host-conflict")), makeGatewayMap([]string{"knative-testing/knative-test-gateway", "knative-testing/" + config.KnativeIngressGateway}, nil)),
+			// Ingresses created at the same time are ordered by namespace.
+			conflictIngress("owner-ns", "owner", time.Time{}, "host-tls.example.com"),
+			resources.MakeIngressVirtualService(insertProbe(conflictIngress("owner-ns", "owner", time.Time{}, "host-tls.example.com")), makeGatewayMap([]string{"knative-testing/knative-test-gateway", "knative-testing/" + config.KnativeIngressGateway}, nil)),
+		},
+		WantDeletes: []clientgotesting.DeleteActionImpl{{
+			ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS,
+				Verb:      "delete",
+				Resource:  v1beta1.SchemeGroupVersion.WithResource("virtualservices"),
+			},
+			Name: "host-conflict-ingress",
+		}},
+		// The probe of the routed hosts changed.
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: resources.MakeMeshVirtualService(insertProbe(withoutHosts(ing("host-conflict"), []hostConflict{{Host: "host-tls.example.com"}})), gateways),
+		}},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: withProgrammedGateways(ingressWithStatusAndFinalizers("host-conflict",
+				v1alpha1.IngressStatus{
+					PrivateLoadBalancer: &v1alpha1.LoadBalancerStatus{Ingress: []v1alpha1.LoadBalancerIngressStatus{{MeshOnly: true}}},
+					PublicLoadBalancer: &v1alpha1.LoadBalancerStatus{Ingress: []v1alpha1.LoadBalancerIngressStatus{
+						{DomainInternal: "test-ingressgateway.istio-system.svc.cluster.local"},
+						{DomainInternal: "istio-ingressgateway.istio-system.svc.cluster.local"},
+					}},
+					Status: duckv1.Status{
+						Conditions: duckv1.Conditions{{
+							Type:     v1alpha1.IngressConditionLoadBalancerReady,
+							Status:   corev1.ConditionFalse,
+							Severity: apis.ConditionSeverityError,
+							Reason:   hostConflictReason,
+							Message:  "Hosts not routed: host-tls.example.com is claimed by Ingress owner-ns/owner",
+						}, {
+							Type:     v1alpha1.IngressConditionNetworkConfigured,
+							Status:   corev1.ConditionTrue,
+							Severity: apis.ConditionSeverityError,
+						}, {
+							Type:     v1alpha1.IngressConditionReady,
+							Status:   corev1.ConditionFalse,
+							Severity: apis.ConditionSeverityError,
+							Reason:   hostConflictReason,
+							Message:  "Hosts not routed: host-tls.example.com is claimed by Ingress owner-ns/owner",
+						}},
+					},
+				}, []string{"ingresses.networking.internal.knative.dev"},
+			), "knative-testing/knative-ingress-gateway", "knative-testing/knative-test-gateway"),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "Updated", "Updated VirtualService %s/%s", "test-ns", "host-conflict-mesh"),
+			Eventf(corev1.EventTypeWarning, hostConflictReason, "Hosts not routed: host-tls.example.com is claimed by Ingress owner-ns/owner"),
+		},
+		PostConditions: []func(*testing.T, *TableRow){proberCalledTimes(0)},
+		CmpOpts:        defaultCmpOptsList,
 	}}
 
 	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher) controller.Reconciler {
 		r := &Reconciler{
-			kubeclient:           kubeclient.Get(ctx),
-			istioClientSet:       istioclient.Get(ctx),
-			virtualServiceLister: listers.GetVirtualServiceLister(),
-			gatewayLister:        listers.GetGatewayLister(),
-			statusManager:        ctx.Value(FakeStatusManagerKey).(status.Manager),
+			kubeclient:            kubeclient.Get(ctx),
+			istioClientSet:        istioclient.Get(ctx),
+			virtualServiceLister:  listers.GetVirtualServiceLister(),
+			gatewayLister:         listers.GetGatewayLister(),
+			svcLister:             listers.GetK8sServiceLister(),
+			virtualServiceIndexer: virtualServiceHostIndexer(listers),
+			ingressLister:         listers.GetIngressLister(),
+			tracker:               &NullTracker{},
+			statusManager:         ctx.Value(FakeStatusManagerKey).(status.Manager),
 		}
 
 		return ingressreconciler.NewReconciler(ctx, logging.FromContext(ctx), fakenetworkingclient.Get(ctx),
@@ -478,6 +549,37 @@ func TestReconcile(t *testing.T) {
 }
 
 func TestReconcile_ExternalDomainTLS(t *testing.T) {
+	// A ready Ingress whose Secret was rotated.
+	rotatedIngress := withProgrammedGateways(ingressWithTLSAndStatus("reconciling-ingress",
+		ingressTLSWithSecretNamespace("knative-serving"),
+		v1alpha1.IngressStatus{
+			PublicLoadBalancer: &v1alpha1.LoadBalancerStatus{
+				Ingress: []v1alpha1.LoadBalancerIngressStatus{
+					{DomainInternal: pkgnet.GetServiceHostname("istio-ingressgateway", "istio-system")},
+				},
+			},
+			PrivateLoadBalancer: &v1alpha1.LoadBalancerStatus{
+				Ingress: []v1alpha1.LoadBalancerIngressStatus{{MeshOnly: true}},
+			},
+			Status: duckv1.Status{
+				Conditions: duckv1.Conditions{{
+					Type:     v1alpha1.IngressConditionLoadBalancerReady,
+					Status:   corev1.ConditionTrue,
+					Severity: apis.ConditionSeverityError,
+				}, {
+					Type:     v1alpha1.IngressConditionNetworkConfigured,
+					Status:   corev1.ConditionTrue,
+					Severity: apis.ConditionSeverityError,
+				}, {
+					Type:     v1alpha1.IngressConditionReady,
+					Status:   corev1.ConditionTrue,
+					Severity: apis.ConditionSeverityError,
+				}},
+			},
+		},
+	), "test-ns/"+externalIngressTLSGatewayName)
+	rotatedIngress.Finalizers = []string{ingressFinalizer}
+
 	table := TableTest{{
 		Name:                    "create Ingress Gateway to match newly created Ingress",
 		SkipNamespaceValidation: true,
@@ -498,7 +600,7 @@ func TestReconcile_ExternalDomainTLS(t *testing.T) {
 			patchAddFinalizerAction("reconciling-ingress", ingressFinalizer),
 		},
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
-			Object: ingressWithTLSAndStatus("reconciling-ingress",
+			Object: withProgrammedGateways(ingressWithTLSAndStatus("reconciling-ingress",
 				externalIngressTLS,
 				v1alpha1.IngressStatus{
 					PublicLoadBalancer: &v1alpha1.LoadBalancerStatus{
@@ -527,7 +629,130 @@ func TestReconcile_ExternalDomainTLS(t *testing.T) {
 						}},
 					},
 				},
-			),
+			), "test-ns/"+externalIngressTLSGatewayName),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", "reconciling-ingress"),
+			Eventf(corev1.EventTypeNormal, "Created", "Created VirtualService %q", "reconciling-ingress-mesh"),
+			Eventf(corev1.EventTypeNormal, "Created", "Created VirtualService %q", "reconciling-ingress-ingress"),
+		},
+		Key:     "test-ns/reconciling-ingress",
+		CmpOpts: defaultCmpOptsList,
+	}, {
+		Name:                    "new Ingress using TLS passthrough",
+		SkipNamespaceValidation: true,
+		Objects: []runtime.Object{
+			passthroughIngressWithTLS("reconciling-ingress", externalIngressTLS),
+			ingressService,
+		},
+		WantCreates: []runtime.Object{
+			// The per-Ingress Gateway forwards the TLS traffic without terminating it,
+			// so the TLS secret is not copied.
+			gateway(externalIngressTLSGatewayName, testNS,
+				[]*istiov1beta1.Server{resources.MakePassthroughServer(passthroughIngressWithTLS("reconciling-ingress", externalIngressTLS), []string{"host-tls.example.com"})},
+				withOwnerRef(passthroughIngressWithTLS("reconciling-ingress", externalIngressTLS)),
+				withLabels(gwLabels), withSelector(selector)),
+			resources.MakeMeshVirtualService(insertProbe(passthroughIngressWithTLS("reconciling-ingress", externalIngressTLS)), externalIngressGateway),
+			resources.MakeIngressVirtualService(insertProbe(passthroughIngressWithTLS("reconciling-ingress", externalIngressTLS)), makeGatewayMap([]string{"test-ns/" + externalIngressTLSGatewayName}, nil)),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchAddFinalizerAction("reconciling-ingress", ingressFinalizer),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: withProgrammedGateways(addAnnotations(ingressWithTLSAndStatus("reconciling-ingress",
+				externalIngressTLS,
+				v1alpha1.IngressStatus{
+					PublicLoadBalancer: &v1alpha1.LoadBalancerStatus{
+						Ingress: []v1alpha1.LoadBalancerIngressStatus{
+							{DomainInternal: pkgnet.GetServiceHostname("istio-ingressgateway", "istio-system")},
+						},
+					},
+					PrivateLoadBalancer: &v1alpha1.LoadBalancerStatus{
+						Ingress: []v1alpha1.LoadBalancerIngressStatus{
+							{MeshOnly: true},
+						},
+					},
+					Status: duckv1.Status{
+						Conditions: duckv1.Conditions{{
+							Type:     v1alpha1.IngressConditionLoadBalancerReady,
+							Status:   corev1.ConditionTrue,
+							Severity: apis.ConditionSeverityError,
+						}, {
+							Type:     v1alpha1.IngressConditionNetworkConfigured,
+							Status:   corev1.ConditionTrue,
+							Severity: apis.ConditionSeverityError,
+						}, {
+							Type:     v1alpha1.IngressConditionReady,
+							Status:   corev1.ConditionTrue,
+							Severity: apis.ConditionSeverityError,
+						}},
+					},
+				},
+			), map[string]string{resources.TLSModeAnnotationKey: resources.TLSModePassthrough}), "test-ns/"+externalIngressTLSGatewayName),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", "reconciling-ingress"),
+			Eventf(corev1.EventTypeNormal, "Created", "Created VirtualService %q", "reconciling-ingress-mesh"),
+			Eventf(corev1.EventTypeNormal, "Created", "Created VirtualService %q", "reconciling-ingress-ingress"),
+		},
+		Key:     "test-ns/reconciling-ingress",
+		CmpOpts: defaultCmpOptsList,
+	}, {
+		Name:                    "new Ingress redirecting to HTTPS with a custom code",
+		SkipNamespaceValidation: true,
+		Objects: []runtime.Object{
+			redirectedIngressWithTLS("reconciling-ingress", externalIngressTLS, "308"),
+			originSecret("istio-system", "secret0"),
+			ingressService,
+		},
+		WantCreates: []runtime.Object{
+			// The HTTP server does not redirect itself, the VirtualService routes do.
+			gateway(externalIngressTLSGatewayName, testNS, []*istiov1beta1.Server{externalIngressTLSServer, ingressHTTPServer},
+				withOwnerRef(redirectedIngressWithTLS("reconciling-ingress", externalIngressTLS, "308")),
+				withLabels(gwLabels), withSelector(selector)),
+			resources.MakeMeshVirtualService(insertProbe(redirectedIngressWithTLS("reconciling-ingress", externalIngressTLS, "308")), externalIngressGateway),
+			func() *v1beta1.VirtualService {
+				vs := resources.MakeIngressVirtualService(insertProbe(redirectedIngressWithTLS("reconciling-ingress", externalIngressTLS, "308")),
+					makeGatewayMap([]string{"test-ns/" + externalIngressTLSGatewayName}, nil))
+				resources.RedirectToHTTPS(vs, config.HTTPSRedirect{Code: 308}, sets.New("test-ns/"+externalIngressTLSGatewayName))
+				return vs
+			}(),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchAddFinalizerAction("reconciling-ingress", ingressFinalizer),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: withProgrammedGateways(func() *v1alpha1.Ingress {
+				ing := redirectedIngressWithTLS("reconciling-ingress", externalIngressTLS, "308")
+				ing.Status = v1alpha1.IngressStatus{
+					PublicLoadBalancer: &v1alpha1.LoadBalancerStatus{
+						Ingress: []v1alpha1.LoadBalancerIngressStatus{
+							{DomainInternal: pkgnet.GetServiceHostname("istio-ingressgateway", "istio-system")},
+						},
+					},
+					PrivateLoadBalancer: &v1alpha1.LoadBalancerStatus{
+						Ingress: []v1alpha1.LoadBalancerIngressStatus{
+							{MeshOnly: true},
+						},
+					},
+					Status: duckv1.Status{
+						Conditions: duckv1.Conditions{{
+							Type:     v1alpha1.IngressConditionLoadBalancerReady,
+							Status:   corev1.ConditionTrue,
+							Severity: apis.ConditionSeverityError,
+						}, {
+							Type:     v1alpha1.IngressConditionNetworkConfigured,
+							Status:   corev1.ConditionTrue,
+							Severity: apis.ConditionSeverityError,
+						}, {
+							Type:     v1alpha1.IngressConditionReady,
+							Status:   corev1.ConditionTrue,
+							Severity: apis.ConditionSeverityError,
+						}},
+					},
+				}
+				return ing
+			}(), "test-ns/"+externalIngressTLSGatewayName),
 		}},
 		WantEvents: []string{
 			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", "reconciling-ingress"),
@@ -565,7 +790,7 @@ func TestReconcile_ExternalDomainTLS(t *testing.T) {
 			patchAddFinalizerAction("reconciling-ingress", ingressFinalizer),
 		},
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
-			Object: ingressWithTLSAndStatus("reconciling-ingress",
+			Object: withProgrammedGateways(ingressWithTLSAndStatus("reconciling-ingress",
 				externalIngressTLS,
 				v1alpha1.IngressStatus{
 					PublicLoadBalancer: &v1alpha1.LoadBalancerStatus{
@@ -594,7 +819,7 @@ func TestReconcile_ExternalDomainTLS(t *testing.T) {
 						}},
 					},
 				},
-			),
+			), "test-ns/"+externalIngressTLSGatewayName),
 		}},
 		WantEvents: []string{
 			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", "reconciling-ingress"),
@@ -629,7 +854,7 @@ func TestReconcile_ExternalDomainTLS(t *testing.T) {
 			patchAddFinalizerAction("reconciling-ingress", ingressFinalizer),
 		},
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
-			Object: ingressWithTLSAndStatus("reconciling-ingress",
+			Object: withProgrammedGateways(ingressWithTLSAndStatus("reconciling-ingress",
 				externalIngressTLS,
 				v1alpha1.IngressStatus{
 					PublicLoadBalancer: &v1alpha1.LoadBalancerStatus{
@@ -658,7 +883,72 @@ func TestReconcile_ExternalDomainTLS(t *testing.T) {
 						}},
 					},
 				},
-			),
+			), "istio-system/"+resources.WildcardGatewayName(wildcardCert.Name, ingressService.Namespace, ingressService.Name), "test-ns/"+externalIngressTLSGatewayName),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", "reconciling-ingress"),
+			Eventf(corev1.EventTypeNormal, "Created", "Created VirtualService %q", "reconciling-ingress-mesh"),
+			Eventf(corev1.EventTypeNormal, "Created", "Created VirtualService %q", "reconciling-ingress-ingress"),
+		},
+		Key:     "test-ns/reconciling-ingress",
+		CmpOpts: defaultCmpOptsList,
+	}, {
+		Name:                    "new Ingress using wildcard certificate with an explicit host",
+		SkipNamespaceValidation: true,
+		Objects: []runtime.Object{
+			ingressWithTLS("reconciling-ingress", externalIngressTLS),
+			mixedWildcardCert,
+			ingressService,
+		},
+		WantCreates: []runtime.Object{
+			wildcardGateway(resources.WildcardGatewayName(wildcardCert.Name, ingressService.Namespace, ingressService.Name), "istio-system",
+				[]*istiov1beta1.Server{withHosts(deepCopy(wildcardTLSServer), "host-tls.example.com", "*.test.example.com")}, selector),
+			// The explicit host of the wildcard certificate is served by the wildcard Gateway too,
+			// so the per-Ingress Gateway has no server with the same certificate.
+			gateway(externalIngressTLSGatewayName, testNS, []*istiov1beta1.Server{ingressHTTPServer},
+				withOwnerRef(ingressWithTLS("reconciling-ingress", externalIngressTLS)),
+				withLabels(gwLabels), withSelector(selector)),
+
+			resources.MakeMeshVirtualService(insertProbe(ingressWithTLS("reconciling-ingress", externalIngressTLS)), externalIngressGateway),
+			resources.MakeIngressVirtualService(insertProbe(ingressWithTLS("reconciling-ingress", externalIngressTLS)), makeGatewayMap([]string{
+				"istio-system/" + resources.WildcardGatewayName(wildcardCert.Name, ingressService.Namespace, ingressService.Name),
+				"test-ns/" + externalIngressTLSGatewayName,
+			}, nil)),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchAddFinalizerAction("reconciling-ingress", ingressFinalizer),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: withProgrammedGateways(ingressWithTLSAndStatus("reconciling-ingress",
+				externalIngressTLS,
+				v1alpha1.IngressStatus{
+					PublicLoadBalancer: &v1alpha1.LoadBalancerStatus{
+						Ingress: []v1alpha1.LoadBalancerIngressStatus{
+							{DomainInternal: pkgnet.GetServiceHostname("istio-ingressgateway", "istio-system")},
+						},
+					},
+					PrivateLoadBalancer: &v1alpha1.LoadBalancerStatus{
+						Ingress: []v1alpha1.LoadBalancerIngressStatus{
+							{MeshOnly: true},
+						},
+					},
+					Status: duckv1.Status{
+						Conditions: duckv1.Conditions{{
+							Type:     v1alpha1.IngressConditionLoadBalancerReady,
+							Status:   corev1.ConditionTrue,
+							Severity: apis.ConditionSeverityError,
+						}, {
+							Type:     v1alpha1.IngressConditionNetworkConfigured,
+							Status:   corev1.ConditionTrue,
+							Severity: apis.ConditionSeverityError,
+						}, {
+							Type:     v1alpha1.IngressConditionReady,
+							Status:   corev1.ConditionTrue,
+							Severity: apis.ConditionSeverityError,
+						}},
+					},
+				},
+			), "istio-system/"+resources.WildcardGatewayName(wildcardCert.Name, ingressService.Namespace, ingressService.Name), "test-ns/"+externalIngressTLSGatewayName),
 		}},
 		WantEvents: []string{
 			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", "reconciling-ingress"),
@@ -809,7 +1099,7 @@ func TestReconcile_ExternalDomainTLS(t *testing.T) {
 			patchAddFinalizerAction("reconciling-ingress", ingressFinalizer),
 		},
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
-			Object: ingressWithTLSAndStatus("reconciling-ingress",
+			Object: withProgrammedGateways(ingressWithTLSAndStatus("reconciling-ingress",
 				ingressTLSWithSecretNamespace("knative-serving"),
 				v1alpha1.IngressStatus{
 					PublicLoadBalancer: &v1alpha1.LoadBalancerStatus{
@@ -838,7 +1128,7 @@ func TestReconcile_ExternalDomainTLS(t *testing.T) {
 						}},
 					},
 				},
-			),
+			), "test-ns/"+externalIngressTLSGatewayName),
 		}},
 		WantEvents: []string{
 			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", "reconciling-ingress"),
@@ -907,7 +1197,7 @@ func TestReconcile_ExternalDomainTLS(t *testing.T) {
 			patchAddFinalizerAction("reconciling-ingress", ingressFinalizer),
 		},
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
-			Object: ingressWithTLSAndStatus("reconciling-ingress",
+			Object: withProgrammedGateways(ingressWithTLSAndStatus("reconciling-ingress",
 				ingressTLSWithSecretNamespace("knative-serving"),
 				v1alpha1.IngressStatus{
 					PublicLoadBalancer: &v1alpha1.LoadBalancerStatus{
@@ -936,7 +1226,7 @@ func TestReconcile_ExternalDomainTLS(t *testing.T) {
 						}},
 					},
 				},
-			),
+			), "test-ns/"+externalIngressTLSGatewayName),
 		}},
 		WantEvents: []string{
 			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", "reconciling-ingress"),
@@ -947,23 +1237,77 @@ func TestReconcile_ExternalDomainTLS(t *testing.T) {
 		Key:     "test-ns/reconciling-ingress",
 		CmpOpts: defaultCmpOptsList,
 	}, {
-		Name:                    "Reconcile with external-domain-tls but cluster local visibility, mesh only",
+		Name:                    "probe a ready Ingress again once its certificate is rotated",
 		SkipNamespaceValidation: true,
 		Objects: []runtime.Object{
-			ingressWithTLSClusterLocal("reconciling-ingress", externalIngressTLS),
-			originSecret("istio-system", "secret0"),
+			rotatedIngress,
+			gateway(externalIngressTLSGatewayName, testNS,
+				[]*istiov1beta1.Server{withCredentialName(deepCopy(externalIngressTLSServer), targetSecretName), ingressHTTPServer},
+				withOwnerRef(rotatedIngress), withLabels(gwLabels), withSelector(selector)),
+			ingressService,
+			originSecret("knative-serving", "secret0"),
+			// The copy of the certificate before its rotation.
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      targetSecretName,
+					Namespace: "istio-system",
+					Labels: map[string]string{
+						"networking.internal.knative.dev/certificate-uid": "",
+						networking.OriginSecretNameLabelKey:               "secret0",
+						networking.OriginSecretNamespaceLabelKey:          "knative-serving",
+					},
+				},
+				Data: map[string][]byte{
+					corev1.TLSCertKey:       []byte("previous-cert"),
+					corev1.TLSPrivateKeyKey: []byte("previous-key"),
+				},
+			},
+			resources.MakeMeshVirtualService(insertProbe(ingressWithTLS("reconciling-ingress", ingressTLSWithSecretNamespace("knative-serving"))), externalIngressGateway),
+			resources.MakeIngressVirtualService(insertProbe(ingressWithTLS("reconciling-ingress", ingressTLSWithSecretNamespace("knative-serving"))), makeGatewayMap([]string{"test-ns/" + externalIngressTLSGatewayName}, nil)),
 		},
 		WantCreates: []runtime.Object{
-			resources.MakeMeshVirtualService(insertProbe(ingressWithTLSClusterLocal("reconciling-ingress", externalIngressTLS)), externalIngressGateway),
+			gateway(externalIngressTLSGatewayName, testNS,
+				[]*istiov1beta1.Server{withCredentialName(deepCopy(externalIngressTLSServer), targetSecretName), ingressHTTPServer},
+				withOwnerRef(rotatedIngress), withLabels(gwLabels), withSelector(selector)),
 		},
-		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
-			Object: ingressWithTLSAndStatusClusterLocal("reconciling-ingress",
-				externalIngressTLS,
-				v1alpha1.IngressStatus{
-					PublicLoadBalancer: &v1alpha1.LoadBalancerStatus{
-						Ingress: []v1alpha1.LoadBalancerIngressStatus{
-							{DomainInternal: pkgnet.GetServiceHostname("istio-ingressgateway", "istio-system")},
-						},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      targetSecretName,
+					Namespace: "istio-system",
+					Labels: map[string]string{
+						"networking.internal.knative.dev/certificate-uid": "",
+						networking.OriginSecretNameLabelKey:               "secret0",
+						networking.OriginSecretNamespaceLabelKey:          "knative-serving",
+					},
+				},
+				Data: nonWildcardCert.Data,
+			},
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "Updated", "Updated Secret %s/%s", "istio-system", targetSecretName),
+		},
+		PostConditions: []func(*testing.T, *TableRow){proberCalledTimes(1)},
+		Key:            "test-ns/reconciling-ingress",
+		CmpOpts:        defaultCmpOptsList,
+	}, {
+		Name:                    "Reconcile with external-domain-tls but cluster local visibility, mesh only",
+		SkipNamespaceValidation: true,
+		Objects: []runtime.Object{
+			ingressWithTLSClusterLocal("reconciling-ingress", externalIngressTLS),
+			originSecret("istio-system", "secret0"),
+		},
+		WantCreates: []runtime.Object{
+			resources.MakeMeshVirtualService(insertProbe(ingressWithTLSClusterLocal("reconciling-ingress", externalIngressTLS)), externalIngressGateway),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: withProgrammedGateways(ingressWithTLSAndStatusClusterLocal("reconciling-ingress",
+				externalIngressTLS,
+				v1alpha1.IngressStatus{
+					PublicLoadBalancer: &v1alpha1.LoadBalancerStatus{
+						Ingress: []v1alpha1.LoadBalancerIngressStatus{
+							{DomainInternal: pkgnet.GetServiceHostname("istio-ingressgateway", "istio-system")},
+						},
 					},
 					PrivateLoadBalancer: &v1alpha1.LoadBalancerStatus{
 						Ingress: []v1alpha1.LoadBalancerIngressStatus{
@@ -986,7 +1330,7 @@ func TestReconcile_ExternalDomainTLS(t *testing.T) {
 						}},
 					},
 				},
-			),
+			), "knative-testing/knative-ingress-gateway"),
 		}},
 		WantEvents: []string{
 			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", "reconciling-ingress"),
@@ -1009,18 +1353,16 @@ func TestReconcile_ExternalDomainTLS(t *testing.T) {
 		}
 
 		r := &Reconciler{
-			kubeclient:           kubeclient.Get(ctx),
-			istioClientSet:       istioclient.Get(ctx),
-			virtualServiceLister: listers.GetVirtualServiceLister(),
-			gatewayLister:        listers.GetGatewayLister(),
-			secretLister:         listers.GetSecretLister(),
-			svcLister:            listers.GetK8sServiceLister(),
-			tracker:              &NullTracker{},
-			statusManager: &fakestatusmanager.FakeStatusManager{
-				FakeIsReady: func(ctx context.Context, ing *v1alpha1.Ingress) (bool, error) {
-					return true, nil
-				},
-			},
+			kubeclient:            kubeclient.Get(ctx),
+			istioClientSet:        istioclient.Get(ctx),
+			virtualServiceLister:  listers.GetVirtualServiceLister(),
+			gatewayLister:         listers.GetGatewayLister(),
+			secretLister:          listers.GetSecretLister(),
+			svcLister:             listers.GetK8sServiceLister(),
+			virtualServiceIndexer: virtualServiceHostIndexer(listers),
+			ingressLister:         listers.GetIngressLister(),
+			tracker:               &NullTracker{},
+			statusManager:         ctx.Value(FakeStatusManagerKey).(status.Manager),
 		}
 
 		return ingressreconciler.NewReconciler(ctx, logging.FromContext(ctx), fakenetworkingclient.Get(ctx),
@@ -1066,7 +1408,7 @@ func TestReconcile_ClusterLocalDomainTLS(t *testing.T) {
 			patchAddFinalizerAction("reconciling-ingress", ingressFinalizer),
 		},
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
-			Object: ingressWithTLSAndStatus("reconciling-ingress",
+			Object: withProgrammedGateways(ingressWithTLSAndStatus("reconciling-ingress",
 				localIngressTLS,
 				v1alpha1.IngressStatus{
 					PublicLoadBalancer: &v1alpha1.LoadBalancerStatus{
@@ -1098,7 +1440,7 @@ func TestReconcile_ClusterLocalDomainTLS(t *testing.T) {
 						}},
 					},
 				},
-			),
+			), "knative-testing/knative-ingress-gateway", "knative-testing/knative-local-gateway", "test-ns/"+localIngressTLSGatewayName),
 		}},
 		WantEvents: []string{
 			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", "reconciling-ingress"),
@@ -1137,7 +1479,7 @@ func TestReconcile_ClusterLocalDomainTLS(t *testing.T) {
 			patchAddFinalizerAction("reconciling-ingress", ingressFinalizer),
 		},
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
-			Object: ingressWithTLSAndStatus("reconciling-ingress",
+			Object: withProgrammedGateways(ingressWithTLSAndStatus("reconciling-ingress",
 				localIngressTLS,
 				v1alpha1.IngressStatus{
 					PublicLoadBalancer: &v1alpha1.LoadBalancerStatus{
@@ -1169,7 +1511,7 @@ func TestReconcile_ClusterLocalDomainTLS(t *testing.T) {
 						}},
 					},
 				},
-			),
+			), "knative-testing/knative-ingress-gateway", "knative-testing/knative-local-gateway", "test-ns/"+localIngressTLSGatewayName),
 		}},
 		WantEvents: []string{
 			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", "reconciling-ingress"),
@@ -1224,13 +1566,15 @@ func TestReconcile_ClusterLocalDomainTLS(t *testing.T) {
 		}
 
 		r := &Reconciler{
-			kubeclient:           kubeclient.Get(ctx),
-			istioClientSet:       istioclient.Get(ctx),
-			virtualServiceLister: listers.GetVirtualServiceLister(),
-			gatewayLister:        listers.GetGatewayLister(),
-			secretLister:         listers.GetSecretLister(),
-			svcLister:            listers.GetK8sServiceLister(),
-			tracker:              &NullTracker{},
+			kubeclient:            kubeclient.Get(ctx),
+			istioClientSet:        istioclient.Get(ctx),
+			virtualServiceLister:  listers.GetVirtualServiceLister(),
+			gatewayLister:         listers.GetGatewayLister(),
+			secretLister:          listers.GetSecretLister(),
+			svcLister:             listers.GetK8sServiceLister(),
+			virtualServiceIndexer: virtualServiceHostIndexer(listers),
+			ingressLister:         listers.GetIngressLister(),
+			tracker:               &NullTracker{},
 			statusManager: &fakestatusmanager.FakeStatusManager{
 				FakeIsReady: func(ctx context.Context, ing *v1alpha1.Ingress) (bool, error) {
 					return true, nil
@@ -1341,6 +1685,11 @@ func secret(namespace, name string, labels map[string]string) *corev1.Secret {
 	return &secret
 }
 
+func withHosts(tlsServer *istiov1beta1.Server, hosts ...string) *istiov1beta1.Server {
+	tlsServer.Hosts = hosts
+	return tlsServer
+}
+
 func withCredentialName(tlsServer *istiov1beta1.Server, credentialName string) *istiov1beta1.Server {
 	tlsServer.Tls.CredentialName = credentialName
 	return tlsServer
@@ -1467,16 +1816,36 @@ func basicReconciledIngress(name string) *v1alpha1.Ingress {
 			}},
 		},
 		PrivateLoadBalancer: &v1alpha1.LoadBalancerStatus{Ingress: []v1alpha1.LoadBalancerIngressStatus{{MeshOnly: true}}},
-		PublicLoadBalancer:  &v1alpha1.LoadBalancerStatus{Ingress: []v1alpha1.LoadBalancerIngressStatus{{DomainInternal: "test-ingressgateway.istio-system.svc.cluster.local"}}},
+		PublicLoadBalancer: &v1alpha1.LoadBalancerStatus{Ingress: []v1alpha1.LoadBalancerIngressStatus{
+			{DomainInternal: "test-ingressgateway.istio-system.svc.cluster.local"},
+			{DomainInternal: "istio-ingressgateway.istio-system.svc.cluster.local"},
+		}},
 	}, []string{"ingresses.networking.internal.knative.dev"})
 
 	return ingress
 }
 
+func withProgrammedGateways(ing *v1alpha1.Ingress, gateways ...string) *v1alpha1.Ingress {
+	setProgrammedGateways(ing, sets.New(gateways...))
+	return ing
+}
+
 func ingressWithTLS(name string, tls []v1alpha1.IngressTLS) *v1alpha1.Ingress {
 	return ingressWithTLSAndStatus(name, tls, v1alpha1.IngressStatus{})
 }
 
+func passthroughIngressWithTLS(name string, tls []v1alpha1.IngressTLS) *v1alpha1.Ingress {
+	return addAnnotations(ingressWithTLS(name, tls), map[string]string{resources.TLSModeAnnotationKey: resources.TLSModePassthrough})
+}
+
+func redirectedIngressWithTLS(name string, tls []v1alpha1.IngressTLS, code string) *v1alpha1.Ingress {
+	ing := addAnnotations(ingressWithTLS(name, tls), map[string]string{
+		resources.IstioAnnotationPrefix + config.HTTPSRedirectCodeKey: code,
+	})
+	ing.Spec.HTTPOption = v1alpha1.HTTPOptionRedirected
+	return ing
+}
+
 func ingressWithTLSClusterLocal(name string, tls []v1alpha1.IngressTLS) *v1alpha1.Ingress {
 	ci := ingressWithTLSAndStatus(name, tls, v1alpha1.IngressStatus{}).DeepCopy()
 	rules := ci.Spec.Rules
@@ -1562,13 +1931,13 @@ func TestGlobalResyncOnUpdateGatewayConfigMap(t *testing.T) {
 		ci := obj.(*v1alpha1.Ingress)
 		t.Logf("Ingress updated: %q", ci.Name)
 
-		gateways := ci.Status.PublicLoadBalancer.Ingress
-		if len(gateways) != 1 {
-			t.Log("Unexpected gateways:", gateways)
-			return HookIncomplete
+		// The new gateway is reported first, along with the address of its load balancer.
+		want := []v1alpha1.LoadBalancerIngressStatus{
+			{DomainInternal: newDomainInternal, IP: "10.0.0.1"},
+			{DomainInternal: originDomainInternal},
 		}
-		if got, want := gateways[0].DomainInternal, newDomainInternal; got != want {
-			t.Logf("Gateway = %q, want: %q", got, want)
+		if diff := cmp.Diff(want, ci.Status.PublicLoadBalancer.Ingress); diff != "" {
+			t.Log("Unexpected gateways (-want, +got):", diff)
 			return HookIncomplete
 		}
 
@@ -1629,6 +1998,21 @@ func TestGlobalResyncOnUpdateGatewayConfigMap(t *testing.T) {
 		t.Fatal("Failed to see ingress propagation:", err)
 	}
 
+	// The Service of the new gateway is exposed by a load balancer.
+	customService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "custom",
+			Namespace: "istio-system",
+		},
+		Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+		Status: corev1.ServiceStatus{
+			LoadBalancer: corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}}},
+		},
+	}
+	if _, err := fakekubeclient.Get(ctx).CoreV1().Services("istio-system").Create(ctx, customService, metav1.CreateOptions{}); err != nil {
+		t.Fatal("Error creating service:", err)
+	}
+
 	// Test changes in gateway config map. Ingress should get updated appropriately.
 	domainConfig := corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1644,6 +2028,106 @@ func TestGlobalResyncOnUpdateGatewayConfigMap(t *testing.T) {
 	}
 }
 
+func TestGlobalResyncOnUpdateIstioConfig(t *testing.T) {
+	ctx, cancel, informers, ctrl, watcher := newTestSetup(t)
+
+	grp := errgroup.Group{}
+
+	networkingclient := fakenetworkingclient.Get(ctx)
+
+	h := NewHooks()
+
+	// Check for Ingress created as a signal that syncHandler ran
+	h.OnUpdate(&networkingclient.Fake, "ingresses", func(obj runtime.Object) HookResult {
+		ci := obj.(*v1alpha1.Ingress)
+		t.Logf("Ingress updated: %q", ci.Name)
+
+		want := []v1alpha1.LoadBalancerIngressStatus{{DomainInternal: newDomainInternal}}
+		if diff := cmp.Diff(want, ci.Status.PublicLoadBalancer.Ingress); diff != "" {
+			t.Log("Unexpected gateways (-want, +got):", diff)
+			return HookIncomplete
+		}
+
+		return HookComplete
+	})
+
+	waitInformers, err := RunAndSyncInformers(ctx, informers...)
+	if err != nil {
+		t.Fatal("Failed to start informers:", err)
+	}
+	defer func() {
+		cancel()
+		if err := grp.Wait(); err != nil {
+			t.Error("Wait() =", err)
+		}
+		waitInformers()
+	}()
+
+	if err := watcher.Start(ctx.Done()); err != nil {
+		t.Fatal("Failed to start ingress manager:", err)
+	}
+	grp.Go(func() error { return ctrl.RunContext(ctx, 1) })
+
+	ingress := ingressWithStatus("istio-config-update",
+		v1alpha1.IngressStatus{
+			PublicLoadBalancer: &v1alpha1.LoadBalancerStatus{
+				Ingress: []v1alpha1.LoadBalancerIngressStatus{
+					{DomainInternal: ""},
+				},
+			},
+			Status: duckv1.Status{
+				Conditions: duckv1.Conditions{{
+					Type:   v1alpha1.IngressConditionLoadBalancerReady,
+					Status: corev1.ConditionTrue,
+				}, {
+					Type:   v1alpha1.IngressConditionNetworkConfigured,
+					Status: corev1.ConditionTrue,
+				}, {
+					Type:   v1alpha1.IngressConditionReady,
+					Status: corev1.ConditionTrue,
+				}},
+			},
+		},
+	)
+	ingressClient := networkingclient.NetworkingV1alpha1().Ingresses(testNS)
+
+	// Create a ingress.
+	ingressClient.Create(ctx, ingress, metav1.CreateOptions{})
+	il := fakeingressclient.Get(ctx).Lister()
+	if err := wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 5*time.Second, true, func(ctx context.Context) (bool, error) {
+		l, err := il.List(labels.Everything())
+		if err != nil {
+			return false, err
+		}
+		// We only create a single ingress.
+		return len(l) > 0, nil
+	}); err != nil {
+		t.Fatal("Failed to see ingress propagation:", err)
+	}
+
+	// The IstioConfig takes precedence over the config map. Ingress should get updated appropriately.
+	istioConfig := &istioconfigv1alpha1.IstioConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      config.IstioConfigName,
+			Namespace: system.Namespace(),
+		},
+		Spec: istioconfigv1alpha1.IstioConfigSpec{
+			ExternalGateways: []istioconfigv1alpha1.Gateway{{
+				Namespace: system.Namespace(),
+				Name:      config.KnativeIngressGateway,
+				Service:   newDomainInternal,
+			}},
+		},
+	}
+	if _, err := fakeistioconfigclient.Get(ctx).IstioV1alpha1().IstioConfigs(system.Namespace()).Create(ctx, istioConfig, metav1.CreateOptions{}); err != nil {
+		t.Fatal("Failed to create IstioConfig:", err)
+	}
+
+	if err := h.WaitForHooks(3 * time.Second); err != nil {
+		t.Error(err)
+	}
+}
+
 func insertProbe(ing *v1alpha1.Ingress) *v1alpha1.Ingress {
 	ing = ing.DeepCopy()
 	ingress.InsertProbe(ing)
@@ -1809,6 +2293,8 @@ func TestReconcile_MeshOnlyMode(t *testing.T) {
 				Type:   v1alpha1.IngressConditionReady,
 				Status: corev1.ConditionTrue,
 			}},
+			// The Ingress is not programmed on any Gateway.
+			Annotations: map[string]string{resources.GatewaysStatusAnnotationKey: ""},
 		},
 		PublicLoadBalancer:  &v1alpha1.LoadBalancerStatus{Ingress: []v1alpha1.LoadBalancerIngressStatus{{MeshOnly: true}}},
 		PrivateLoadBalancer: &v1alpha1.LoadBalancerStatus{Ingress: []v1alpha1.LoadBalancerIngressStatus{{MeshOnly: true}}},
@@ -1963,12 +2449,15 @@ func TestReconcile_MeshOnlyMode(t *testing.T) {
 
 	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher) controller.Reconciler {
 		r := &Reconciler{
-			kubeclient:           kubeclient.Get(ctx),
-			istioClientSet:       istioclient.Get(ctx),
-			virtualServiceLister: listers.GetVirtualServiceLister(),
-			gatewayLister:        listers.GetGatewayLister(),
-			secretLister:         listers.GetSecretLister(),
-			statusManager:        ctx.Value(FakeStatusManagerKey).(status.Manager),
+			kubeclient:            kubeclient.Get(ctx),
+			istioClientSet:        istioclient.Get(ctx),
+			virtualServiceLister:  listers.GetVirtualServiceLister(),
+			gatewayLister:         listers.GetGatewayLister(),
+			secretLister:          listers.GetSecretLister(),
+			svcLister:             listers.GetK8sServiceLister(),
+			virtualServiceIndexer: virtualServiceHostIndexer(listers),
+			ingressLister:         listers.GetIngressLister(),
+			statusManager:         ctx.Value(FakeStatusManagerKey).(status.Manager),
 		}
 
 		return ingressreconciler.NewReconciler(ctx, logging.FromContext(ctx), fakenetworkingclient.Get(ctx),
@@ -1979,3 +2468,333 @@ func TestReconcile_MeshOnlyMode(t *testing.T) {
 			})
 	}))
 }
+
+func TestReconcile_MeshOnlyConfigStatus(t *testing.T) {
+	emptyGateways := makeGatewayMap(nil, nil)
+	meshVS := func(status *istiometa.IstioStatus) *v1beta1.VirtualService {
+		vs := resources.MakeMeshVirtualService(insertProbe(ing("mesh-only-ingress")), emptyGateways)
+		vs.Generation = 1
+		vs.Status.Conditions = status.GetConditions()
+		vs.Status.ObservedGeneration = status.GetObservedGeneration()
+		return vs
+	}
+	meshOnlyStatus := func(loadBalancerReady corev1.ConditionStatus) v1alpha1.IngressStatus {
+		status := v1alpha1.IngressStatus{
+			Status: duckv1.Status{
+				Conditions: duckv1.Conditions{{
+					Type:   v1alpha1.IngressConditionLoadBalancerReady,
+					Status: loadBalancerReady,
+				}, {
+					Type:   v1alpha1.IngressConditionNetworkConfigured,
+					Status: corev1.ConditionTrue,
+				}, {
+					Type:   v1alpha1.IngressConditionReady,
+					Status: loadBalancerReady,
+				}},
+				Annotations: map[string]string{resources.GatewaysStatusAnnotationKey: ""},
+			},
+		}
+		if loadBalancerReady == corev1.ConditionTrue {
+			status.PublicLoadBalancer = &v1alpha1.LoadBalancerStatus{Ingress: []v1alpha1.LoadBalancerIngressStatus{{MeshOnly: true}}}
+			status.PrivateLoadBalancer = &v1alpha1.LoadBalancerStatus{Ingress: []v1alpha1.LoadBalancerIngressStatus{{MeshOnly: true}}}
+		} else {
+			for i := range status.Conditions {
+				if status.Conditions[i].Status == corev1.ConditionUnknown {
+					status.Conditions[i].Reason = "Uninitialized"
+					status.Conditions[i].Message = "Waiting for load balancer to be ready"
+				}
+			}
+		}
+		return status
+	}
+
+	table := TableTest{{
+		Name: "mesh-only: mesh VirtualService distributed",
+		Objects: []runtime.Object{
+			ing("mesh-only-ingress"),
+			meshVS(reconciledStatus(1)),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: ingressWithStatus("mesh-only-ingress", meshOnlyStatus(corev1.ConditionTrue)),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", "mesh-only-ingress"),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchAddFinalizerAction("mesh-only-ingress", "ingresses.networking.internal.knative.dev"),
+		},
+		Key:     "test-ns/mesh-only-ingress",
+		CmpOpts: defaultCmpOptsList,
+	}, {
+		Name: "mesh-only: mesh VirtualService not distributed yet",
+		Objects: []runtime.Object{
+			ing("mesh-only-ingress"),
+			meshVS(nil),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: ingressWithStatus("mesh-only-ingress", meshOnlyStatus(corev1.ConditionUnknown)),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", "mesh-only-ingress"),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchAddFinalizerAction("mesh-only-ingress", "ingresses.networking.internal.knative.dev"),
+		},
+		Key:     "test-ns/mesh-only-ingress",
+		CmpOpts: defaultCmpOptsList,
+	}}
+
+	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher) controller.Reconciler {
+		cfg := meshOnlyTestConfig()
+		cfg.Istio.ReadinessStrategy = config.ReadinessStrategyConfigStatus
+		r := &Reconciler{
+			kubeclient:            kubeclient.Get(ctx),
+			istioClientSet:        istioclient.Get(ctx),
+			virtualServiceLister:  listers.GetVirtualServiceLister(),
+			gatewayLister:         listers.GetGatewayLister(),
+			secretLister:          listers.GetSecretLister(),
+			svcLister:             listers.GetK8sServiceLister(),
+			virtualServiceIndexer: virtualServiceHostIndexer(listers),
+			ingressLister:         listers.GetIngressLister(),
+			statusManager: &ingressStatusManager{
+				configStatus: &configStatusManager{
+					logger:               logging.FromContext(ctx),
+					virtualServiceLister: listers.GetVirtualServiceLister(),
+					gatewayLister:        listers.GetGatewayLister(),
+				},
+			},
+		}
+
+		return ingressreconciler.NewReconciler(ctx, logging.FromContext(ctx), fakenetworkingclient.Get(ctx),
+			listers.GetIngressLister(), controller.GetEventRecorder(ctx), r, netconfig.IstioIngressClassName, controller.Options{
+				ConfigStore: &testConfigStore{config: cfg},
+			})
+	}))
+}
+
+func TestReconcile_GatewayMigration(t *testing.T) {
+	// A server of the Ingress on a Gateway that is not configured anymore.
+	oldServer := &istiov1beta1.Server{
+		Hosts: []string{"host-tls.example.com"},
+		Port: &istiov1beta1.Port{
+			Name:     "test-ns/reconciling-ingress:0",
+			Number:   resources.ExternalGatewayHTTPSPort,
+			Protocol: "HTTPS",
+		},
+		Tls: &istiov1beta1.ServerTLSSettings{
+			Mode:           istiov1beta1.ServerTLSSettings_SIMPLE,
+			CredentialName: "secret0",
+		},
+	}
+	oldGateways := []string{"knative-testing/knative-old-gateway"}
+	newGateways := []string{"knative-testing/knative-ingress-gateway", "knative-testing/knative-test-gateway"}
+	migratingGateways := []string{"knative-testing/knative-ingress-gateway", "knative-testing/knative-old-gateway", "knative-testing/knative-test-gateway"}
+	gatewayMap := makeGatewayMap([]string{"knative-testing/knative-test-gateway", "knative-testing/" + config.KnativeIngressGateway}, nil)
+	oldGatewayMap := makeGatewayMap(oldGateways, nil)
+	migratingGatewayMap := makeGatewayMap(migratingGateways, nil)
+
+	migratingIngress := withProgrammedGateways(basicReconciledIngress("reconciling-ingress"), migratingGateways...)
+	migratingIngress.Status.MarkLoadBalancerNotReady()
+
+	deletedIngress := withProgrammedGateways(basicReconciledIngress("reconciling-ingress"), oldGateways...)
+	deletedIngress.DeletionTimestamp = &deletionTime
+
+	table := TableTest{{
+		Name:                    "ready ingress moved to other gateways is removed from the old ones and probed again",
+		SkipNamespaceValidation: true,
+		Objects: []runtime.Object{
+			withProgrammedGateways(basicReconciledIngress("reconciling-ingress"), oldGateways...),
+			gateway("knative-old-gateway", system.Namespace(), []*istiov1beta1.Server{oldServer, irrelevantServer1}),
+			resources.MakeMeshVirtualService(insertProbe(ing("reconciling-ingress")), gatewayMap),
+			resources.MakeIngressVirtualService(insertProbe(ing("reconciling-ingress")), gatewayMap),
+		},
+		WantCreates: []runtime.Object{
+			// The creation of gateways are triggered when setting up the test.
+			gateway("knative-old-gateway", system.Namespace(), []*istiov1beta1.Server{oldServer, irrelevantServer1}),
+		},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: gateway("knative-old-gateway", system.Namespace(), []*istiov1beta1.Server{irrelevantServer1}),
+		}},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: withProgrammedGateways(basicReconciledIngress("reconciling-ingress"), newGateways...),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "Updated", "Updated Gateway %s/%s", system.Namespace(), "knative-old-gateway"),
+		},
+		PostConditions: []func(*testing.T, *TableRow){proberCalledTimes(1)},
+		Key:            "test-ns/reconciling-ingress",
+		CmpOpts:        defaultCmpOptsList,
+	}, {
+		Name:                    "ingress routed through the old gateways is only routed through the new ones once probed",
+		SkipNamespaceValidation: true,
+		Objects: []runtime.Object{
+			withProgrammedGateways(basicReconciledIngress("reconciling-ingress"), oldGateways...),
+			gateway("knative-old-gateway", system.Namespace(), []*istiov1beta1.Server{oldServer, irrelevantServer1}),
+			resources.MakeMeshVirtualService(insertProbe(ing("reconciling-ingress")), gatewayMap),
+			resources.MakeIngressVirtualService(insertProbe(ing("reconciling-ingress")), oldGatewayMap),
+		},
+		WantCreates: []runtime.Object{
+			// The creation of gateways are triggered when setting up the test.
+			gateway("knative-old-gateway", system.Namespace(), []*istiov1beta1.Server{oldServer, irrelevantServer1}),
+		},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: resources.MakeIngressVirtualService(insertProbe(ing("reconciling-ingress")), migratingGatewayMap),
+		}, {
+			Object: resources.MakeIngressVirtualService(insertProbe(ing("reconciling-ingress")), gatewayMap),
+		}, {
+			Object: gateway("knative-old-gateway", system.Namespace(), []*istiov1beta1.Server{irrelevantServer1}),
+		}},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: withProgrammedGateways(basicReconciledIngress("reconciling-ingress"), newGateways...),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "Updated", "Updated VirtualService %s/%s", testNS, "reconciling-ingress-ingress"),
+			Eventf(corev1.EventTypeNormal, "Updated", "Updated VirtualService %s/%s", testNS, "reconciling-ingress-ingress"),
+			Eventf(corev1.EventTypeNormal, "Updated", "Updated Gateway %s/%s", system.Namespace(), "knative-old-gateway"),
+		},
+		PostConditions: []func(*testing.T, *TableRow){proberCalledTimes(1)},
+		Key:            "test-ns/reconciling-ingress",
+		CmpOpts:        defaultCmpOptsList,
+	}, {
+		Name:                    "migrating ingress probed ready is removed from the old gateways",
+		SkipNamespaceValidation: true,
+		Objects: []runtime.Object{
+			migratingIngress,
+			gateway("knative-old-gateway", system.Namespace(), []*istiov1beta1.Server{oldServer, irrelevantServer1}),
+			resources.MakeMeshVirtualService(insertProbe(ing("reconciling-ingress")), gatewayMap),
+			resources.MakeIngressVirtualService(insertProbe(ing("reconciling-ingress")), migratingGatewayMap),
+		},
+		WantCreates: []runtime.Object{
+			// The creation of gateways are triggered when setting up the test.
+			gateway("knative-old-gateway", system.Namespace(), []*istiov1beta1.Server{oldServer, irrelevantServer1}),
+		},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: resources.MakeIngressVirtualService(insertProbe(ing("reconciling-ingress")), gatewayMap),
+		}, {
+			Object: gateway("knative-old-gateway", system.Namespace(), []*istiov1beta1.Server{irrelevantServer1}),
+		}},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: withProgrammedGateways(basicReconciledIngress("reconciling-ingress"), newGateways...),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "Updated", "Updated VirtualService %s/%s", testNS, "reconciling-ingress-ingress"),
+			Eventf(corev1.EventTypeNormal, "Updated", "Updated Gateway %s/%s", system.Namespace(), "knative-old-gateway"),
+		},
+		PostConditions: []func(*testing.T, *TableRow){proberCalledTimes(1)},
+		Key:            "test-ns/reconciling-ingress",
+		CmpOpts:        defaultCmpOptsList,
+	}, {
+		Name:                    "ready ingress on the same gateways is not probed again",
+		SkipNamespaceValidation: true,
+		Objects: []runtime.Object{
+			withProgrammedGateways(basicReconciledIngress("reconciling-ingress"), newGateways...),
+			resources.MakeMeshVirtualService(insertProbe(ing("reconciling-ingress")), gatewayMap),
+			resources.MakeIngressVirtualService(insertProbe(ing("reconciling-ingress")), gatewayMap),
+		},
+		PostConditions: []func(*testing.T, *TableRow){proberCalledTimes(0)},
+		Key:            "test-ns/reconciling-ingress",
+		CmpOpts:        defaultCmpOptsList,
+	}, {
+		Name:                    "delete ingress programmed on a gateway that is not configured anymore",
+		SkipNamespaceValidation: true,
+		Objects: []runtime.Object{
+			deletedIngress,
+			gateway("knative-old-gateway", system.Namespace(), []*istiov1beta1.Server{oldServer, irrelevantServer1}),
+		},
+		WantCreates: []runtime.Object{
+			// The creation of gateways are triggered when setting up the test.
+			gateway("knative-old-gateway", system.Namespace(), []*istiov1beta1.Server{oldServer, irrelevantServer1}),
+		},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: gateway("knative-old-gateway", system.Namespace(), []*istiov1beta1.Server{irrelevantServer1}),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchAddFinalizerAction("reconciling-ingress", ""),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "Updated", "Updated Gateway %s/%s", system.Namespace(), "knative-old-gateway"),
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", "reconciling-ingress"),
+		},
+		Key:     "test-ns/reconciling-ingress",
+		CmpOpts: defaultCmpOptsList,
+	}}
+
+	// The old gateways keep serving the Ingress until it was probed ready on the new ones.
+	notReadyTable := TableTest{{
+		Name:                    "ingress moved to other gateways is kept on the old ones until probed ready",
+		SkipNamespaceValidation: true,
+		Objects: []runtime.Object{
+			withProgrammedGateways(basicReconciledIngress("reconciling-ingress"), oldGateways...),
+			gateway("knative-old-gateway", system.Namespace(), []*istiov1beta1.Server{oldServer, irrelevantServer1}),
+			resources.MakeMeshVirtualService(insertProbe(ing("reconciling-ingress")), gatewayMap),
+			resources.MakeIngressVirtualService(insertProbe(ing("reconciling-ingress")), oldGatewayMap),
+		},
+		WantCreates: []runtime.Object{
+			// The creation of gateways are triggered when setting up the test.
+			gateway("knative-old-gateway", system.Namespace(), []*istiov1beta1.Server{oldServer, irrelevantServer1}),
+		},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: resources.MakeIngressVirtualService(insertProbe(ing("reconciling-ingress")), migratingGatewayMap),
+		}},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: migratingIngress,
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "Updated", "Updated VirtualService %s/%s", testNS, "reconciling-ingress-ingress"),
+		},
+		PostConditions: []func(*testing.T, *TableRow){proberCalledTimes(1)},
+		Key:            "test-ns/reconciling-ingress",
+		CmpOpts:        defaultCmpOptsList,
+	}, {
+		Name:                    "migrating ingress not probed ready yet is kept on the old gateways",
+		SkipNamespaceValidation: true,
+		Objects: []runtime.Object{
+			migratingIngress,
+			gateway("knative-old-gateway", system.Namespace(), []*istiov1beta1.Server{oldServer, irrelevantServer1}),
+			resources.MakeMeshVirtualService(insertProbe(ing("reconciling-ingress")), gatewayMap),
+			resources.MakeIngressVirtualService(insertProbe(ing("reconciling-ingress")), migratingGatewayMap),
+		},
+		WantCreates: []runtime.Object{
+			// The creation of gateways are triggered when setting up the test.
+			gateway("knative-old-gateway", system.Namespace(), []*istiov1beta1.Server{oldServer, irrelevantServer1}),
+		},
+		PostConditions: []func(*testing.T, *TableRow){proberCalledTimes(1)},
+		Key:            "test-ns/reconciling-ingress",
+		CmpOpts:        defaultCmpOptsList,
+	}}
+
+	factory := func(ready bool) Factory {
+		return MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher) controller.Reconciler {
+			statusManager := ctx.Value(FakeStatusManagerKey).(*fakestatusmanager.FakeStatusManager)
+			statusManager.FakeIsReady = func(context.Context, *v1alpha1.Ingress) (bool, error) {
+				return ready, nil
+			}
+
+			// See TestReconcile_ExternalDomainTLS for why the gateways are created explicitly.
+			for _, gateway := range getGatewaysFromObjects(listers.GetIstioObjects()) {
+				fakeistioclient.Get(ctx).NetworkingV1beta1().Gateways(gateway.Namespace).Create(ctx, gateway, metav1.CreateOptions{})
+			}
+
+			r := &Reconciler{
+				kubeclient:            kubeclient.Get(ctx),
+				istioClientSet:        istioclient.Get(ctx),
+				virtualServiceLister:  listers.GetVirtualServiceLister(),
+				gatewayLister:         listers.GetGatewayLister(),
+				secretLister:          listers.GetSecretLister(),
+				svcLister:             listers.GetK8sServiceLister(),
+				virtualServiceIndexer: virtualServiceHostIndexer(listers),
+				ingressLister:         listers.GetIngressLister(),
+				statusManager:         statusManager,
+			}
+
+			return ingressreconciler.NewReconciler(ctx, logging.FromContext(ctx), fakenetworkingclient.Get(ctx),
+				listers.GetIngressLister(), controller.GetEventRecorder(ctx), r, netconfig.IstioIngressClassName, controller.Options{
+					ConfigStore: &testConfigStore{
+						config: ReconcilerTestConfig(),
+					},
+				})
+		})
+	}
+	table.Test(t, factory(true))
+	notReadyTable.Test(t, factory(false))
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"strings"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"knative.dev/net-istio/pkg/reconciler/ingress/config"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+	"knative.dev/pkg/logging"
+)
+
+// getLBStatus returns the load balancer status of an Ingress served by the given
+// gateways. Every gateway is reported by the hostname of its Service, along with the
+// addresses of the load balancer exposing the Service if any. The first gateway stays
+// first, as consumers of the status only look at the first entry. An Ingress without
+// gateways is load balanced through the mesh.
+//
+// The status of the Istio Gateways is not looked at: unlike the Gateways of the Kubernetes
+// Gateway API, networking.istio.io Gateways only report conditions and validation messages,
+// the addresses they are reachable on are only published by the Services of the gateways.
+func (r *Reconciler) getLBStatus(ctx context.Context, gateways []config.Gateway) []v1alpha1.LoadBalancerIngressStatus {
+	var ret []v1alpha1.LoadBalancerIngressStatus
+	seen := sets.New[string]()
+	for _, gateway := range gateways {
+		if gateway.ServiceURL == "" || seen.Has(gateway.ServiceURL) {
+			continue
+		}
+		seen.Insert(gateway.ServiceURL)
+
+		lbs, err := loadBalancerIngresses(r.svcLister, gateway.ServiceURL)
+		if err != nil {
+			// The addresses are informational, the gateway is still reported by its hostname.
+			logging.FromContext(ctx).Warnw("Failed to get the load balancer of gateway "+gateway.QualifiedName(), zap.Error(err))
+		}
+		if len(lbs) == 0 {
+			ret = append(ret, v1alpha1.LoadBalancerIngressStatus{DomainInternal: gateway.ServiceURL})
+			continue
+		}
+		for _, lb := range lbs {
+			ret = append(ret, v1alpha1.LoadBalancerIngressStatus{
+				DomainInternal: gateway.ServiceURL,
+				IP:             lb.IP,
+				Domain:         lb.Hostname,
+			})
+		}
+	}
+
+	if len(ret) == 0 {
+		// The Ingress isn't load-balanced by any particular
+		// Service, but through a Service mesh.
+		return []v1alpha1.LoadBalancerIngressStatus{{MeshOnly: true}}
+	}
+	return ret
+}
+
+// loadBalancerIngresses returns the load balancer addresses of the Service with the
+// given hostname, e.g. istio-ingressgateway.istio-system.svc.cluster.local. Hostnames
+// which are not the ones of a Service of the cluster have none.
+func loadBalancerIngresses(svcLister corev1listers.ServiceLister, serviceURL string) ([]corev1.LoadBalancerIngress, error) {
+	name, namespace, ok := serviceNameNamespace(serviceURL)
+	if !ok {
+		return nil, nil
+	}
+	svc, err := svcLister.Services(namespace).Get(name)
+	if apierrs.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if svc.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		return nil, nil
+	}
+	return svc.Status.LoadBalancer.Ingress, nil
+}
+
+// serviceNameNamespace returns the name and namespace of the Service with the given
+// hostname.
+func serviceNameNamespace(serviceURL string) (string, string, bool) {
+	parts := strings.SplitN(strings.TrimSuffix(serviceURL, "."), ".", 3)
+	if len(parts) < 2 {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// isGatewayService returns whether the given Service is the Service of one of the
+// given gateways.
+func isGatewayService(svc *corev1.Service, istio *config.Istio) bool {
+	if istio == nil {
+		return false
+	}
+	for _, gateways := range [][]config.Gateway{istio.IngressGateways, istio.LocalGateways} {
+		for _, gateway := range gateways {
+			if name, namespace, ok := serviceNameNamespace(gateway.ServiceURL); ok && name == svc.Name && namespace == svc.Namespace {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// loadBalancerChanged returns whether the load balancer of the given Service changed.
+func loadBalancerChanged(oldSvc, newSvc *corev1.Service) bool {
+	return oldSvc.Spec.Type != newSvc.Spec.Type ||
+		!equality.Semantic.DeepEqual(oldSvc.Status.LoadBalancer, newSvc.Status.LoadBalancer)
+}
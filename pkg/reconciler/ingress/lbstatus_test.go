@@ -0,0 +1,160 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/net-istio/pkg/reconciler/ingress/config"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+)
+
+func gatewayService(name string, typ corev1.ServiceType, lbs ...corev1.LoadBalancerIngress) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "istio-system", Name: name},
+		Spec:       corev1.ServiceSpec{Type: typ},
+		Status:     corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: lbs}},
+	}
+}
+
+func TestGetLBStatus(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, svc := range []*corev1.Service{
+		gatewayService("istio-ingressgateway", corev1.ServiceTypeLoadBalancer,
+			corev1.LoadBalancerIngress{IP: "1.2.3.4"}, corev1.LoadBalancerIngress{IP: "2001:db8::1"}),
+		gatewayService("aws-ingressgateway", corev1.ServiceTypeLoadBalancer,
+			corev1.LoadBalancerIngress{Hostname: "lb.elb.amazonaws.com"}),
+		gatewayService("pending-ingressgateway", corev1.ServiceTypeLoadBalancer),
+		gatewayService("knative-local-gateway", corev1.ServiceTypeClusterIP,
+			corev1.LoadBalancerIngress{IP: "5.6.7.8"}),
+	} {
+		indexer.Add(svc)
+	}
+	r := &Reconciler{svcLister: corev1listers.NewServiceLister(indexer)}
+
+	gateway := func(name, serviceURL string) config.Gateway {
+		return config.Gateway{Namespace: "knative-serving", Name: name, ServiceURL: serviceURL}
+	}
+	tests := []struct {
+		name     string
+		gateways []config.Gateway
+		want     []v1alpha1.LoadBalancerIngressStatus
+	}{{
+		name: "no gateways",
+		want: []v1alpha1.LoadBalancerIngressStatus{{MeshOnly: true}},
+	}, {
+		name:     "load balancer with IPs",
+		gateways: []config.Gateway{gateway("knative-ingress-gateway", "istio-ingressgateway.istio-system.svc.cluster.local")},
+		want: []v1alpha1.LoadBalancerIngressStatus{{
+			DomainInternal: "istio-ingressgateway.istio-system.svc.cluster.local",
+			IP:             "1.2.3.4",
+		}, {
+			DomainInternal: "istio-ingressgateway.istio-system.svc.cluster.local",
+			IP:             "2001:db8::1",
+		}},
+	}, {
+		name: "several gateways",
+		gateways: []config.Gateway{
+			gateway("aws-gateway", "aws-ingressgateway.istio-system.svc.cluster.local"),
+			gateway("knative-ingress-gateway", "istio-ingressgateway.istio-system.svc.cluster.local"),
+		},
+		want: []v1alpha1.LoadBalancerIngressStatus{{
+			DomainInternal: "aws-ingressgateway.istio-system.svc.cluster.local",
+			Domain:         "lb.elb.amazonaws.com",
+		}, {
+			DomainInternal: "istio-ingressgateway.istio-system.svc.cluster.local",
+			IP:             "1.2.3.4",
+		}, {
+			DomainInternal: "istio-ingressgateway.istio-system.svc.cluster.local",
+			IP:             "2001:db8::1",
+		}},
+	}, {
+		name: "gateways sharing a service",
+		gateways: []config.Gateway{
+			gateway("aws-gateway", "aws-ingressgateway.istio-system.svc.cluster.local"),
+			gateway("other-aws-gateway", "aws-ingressgateway.istio-system.svc.cluster.local"),
+		},
+		want: []v1alpha1.LoadBalancerIngressStatus{{
+			DomainInternal: "aws-ingressgateway.istio-system.svc.cluster.local",
+			Domain:         "lb.elb.amazonaws.com",
+		}},
+	}, {
+		name:     "load balancer not provisioned yet",
+		gateways: []config.Gateway{gateway("pending-gateway", "pending-ingressgateway.istio-system.svc.cluster.local")},
+		want: []v1alpha1.LoadBalancerIngressStatus{{
+			DomainInternal: "pending-ingressgateway.istio-system.svc.cluster.local",
+		}},
+	}, {
+		name:     "not a load balancer",
+		gateways: []config.Gateway{gateway("knative-local-gateway", "knative-local-gateway.istio-system.svc.cluster.local")},
+		want: []v1alpha1.LoadBalancerIngressStatus{{
+			DomainInternal: "knative-local-gateway.istio-system.svc.cluster.local",
+		}},
+	}, {
+		name:     "missing service",
+		gateways: []config.Gateway{gateway("missing-gateway", "missing.istio-system.svc.cluster.local")},
+		want: []v1alpha1.LoadBalancerIngressStatus{{
+			DomainInternal: "missing.istio-system.svc.cluster.local",
+		}},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := r.getLBStatus(context.Background(), test.gateways)
+			if !cmp.Equal(got, test.want) {
+				t.Error("getLBStatus (-want, +got):", cmp.Diff(test.want, got))
+			}
+		})
+	}
+}
+
+func TestIsGatewayService(t *testing.T) {
+	istio := &config.Istio{
+		IngressGateways: []config.Gateway{{ServiceURL: "istio-ingressgateway.istio-system.svc.cluster.local"}},
+		LocalGateways:   []config.Gateway{{ServiceURL: "knative-local-gateway.istio-system.svc.cluster.local."}},
+	}
+	tests := []struct {
+		name string
+		svc  *corev1.Service
+		want bool
+	}{{
+		name: "ingress gateway",
+		svc:  gatewayService("istio-ingressgateway", corev1.ServiceTypeLoadBalancer),
+		want: true,
+	}, {
+		name: "local gateway",
+		svc:  gatewayService("knative-local-gateway", corev1.ServiceTypeClusterIP),
+		want: true,
+	}, {
+		name: "other service",
+		svc:  gatewayService("istiod", corev1.ServiceTypeClusterIP),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := isGatewayService(test.svc, istio); got != test.want {
+				t.Errorf("isGatewayService() = %t, want %t", got, test.want)
+			}
+		})
+	}
+}
@@ -18,7 +18,10 @@ package ingress
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"hash/fnv"
+	"net"
 	"net/url"
 	"sort"
 	"strconv"
@@ -27,12 +30,19 @@ import (
 	istiov1beta1 "istio.io/api/networking/v1beta1"
 	"istio.io/client-go/pkg/apis/networking/v1beta1"
 	istiolisters "istio.io/client-go/pkg/listers/networking/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/sets"
 	corev1listers "k8s.io/client-go/listers/core/v1"
+	discoveryv1listers "k8s.io/client-go/listers/discovery/v1"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
 	"knative.dev/net-istio/pkg/reconciler/ingress/config"
 	"knative.dev/net-istio/pkg/reconciler/ingress/resources"
+	"knative.dev/networking/pkg/apis/networking"
 	"knative.dev/networking/pkg/apis/networking/v1alpha1"
 	"knative.dev/networking/pkg/ingress"
 	"knative.dev/networking/pkg/k8s"
@@ -42,23 +52,26 @@ import (
 func NewProbeTargetLister(
 	logger *zap.SugaredLogger,
 	gatewayLister istiolisters.GatewayLister,
-	endpointsLister corev1listers.EndpointsLister,
+	endpointSliceLister discoveryv1listers.EndpointSliceLister,
 	serviceLister corev1listers.ServiceLister,
+	secretLister corev1listers.SecretLister,
 ) status.ProbeTargetLister {
 	return &gatewayPodTargetLister{
-		logger:          logger,
-		gatewayLister:   gatewayLister,
-		endpointsLister: endpointsLister,
-		serviceLister:   serviceLister,
+		logger:              logger,
+		gatewayLister:       gatewayLister,
+		endpointSliceLister: endpointSliceLister,
+		serviceLister:       serviceLister,
+		secretLister:        secretLister,
 	}
 }
 
 type gatewayPodTargetLister struct {
 	logger *zap.SugaredLogger
 
-	gatewayLister   istiolisters.GatewayLister
-	endpointsLister corev1listers.EndpointsLister
-	serviceLister   corev1listers.ServiceLister
+	gatewayLister       istiolisters.GatewayLister
+	endpointSliceLister discoveryv1listers.EndpointSliceLister
+	serviceLister       corev1listers.ServiceLister
+	secretLister        corev1listers.SecretLister
 }
 
 func (l *gatewayPodTargetLister) ListProbeTargets(ctx context.Context, ing *v1alpha1.Ingress) ([]status.ProbeTarget, error) {
@@ -77,6 +90,20 @@ func (l *gatewayPodTargetLister) ListProbeTargets(ctx context.Context, ing *v1al
 		return nil, fmt.Errorf("failed to get gateways for ingress: %w", err)
 	}
 	hostsByGateway := ingress.HostsPerVisibility(ing, gatewayQualifiedNames)
+	// Hosts placed on gateways by domain are only probed through these gateways.
+	gatewayHosts, err := resources.ExternalGatewayHosts(ctx, ing, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the hosts of the gateways for ingress: %w", err)
+	}
+	for gatewayName, hosts := range gatewayHosts {
+		if probed, ok := hostsByGateway[gatewayName]; ok {
+			if hosts = probed.Intersection(ingress.ExpandedHosts(hosts)); hosts.Len() > 0 {
+				hostsByGateway[gatewayName] = hosts
+			} else {
+				delete(hostsByGateway, gatewayName)
+			}
+		}
+	}
 	gatewayNames := make([]string, 0, len(hostsByGateway))
 	for gatewayName := range hostsByGateway {
 		gatewayNames = append(gatewayNames, gatewayName)
@@ -84,37 +111,69 @@ func (l *gatewayPodTargetLister) ListProbeTargets(ctx context.Context, ing *v1al
 
 	// Sort the gateway names for a consistent ordering.
 	sort.Strings(gatewayNames)
+	http3 := shouldAdvertiseHTTP3(ctx, ing)
 	for _, gatewayName := range gatewayNames {
 		gateway, err := l.getGateway(gatewayName)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get Gateway %q: %w", gatewayName, err)
 		}
-		targets, err := l.listGatewayTargets(gateway)
+		if http3 && gatewayQualifiedNames[v1alpha1.IngressVisibilityExternalIP].Has(gatewayName) {
+			if err := l.checkHTTP3ServicePort(gateway); err != nil {
+				return nil, &http3PortNotExposedError{Gateway: gatewayName, Err: err}
+			}
+		}
+		targets, err := l.listGatewayTargets(ctx, gateway, false)
 		if err != nil {
 			return nil, fmt.Errorf("failed to list the probing URLs of Gateway %q: %w", gatewayName, err)
 		}
 		if len(targets) == 0 {
 			continue
 		}
+		hosts := probedHosts(hostsByGateway[gatewayName], cfg.Istio.ProbeHosts)
 		for _, target := range targets {
 			qualifiedTarget := status.ProbeTarget{
 				PodIPs:  target.PodIPs,
 				PodPort: target.PodPort,
 				Port:    target.Port,
-				URLs:    make([]*url.URL, 1),
-			}
-			// Pick a single host since they all end up being used in the same
-			// VirtualService and will be applied atomically by Istio.
-			host := sets.List(hostsByGateway[gatewayName])[0]
-			newURL := *target.URLs[0]
-			newURL.Host = host + ":" + target.Port
-			qualifiedTarget.URLs[0] = &newURL
+				URLs:    make([]*url.URL, 0, len(hosts)),
+			}
+			for _, host := range hosts {
+				newURL := *target.URLs[0]
+				newURL.Host = net.JoinHostPort(host, target.Port)
+				qualifiedTarget.URLs = append(qualifiedTarget.URLs, &newURL)
+			}
 			results = append(results, qualifiedTarget)
 		}
 	}
 	return results, nil
 }
 
+// probedHosts returns the hosts to probe on a gateway, sorted. By default, a single host is
+// picked since they all end up being used in the same VirtualService and will be applied
+// atomically by Istio. Otherwise, up to limit hosts are picked by their hash, so that the
+// same hosts are probed across reconciliations, or all of them with config.ProbeAllHosts.
+func probedHosts(hosts sets.Set[string], limit int) []string {
+	sorted := sets.List(hosts)
+	switch {
+	case limit == 0:
+		return sorted[:1]
+	case limit == config.ProbeAllHosts, limit >= len(sorted):
+		return sorted
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return hostHash(sorted[i]) < hostHash(sorted[j])
+	})
+	sample := sorted[:limit]
+	sort.Strings(sample)
+	return sample
+}
+
+func hostHash(host string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(host))
+	return h.Sum64()
+}
+
 func (l *gatewayPodTargetLister) getGateway(name string) (*v1beta1.Gateway, error) {
 	namespace, name, err := cache.SplitMetaNamespaceKey(name)
 	if err != nil {
@@ -126,8 +185,234 @@ func (l *gatewayPodTargetLister) getGateway(name string) (*v1beta1.Gateway, erro
 	return l.gatewayLister.Gateways(namespace).Get(name)
 }
 
-// listGatewayPodsURLs returns a probe targets for a given Gateway.
-func (l *gatewayPodTargetLister) listGatewayTargets(gateway *v1beta1.Gateway) ([]status.ProbeTarget, error) {
+// ListPassthroughProbeTargets returns the targets to probe the TLS passthrough servers of the
+// Gateways owned by the given Ingress. The URLs use the "tls" scheme and carry the SNI host to
+// present during the TLS handshake.
+func (l *gatewayPodTargetLister) ListPassthroughProbeTargets(ctx context.Context, ing *v1alpha1.Ingress) ([]status.ProbeTarget, error) {
+	gateways, err := l.gatewayLister.Gateways(ing.GetNamespace()).List(
+		labels.SelectorFromSet(labels.Set{networking.IngressLabelKey: ing.GetName()}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Gateways: %w", err)
+	}
+	// Sort the gateways for a consistent ordering.
+	sort.Slice(gateways, func(i, j int) bool {
+		return gateways[i].Name < gateways[j].Name
+	})
+
+	results := []status.ProbeTarget{}
+	for _, gateway := range gateways {
+		if !metav1.IsControlledBy(gateway, ing) {
+			continue
+		}
+		targets, err := l.listGatewayTargets(ctx, gateway, true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the probing URLs of Gateway %s/%s: %w", gateway.Namespace, gateway.Name, err)
+		}
+		for _, server := range gateway.Spec.GetServers() {
+			if server.GetTls().GetMode() != istiov1beta1.ServerTLSSettings_PASSTHROUGH {
+				continue
+			}
+			for _, target := range targets {
+				if target.Port != strconv.Itoa(int(server.GetPort().GetNumber())) {
+					continue
+				}
+				qualifiedTarget := status.ProbeTarget{
+					PodIPs:  target.PodIPs,
+					PodPort: target.PodPort,
+					Port:    target.Port,
+					URLs:    make([]*url.URL, 0, len(server.GetHosts())),
+				}
+				for _, host := range server.GetHosts() {
+					qualifiedTarget.URLs = append(qualifiedTarget.URLs, &url.URL{
+						Scheme: "tls",
+						Host:   net.JoinHostPort(host, target.Port),
+					})
+				}
+				results = append(results, qualifiedTarget)
+			}
+		}
+	}
+	return results, nil
+}
+
+// ListTLSProbeTargets returns the targets to probe the HTTPS servers of the Gateways owned by
+// the given Ingress, along with the certificates the servers are expected to serve. The URLs
+// use the "https" scheme and carry the SNI host to present during the TLS handshake. The
+// shared Gateways of wildcard certificates are not probed.
+func (l *gatewayPodTargetLister) ListTLSProbeTargets(ctx context.Context, ing *v1alpha1.Ingress) ([]tlsProbeTarget, error) {
+	gateways, err := l.gatewayLister.Gateways(ing.GetNamespace()).List(
+		labels.SelectorFromSet(labels.Set{networking.IngressLabelKey: ing.GetName()}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Gateways: %w", err)
+	}
+	// Sort the gateways for a consistent ordering.
+	sort.Slice(gateways, func(i, j int) bool {
+		return gateways[i].Name < gateways[j].Name
+	})
+
+	cfg := config.FromContext(ctx)
+	results := []tlsProbeTarget{}
+	for _, gateway := range gateways {
+		if !metav1.IsControlledBy(gateway, ing) {
+			continue
+		}
+		targets, err := l.listGatewayTargets(ctx, gateway, false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the probing URLs of Gateway %s/%s: %w", gateway.Namespace, gateway.Name, err)
+		}
+		if len(targets) == 0 {
+			continue
+		}
+		// The credentials of the servers are read from the namespace of the gateway pods.
+		service, err := l.gatewayService(gateway)
+		if err != nil {
+			return nil, err
+		}
+		for _, server := range resources.GetServers(gateway, ing) {
+			if server.GetPort().GetProtocol() != "HTTPS" || server.GetTls().GetMode() != istiov1beta1.ServerTLSSettings_SIMPLE {
+				continue
+			}
+			certificate, err := l.getCertificate(ctx, service.Namespace, server.GetTls().GetCredentialName())
+			if err != nil {
+				return nil, err
+			}
+			hosts := probedHosts(sets.New(server.GetHosts()...), cfg.Istio.ProbeHosts)
+			for _, target := range targets {
+				if target.URLs[0].Scheme != "https" || target.Port != strconv.Itoa(int(server.GetPort().GetNumber())) {
+					continue
+				}
+				qualifiedTarget := tlsProbeTarget{
+					ProbeTarget: status.ProbeTarget{
+						PodIPs:  target.PodIPs,
+						PodPort: target.PodPort,
+						Port:    target.Port,
+						URLs:    make([]*url.URL, 0, len(hosts)),
+					},
+					Certificate: certificate,
+				}
+				for _, host := range hosts {
+					qualifiedTarget.URLs = append(qualifiedTarget.URLs, &url.URL{
+						Scheme: "https",
+						Host:   net.JoinHostPort(host, target.Port),
+					})
+				}
+				results = append(results, qualifiedTarget)
+			}
+		}
+	}
+	return results, nil
+}
+
+// gatewayService returns the Service of the pods of the given Gateway.
+func (l *gatewayPodTargetLister) gatewayService(gateway *v1beta1.Gateway) (*corev1.Service, error) {
+	services, err := l.serviceLister.List(labels.SelectorFromSet(gateway.Spec.GetSelector()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Services: %w", err)
+	}
+	if len(services) == 0 {
+		return nil, fmt.Errorf("no Service selects the pods of Gateway %s/%s", gateway.Namespace, gateway.Name)
+	}
+	return services[0], nil
+}
+
+// getCertificate returns the certificate of the given Secret, as read by the gateway pods.
+// The copies being reconciled take precedence over the informer, which may not have seen
+// their update yet. Secrets which are not copied by the controller are not watched, so their
+// certificate is not verified.
+func (l *gatewayPodTargetLister) getCertificate(ctx context.Context, namespace, name string) (*tlsCertificate, error) {
+	secret := certSecretFromContext(ctx, namespace, name)
+	if secret == nil {
+		var err error
+		secret, err = l.secretLister.Secrets(namespace).Get(name)
+		if apierrs.IsNotFound(err) {
+			return nil, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to get Secret %s/%s: %w", namespace, name, err)
+		}
+	}
+	certificate, err := newTLSCertificate(secret)
+	if err != nil {
+		l.logger.Infof("Not verifying the certificate of Secret %s/%s: %v", namespace, name, err)
+		return nil, nil
+	}
+	return certificate, nil
+}
+
+// http3PortNotExposedError is returned when listing the probe targets of an Ingress advertising
+// HTTP/3 on external gateways whose Service does not expose the HTTP/3 port.
+type http3PortNotExposedError struct {
+	// Gateway is the qualified name of the gateway not exposing the HTTP/3 port.
+	Gateway string
+	Err     error
+}
+
+func (e *http3PortNotExposedError) Error() string {
+	return fmt.Sprintf("the HTTP/3 port is not exposed for the pods of Gateway %q: %v", e.Gateway, e.Err)
+}
+
+func (e *http3PortNotExposedError) Unwrap() error {
+	return e.Err
+}
+
+// markHTTP3PortNotExposed marks the given Ingress not ready and returns true if err reports that the
+// HTTP/3 port of its gateways is not exposed. This is a configuration issue of the gateway Services, which is
+// not fixed by retrying the reconciliation right away.
+func markHTTP3PortNotExposed(ing *v1alpha1.Ingress, err error) bool {
+	var notExposed *http3PortNotExposedError
+	if !errors.As(err, &notExposed) {
+		return false
+	}
+	ing.GetConditionSet().Manage(&ing.Status).MarkUnknown(v1alpha1.IngressConditionLoadBalancerReady,
+		http3PortNotExposedReason, notExposed.Error())
+	return true
+}
+
+// checkHTTP3ServicePort checks that the Service of the given Gateway exposes the external HTTPS
+// port over UDP as well, on ready endpoints. This is a precondition for Istio to program the QUIC
+// listener of an HTTPS server, not a check that the listener is programmed: e.g. when istiod runs
+// without PILOT_ENABLE_QUIC_LISTENERS, the gateway pods never listen for QUIC connections, and the
+// Ingresses are still reported ready. Confirming it needs HTTP/3 probes of the gateway pods.
+func (l *gatewayPodTargetLister) checkHTTP3ServicePort(gateway *v1beta1.Gateway) error {
+	services, err := l.serviceLister.List(labels.SelectorFromSet(gateway.Spec.GetSelector()))
+	if err != nil {
+		return fmt.Errorf("failed to list Services: %w", err)
+	}
+	if len(services) == 0 {
+		// There is nothing to probe, like in listGatewayTargets.
+		return nil
+	}
+	service := services[0]
+
+	portName := ""
+	for _, port := range service.Spec.Ports {
+		if port.Protocol == corev1.ProtocolUDP && port.Port == resources.ExternalGatewayHTTPSPort {
+			portName = port.Name
+			break
+		}
+	}
+	if portName == "" {
+		return fmt.Errorf("gateway Service %s/%s doesn't expose port %d over UDP", service.Namespace, service.Name, resources.ExternalGatewayHTTPSPort)
+	}
+
+	slices, err := l.listEndpointSlices(service)
+	if err != nil {
+		return err
+	}
+	for _, slice := range slices {
+		if len(readyAddresses(slice)) == 0 {
+			continue
+		}
+		if port, ok := endpointPort(slice, portName); ok && ptr.Deref(port.Protocol, corev1.ProtocolTCP) == corev1.ProtocolUDP {
+			return nil
+		}
+	}
+	return fmt.Errorf("no ready endpoint of Service %s/%s listens on UDP port %q", service.Namespace, service.Name, portName)
+}
+
+// listGatewayPodsURLs returns a probe targets for a given Gateway. When passthrough is true,
+// only TLS passthrough servers are considered, otherwise only HTTP and HTTPS servers. There
+// is one target per port number and IP family of the gateway pods.
+func (l *gatewayPodTargetLister) listGatewayTargets(ctx context.Context, gateway *v1beta1.Gateway, passthrough bool) ([]status.ProbeTarget, error) {
 	selector := labels.SelectorFromSet(gateway.Spec.GetSelector())
 
 	services, err := l.serviceLister.List(selector)
@@ -140,23 +425,29 @@ func (l *gatewayPodTargetLister) listGatewayTargets(gateway *v1beta1.Gateway) ([
 	}
 	service := services[0]
 
-	endpoints, err := l.endpointsLister.Endpoints(service.Namespace).Get(service.Name)
+	slices, err := l.listEndpointSlices(service)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get Endpoints: %w", err)
+		return nil, err
 	}
+	families, required := probedIPFamilies(service, config.FromContext(ctx).Istio.ProbeIPFamilies)
 
 	seen := sets.New[string]()
 	targets := []status.ProbeTarget{}
 	for _, server := range gateway.Spec.GetServers() {
 		tURL := &url.URL{}
-		switch server.GetPort().GetProtocol() {
-		case "HTTP", "HTTP2":
+		switch protocol := server.GetPort().GetProtocol(); {
+		case passthrough:
+			if protocol != "TLS" || server.GetTls().GetMode() != istiov1beta1.ServerTLSSettings_PASSTHROUGH {
+				continue
+			}
+			tURL.Scheme = "tls"
+		case protocol == "HTTP", protocol == "HTTP2":
 			if server.GetTls() != nil && server.GetTls().GetHttpsRedirect() {
 				// ignoring HTTPS redirects.
 				continue
 			}
 			tURL.Scheme = "http"
-		case "HTTPS":
+		case protocol == "HTTPS":
 			if server.GetTls().GetMode() == istiov1beta1.ServerTLSSettings_MUTUAL {
 				l.logger.Infof("Skipping Server %q because HTTPS with TLS mode MUTUAL is not supported", server.GetPort().GetName())
 				continue
@@ -180,27 +471,176 @@ func (l *gatewayPodTargetLister) listGatewayTargets(gateway *v1beta1.Gateway) ([
 		}
 		seen.Insert(key)
 
-		for _, sub := range endpoints.Subsets {
-			// The translation from server.Port.Number -> portName -> portNumber is intentional.
-			// We can't simply translate from the Service.Spec because Service.Spec.Target.Port
-			// could be either a name or a number.  In the Endpoints spec, all ports are provided
-			// as numbers.
-			portNumber, err := k8s.PortNumberForName(sub, portName)
-			if err != nil {
-				l.logger.Infof("Skipping Subset %v because it doesn't contain a port name %q", sub.Addresses, portName)
+		// The translation from server.Port.Number -> portName -> portNumber is intentional.
+		// We can't simply translate from the Service.Spec because Service.Spec.Target.Port
+		// could be either a name or a number.  In the EndpointSlices, all ports are provided
+		// as numbers. The endpoints of a Service are spread over several slices, and they
+		// may listen on different port numbers, so there is one target per port number.
+		// The slices hold the addresses of a single IP family, which are probed separately
+		// so that the pods of dual-stack Services are ready on every probed family.
+		podIPs := map[endpointPortKey]sets.Set[string]{}
+		readyFamilies := sets.New[discoveryv1.AddressType]()
+		for _, slice := range slices {
+			if !families.Has(slice.AddressType) {
 				continue
 			}
-			target := status.ProbeTarget{
-				PodIPs:  sets.New[string](),
-				PodPort: strconv.Itoa(int(portNumber)),
+			port, ok := endpointPort(slice, portName)
+			if !ok || port.Port == nil {
+				l.logger.Infof("Skipping EndpointSlice %s/%s because it doesn't contain a port name %q", slice.Namespace, slice.Name, portName)
+				continue
+			}
+			key := endpointPortKey{port: *port.Port, family: slice.AddressType}
+			if podIPs[key] == nil {
+				podIPs[key] = sets.New[string]()
+			}
+			addresses := readyAddresses(slice)
+			podIPs[key].Insert(addresses...)
+			if len(addresses) > 0 {
+				readyFamilies.Insert(slice.AddressType)
+			}
+		}
+		if required && readyFamilies.Len() > 0 && !readyFamilies.Equal(families) {
+			return nil, fmt.Errorf("no ready endpoint of Service %s/%s for IP family %s on port %q",
+				service.Namespace, service.Name, sets.List(families.Difference(readyFamilies))[0], portName)
+		}
+		keys := sets.KeySet(podIPs).UnsortedList()
+		sort.Slice(keys, func(i, j int) bool {
+			if keys[i].port != keys[j].port {
+				return keys[i].port < keys[j].port
+			}
+			return keys[i].family < keys[j].family
+		})
+		for _, key := range keys {
+			targets = append(targets, status.ProbeTarget{
+				PodIPs:  podIPs[key],
+				PodPort: strconv.Itoa(int(key.port)),
 				Port:    strconv.Itoa(int(server.GetPort().GetNumber())),
 				URLs:    []*url.URL{tURL},
+			})
+		}
+	}
+	return targets, nil
+}
+
+// endpointPortKey identifies the endpoints of a gateway probed by a target.
+type endpointPortKey struct {
+	port   int32
+	family discoveryv1.AddressType
+}
+
+// probedIPFamilies returns the IP families of the endpoints of the given Service to probe,
+// and true if the pods must be ready on all of them. The endpoints of all the families are
+// probed if the Service does not state its IP families.
+func probedIPFamilies(service *corev1.Service, probed config.ProbeIPFamilies) (sets.Set[discoveryv1.AddressType], bool) {
+	if len(service.Spec.IPFamilies) == 0 {
+		return sets.New(discoveryv1.AddressTypeIPv4, discoveryv1.AddressTypeIPv6), false
+	}
+	if probed != config.ProbeIPFamiliesAll {
+		return sets.New(discoveryv1.AddressType(service.Spec.IPFamilies[0])), false
+	}
+	families := sets.New[discoveryv1.AddressType]()
+	for _, family := range service.Spec.IPFamilies {
+		families.Insert(discoveryv1.AddressType(family))
+	}
+	return families, families.Len() > 1
+}
+
+// listEndpointSlices returns the EndpointSlices of the given Service.
+func (l *gatewayPodTargetLister) listEndpointSlices(service *corev1.Service) ([]*discoveryv1.EndpointSlice, error) {
+	slices, err := l.endpointSliceLister.EndpointSlices(service.Namespace).List(
+		labels.SelectorFromSet(labels.Set{discoveryv1.LabelServiceName: service.Name}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list EndpointSlices: %w", err)
+	}
+	return slices, nil
+}
+
+// endpointPort returns the port of the given EndpointSlice with the given name.
+func endpointPort(slice *discoveryv1.EndpointSlice, name string) (discoveryv1.EndpointPort, bool) {
+	for _, port := range slice.Ports {
+		if ptr.Deref(port.Name, "") == name {
+			return port, true
+		}
+	}
+	return discoveryv1.EndpointPort{}, false
+}
+
+// readyAddresses returns the IPv4 and IPv6 addresses of the endpoints of the given
+// EndpointSlice which are ready and not terminating. FQDN endpoints are not probed.
+//
+// An unset Ready condition means that the readiness of the endpoint is unknown, which
+// the EndpointSlice API requires consumers to interpret as ready, and likewise an unset
+// Terminating condition as not terminating. Such endpoints are probed, and the probes
+// find out whether they serve the Ingress.
+func readyAddresses(slice *discoveryv1.EndpointSlice) []string {
+	if slice.AddressType != discoveryv1.AddressTypeIPv4 && slice.AddressType != discoveryv1.AddressTypeIPv6 {
+		return nil
+	}
+	addresses := make([]string, 0, len(slice.Endpoints))
+	for _, endpoint := range slice.Endpoints {
+		if !ptr.Deref(endpoint.Conditions.Ready, true) || ptr.Deref(endpoint.Conditions.Terminating, false) {
+			continue
+		}
+		// The addresses of an endpoint are fungible, consumers use the first one.
+		if len(endpoint.Addresses) > 0 {
+			addresses = append(addresses, endpoint.Addresses[0])
+		}
+	}
+	return addresses
+}
+
+// departedPods returns Pods carrying the addresses of the gateway endpoints which stopped
+// serving between the given versions of an EndpointSlice, for the probers to stop probing
+// them: the terminating endpoints of the new version, and the endpoints of the old version
+// missing from the new one. oldObj is nil when the EndpointSlice is added, and newObj is nil
+// when it is deleted. Endpoints moving to another EndpointSlice of the same Service are not
+// departing, so those are looked up with the given lister.
+func departedPods(lister discoveryv1listers.EndpointSliceLister, oldObj, newObj interface{}) []*corev1.Pod {
+	departed, remaining := sets.New[string](), sets.New[string]()
+	if slice, ok := newObj.(*discoveryv1.EndpointSlice); ok {
+		for _, endpoint := range slice.Endpoints {
+			if ptr.Deref(endpoint.Conditions.Terminating, false) {
+				departed.Insert(endpoint.Addresses...)
+			} else {
+				remaining.Insert(endpoint.Addresses...)
 			}
-			for _, addr := range sub.Addresses {
-				target.PodIPs.Insert(addr.IP)
+		}
+	}
+
+	if tombstone, ok := oldObj.(cache.DeletedFinalStateUnknown); ok {
+		oldObj = tombstone.Obj
+	}
+	if old, ok := oldObj.(*discoveryv1.EndpointSlice); ok {
+		missing := sets.New[string]()
+		for _, endpoint := range old.Endpoints {
+			for _, address := range endpoint.Addresses {
+				if !remaining.Has(address) && !departed.Has(address) {
+					missing.Insert(address)
+				}
+			}
+		}
+		if service := old.Labels[discoveryv1.LabelServiceName]; service != "" && missing.Len() > 0 {
+			siblings, err := lister.EndpointSlices(old.Namespace).List(
+				labels.SelectorFromSet(labels.Set{discoveryv1.LabelServiceName: service}))
+			if err == nil {
+				for _, sibling := range siblings {
+					if sibling.Name == old.Name {
+						continue
+					}
+					for _, endpoint := range sibling.Endpoints {
+						if !ptr.Deref(endpoint.Conditions.Terminating, false) {
+							missing.Delete(endpoint.Addresses...)
+						}
+					}
+				}
 			}
-			targets = append(targets, target)
 		}
+		departed = departed.Union(missing)
 	}
-	return targets, nil
+
+	pods := make([]*corev1.Pod, 0, departed.Len())
+	for _, address := range sets.List(departed) {
+		pods = append(pods, &corev1.Pod{Status: corev1.PodStatus{PodIP: address}})
+	}
+	return pods
 }
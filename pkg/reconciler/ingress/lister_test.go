@@ -19,6 +19,7 @@ package ingress
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
 	"net/url"
 	"sort"
@@ -26,33 +27,41 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	istiov1beta1 "istio.io/api/networking/v1beta1"
 	v1beta1 "istio.io/client-go/pkg/apis/networking/v1beta1"
 	"knative.dev/net-istio/pkg/reconciler/ingress/config"
+	"knative.dev/net-istio/pkg/reconciler/ingress/resources"
+	"knative.dev/networking/pkg/apis/networking"
 	"knative.dev/networking/pkg/apis/networking/v1alpha1"
 	"knative.dev/networking/pkg/status"
+	"knative.dev/pkg/kmeta"
 
 	"go.uber.org/zap/zaptest"
 	istiolisters "istio.io/client-go/pkg/listers/networking/v1beta1"
 	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 	corev1listers "k8s.io/client-go/listers/core/v1"
+	discoveryv1listers "k8s.io/client-go/listers/discovery/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
 )
 
 func TestListProbeTargets(t *testing.T) {
 	tests := []struct {
-		name            string
-		ingress         *v1alpha1.Ingress
-		ingressGateways []config.Gateway
-		localGateways   []config.Gateway
-		gatewayLister   istiolisters.GatewayLister
-		endpointsLister corev1listers.EndpointsLister
-		serviceLister   corev1listers.ServiceLister
-		errMessage      string
-		results         []status.ProbeTarget
+		name                string
+		ingress             *v1alpha1.Ingress
+		ingressGateways     []config.Gateway
+		localGateways       []config.Gateway
+		gatewayLister       istiolisters.GatewayLister
+		endpointSliceLister discoveryv1listers.EndpointSliceLister
+		serviceLister       corev1listers.ServiceLister
+		errMessage          string
+		results             []status.ProbeTarget
 	}{{
 		name: "unqualified gateway",
 		ingressGateways: []config.Gateway{{
@@ -174,7 +183,7 @@ func TestListProbeTargets(t *testing.T) {
 				},
 			}},
 		},
-		endpointsLister: &fakeEndpointsLister{fails: true},
+		endpointSliceLister: &fakeEndpointSliceLister{fails: true},
 		ingress: &v1alpha1.Ingress{
 			ObjectMeta: metav1.ObjectMeta{
 				Namespace: "default",
@@ -189,7 +198,7 @@ func TestListProbeTargets(t *testing.T) {
 				}},
 			},
 		},
-		errMessage: "failed to get Endpoints",
+		errMessage: "failed to list EndpointSlices",
 	}, {
 		name: "service port not found",
 		ingressGateways: []config.Gateway{{
@@ -230,14 +239,7 @@ func TestListProbeTargets(t *testing.T) {
 				},
 			}},
 		},
-		endpointsLister: &fakeEndpointsLister{
-			endpointses: []*v1.Endpoints{{
-				ObjectMeta: metav1.ObjectMeta{
-					Namespace: "default",
-					Name:      "gateway",
-				},
-			}},
-		},
+		endpointSliceLister: &fakeEndpointSliceLister{},
 		ingress: &v1alpha1.Ingress{
 			ObjectMeta: metav1.ObjectMeta{
 				Namespace: "default",
@@ -292,15 +294,7 @@ func TestListProbeTargets(t *testing.T) {
 				},
 			}},
 		},
-		endpointsLister: &fakeEndpointsLister{
-			endpointses: []*v1.Endpoints{{
-				ObjectMeta: metav1.ObjectMeta{
-					Namespace: "default",
-					Name:      "gateway",
-				},
-				Subsets: []v1.EndpointSubset{},
-			}},
-		},
+		endpointSliceLister: &fakeEndpointSliceLister{},
 		ingress: &v1alpha1.Ingress{
 			ObjectMeta: metav1.ObjectMeta{
 				Namespace: "default",
@@ -341,8 +335,8 @@ func TestListProbeTargets(t *testing.T) {
 				},
 			}},
 		},
-		serviceLister:   &fakeServiceLister{},
-		endpointsLister: &fakeEndpointsLister{},
+		serviceLister:       &fakeServiceLister{},
+		endpointSliceLister: &fakeEndpointSliceLister{},
 		ingress: &v1alpha1.Ingress{
 			ObjectMeta: metav1.ObjectMeta{
 				Namespace: "default",
@@ -397,7 +391,7 @@ func TestListProbeTargets(t *testing.T) {
 				},
 			}},
 		},
-		endpointsLister: &fakeEndpointsLister{},
+		endpointSliceLister: &fakeEndpointSliceLister{},
 		ingress: &v1alpha1.Ingress{
 			ObjectMeta: metav1.ObjectMeta{
 				Namespace: "default",
@@ -439,23 +433,23 @@ func TestListProbeTargets(t *testing.T) {
 				},
 			}},
 		},
-		endpointsLister: &fakeEndpointsLister{
-			endpointses: []*v1.Endpoints{{
+		endpointSliceLister: &fakeEndpointSliceLister{
+			slices: []*discoveryv1.EndpointSlice{{
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: "default",
-					Name:      "gateway",
-				},
-				Subsets: []v1.EndpointSubset{{
-					Ports: []v1.EndpointPort{{
-						Name: "bogus",
-						Port: 8081,
-					}, {
-						Name: "real",
-						Port: 8080,
-					}},
-					Addresses: []v1.EndpointAddress{{
-						IP: "1.1.1.1",
-					}},
+					Name:      "gateway-1",
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "gateway"},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Ports: []discoveryv1.EndpointPort{{
+					Name: ptr.To("bogus"),
+					Port: ptr.To[int32](8081),
+				}, {
+					Name: ptr.To("real"),
+					Port: ptr.To[int32](8080),
+				}},
+				Endpoints: []discoveryv1.Endpoint{{
+					Addresses: []string{"1.1.1.1"},
 				}},
 			}},
 		},
@@ -526,23 +520,23 @@ func TestListProbeTargets(t *testing.T) {
 				},
 			}},
 		},
-		endpointsLister: &fakeEndpointsLister{
-			endpointses: []*v1.Endpoints{{
+		endpointSliceLister: &fakeEndpointSliceLister{
+			slices: []*discoveryv1.EndpointSlice{{
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: "default",
-					Name:      "gateway",
-				},
-				Subsets: []v1.EndpointSubset{{
-					Ports: []v1.EndpointPort{{
-						Name: "bogus",
-						Port: 8081,
-					}, {
-						Name: "real",
-						Port: 8443,
-					}},
-					Addresses: []v1.EndpointAddress{{
-						IP: "1.1.1.1",
-					}},
+					Name:      "gateway-1",
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "gateway"},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Ports: []discoveryv1.EndpointPort{{
+					Name: ptr.To("bogus"),
+					Port: ptr.To[int32](8081),
+				}, {
+					Name: ptr.To("real"),
+					Port: ptr.To[int32](8443),
+				}},
+				Endpoints: []discoveryv1.Endpoint{{
+					Addresses: []string{"1.1.1.1"},
 				}},
 			}},
 		},
@@ -616,23 +610,23 @@ func TestListProbeTargets(t *testing.T) {
 				},
 			}},
 		},
-		endpointsLister: &fakeEndpointsLister{
-			endpointses: []*v1.Endpoints{{
+		endpointSliceLister: &fakeEndpointSliceLister{
+			slices: []*discoveryv1.EndpointSlice{{
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: "default",
-					Name:      "gateway",
-				},
-				Subsets: []v1.EndpointSubset{{
-					Ports: []v1.EndpointPort{{
-						Name: "bogus",
-						Port: 8081,
-					}, {
-						Name: "real",
-						Port: 8443,
-					}},
-					Addresses: []v1.EndpointAddress{{
-						IP: "1.1.1.1",
-					}},
+					Name:      "gateway-1",
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "gateway"},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Ports: []discoveryv1.EndpointPort{{
+					Name: ptr.To("bogus"),
+					Port: ptr.To[int32](8081),
+				}, {
+					Name: ptr.To("real"),
+					Port: ptr.To[int32](8443),
+				}},
+				Endpoints: []discoveryv1.Endpoint{{
+					Addresses: []string{"1.1.1.1"},
 				}},
 			}},
 		},
@@ -707,23 +701,23 @@ func TestListProbeTargets(t *testing.T) {
 				},
 			}},
 		},
-		endpointsLister: &fakeEndpointsLister{
-			endpointses: []*v1.Endpoints{{
+		endpointSliceLister: &fakeEndpointSliceLister{
+			slices: []*discoveryv1.EndpointSlice{{
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: "default",
-					Name:      "gateway",
-				},
-				Subsets: []v1.EndpointSubset{{
-					Ports: []v1.EndpointPort{{
-						Name: "bogus",
-						Port: 8081,
-					}, {
-						Name: "real",
-						Port: 8080, // Different port number between Endpoint and Gateway Service.
-					}},
-					Addresses: []v1.EndpointAddress{{
-						IP: "1.1.1.1",
-					}},
+					Name:      "gateway-1",
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "gateway"},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Ports: []discoveryv1.EndpointPort{{
+					Name: ptr.To("bogus"),
+					Port: ptr.To[int32](8081),
+				}, {
+					Name: ptr.To("real"),
+					Port: ptr.To[int32](8080),
+				}},
+				Endpoints: []discoveryv1.Endpoint{{
+					Addresses: []string{"1.1.1.1"},
 				}},
 			}},
 		},
@@ -804,23 +798,23 @@ func TestListProbeTargets(t *testing.T) {
 				},
 			}},
 		},
-		endpointsLister: &fakeEndpointsLister{
-			endpointses: []*v1.Endpoints{{
+		endpointSliceLister: &fakeEndpointSliceLister{
+			slices: []*discoveryv1.EndpointSlice{{
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: "default",
-					Name:      "gateway",
-				},
-				Subsets: []v1.EndpointSubset{{
-					Ports: []v1.EndpointPort{{
-						Name: "bogus",
-						Port: 8080,
-					}, {
-						Name: "real",
-						Port: 80,
-					}},
-					Addresses: []v1.EndpointAddress{{
-						IP: "1.1.1.1",
-					}},
+					Name:      "gateway-1",
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "gateway"},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Ports: []discoveryv1.EndpointPort{{
+					Name: ptr.To("bogus"),
+					Port: ptr.To[int32](8080),
+				}, {
+					Name: ptr.To("real"),
+					Port: ptr.To[int32](80),
+				}},
+				Endpoints: []discoveryv1.Endpoint{{
+					Addresses: []string{"1.1.1.1"},
 				}},
 			}},
 		},
@@ -886,23 +880,23 @@ func TestListProbeTargets(t *testing.T) {
 				},
 			}},
 		},
-		endpointsLister: &fakeEndpointsLister{
-			endpointses: []*v1.Endpoints{{
+		endpointSliceLister: &fakeEndpointSliceLister{
+			slices: []*discoveryv1.EndpointSlice{{
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: "default",
-					Name:      "gateway",
-				},
-				Subsets: []v1.EndpointSubset{{
-					Ports: []v1.EndpointPort{{
-						Name: "bogus",
-						Port: 8080,
-					}, {
-						Name: "real",
-						Port: 80,
-					}},
-					Addresses: []v1.EndpointAddress{{
-						IP: "1.1.1.1",
-					}},
+					Name:      "gateway-1",
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "gateway"},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Ports: []discoveryv1.EndpointPort{{
+					Name: ptr.To("bogus"),
+					Port: ptr.To[int32](8080),
+				}, {
+					Name: ptr.To("real"),
+					Port: ptr.To[int32](80),
+				}},
+				Endpoints: []discoveryv1.Endpoint{{
+					Addresses: []string{"1.1.1.1"},
 				}},
 			}},
 		},
@@ -968,20 +962,20 @@ func TestListProbeTargets(t *testing.T) {
 				},
 			}},
 		},
-		endpointsLister: &fakeEndpointsLister{
-			endpointses: []*v1.Endpoints{{
+		endpointSliceLister: &fakeEndpointSliceLister{
+			slices: []*discoveryv1.EndpointSlice{{
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: "default",
-					Name:      "gateway",
+					Name:      "gateway-1",
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "gateway"},
 				},
-				Subsets: []v1.EndpointSubset{{
-					Ports: []v1.EndpointPort{{
-						Name: "unknown",
-						Port: 9999,
-					}},
-					Addresses: []v1.EndpointAddress{{
-						IP: "1.1.1.1",
-					}},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Ports: []discoveryv1.EndpointPort{{
+					Name: ptr.To("unknown"),
+					Port: ptr.To[int32](9999),
+				}},
+				Endpoints: []discoveryv1.Endpoint{{
+					Addresses: []string{"1.1.1.1"},
 				}},
 			}},
 		},
@@ -1068,42 +1062,192 @@ func TestListProbeTargets(t *testing.T) {
 				},
 			}},
 		},
-		endpointsLister: &fakeEndpointsLister{
-			endpointses: []*v1.Endpoints{{
+		endpointSliceLister: &fakeEndpointSliceLister{
+			slices: []*discoveryv1.EndpointSlice{{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "gateway-1",
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "gateway"},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Ports: []discoveryv1.EndpointPort{{
+					Name: ptr.To("bogus"),
+					Port: ptr.To[int32](8080),
+				}, {
+					Name: ptr.To("real"),
+					Port: ptr.To[int32](80),
+				}},
+				Endpoints: []discoveryv1.Endpoint{{
+					Addresses: []string{"1.1.1.1"},
+				}},
+			}, {
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "gateway-two-1",
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "gateway-two"},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Ports: []discoveryv1.EndpointPort{{
+					Name: ptr.To("bogus"),
+					Port: ptr.To[int32](8080),
+				}, {
+					Name: ptr.To("real"),
+					Port: ptr.To[int32](90),
+				}},
+				Endpoints: []discoveryv1.Endpoint{{
+					Addresses: []string{"2.2.2.2"},
+				}, {
+					Addresses: []string{"2.2.2.3"},
+				}},
+			}},
+		},
+		serviceLister: &fakeServiceLister{
+			services: []*v1.Service{{
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: "default",
 					Name:      "gateway",
+					Labels: map[string]string{
+						"gwt": "istio",
+					},
 				},
-				Subsets: []v1.EndpointSubset{{
-					Ports: []v1.EndpointPort{{
+				Spec: v1.ServiceSpec{
+					Ports: []v1.ServicePort{{
 						Name: "bogus",
 						Port: 8080,
 					}, {
 						Name: "real",
 						Port: 80,
 					}},
-					Addresses: []v1.EndpointAddress{{
-						IP: "1.1.1.1",
-					}},
-				}},
+				},
 			}, {
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: "default",
 					Name:      "gateway-two",
+					Labels: map[string]string{
+						"gwt": "gateway-two",
+					},
 				},
-				Subsets: []v1.EndpointSubset{{
-					Ports: []v1.EndpointPort{{
+				Spec: v1.ServiceSpec{
+					Ports: []v1.ServicePort{{
 						Name: "bogus",
 						Port: 8080,
 					}, {
 						Name: "real",
 						Port: 90,
 					}},
-					Addresses: []v1.EndpointAddress{{
-						IP: "2.2.2.2",
-					}, {
-						IP: "2.2.2.3",
+				},
+			}},
+		},
+		ingress: &v1alpha1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "whatever",
+			},
+			Spec: v1alpha1.IngressSpec{
+				Rules: []v1alpha1.IngressRule{{
+					Hosts: []string{
+						"foo.bar.com",
+					},
+					Visibility: v1alpha1.IngressVisibilityExternalIP,
+				}},
+			},
+		},
+		results: []status.ProbeTarget{{
+			PodIPs:  sets.New("1.1.1.1"),
+			PodPort: "80",
+			Port:    "80",
+			URLs:    []*url.URL{{Scheme: "http", Host: "foo.bar.com:80"}},
+		}, {
+			PodIPs:  sets.New("2.2.2.2", "2.2.2.3"),
+			PodPort: "90",
+			Port:    "90",
+			URLs:    []*url.URL{{Scheme: "http", Host: "foo.bar.com:90"}},
+		}},
+	}, {
+		name: "two gateways, hosts placed by domain",
+		ingressGateways: []config.Gateway{{
+			Name:      "gateway",
+			Namespace: "default",
+		}, {
+			Name:      "gateway-two",
+			Namespace: "default",
+			Domains:   []string{"*.internal.bar.com"},
+		}},
+		gatewayLister: &fakeGatewayLister{
+			gateways: []*v1beta1.Gateway{{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "gateway",
+				},
+				Spec: istiov1beta1.Gateway{
+					Servers: []*istiov1beta1.Server{{
+						Hosts: []string{"*"},
+						Port: &istiov1beta1.Port{
+							Name:     "http",
+							Number:   80,
+							Protocol: "HTTP",
+						},
+					}},
+					Selector: map[string]string{
+						"gwt": "istio",
+					},
+				},
+			}, {
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "gateway-two",
+				},
+				Spec: istiov1beta1.Gateway{
+					Servers: []*istiov1beta1.Server{{
+						Hosts: []string{"*"},
+						Port: &istiov1beta1.Port{
+							Name:     "http",
+							Number:   90,
+							Protocol: "HTTP",
+						},
 					}},
+					Selector: map[string]string{
+						"gwt": "gateway-two",
+					},
+				},
+			}},
+		},
+		endpointSliceLister: &fakeEndpointSliceLister{
+			slices: []*discoveryv1.EndpointSlice{{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "gateway-1",
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "gateway"},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Ports: []discoveryv1.EndpointPort{{
+					Name: ptr.To("bogus"),
+					Port: ptr.To[int32](8080),
+				}, {
+					Name: ptr.To("real"),
+					Port: ptr.To[int32](80),
+				}},
+				Endpoints: []discoveryv1.Endpoint{{
+					Addresses: []string{"1.1.1.1"},
+				}},
+			}, {
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "gateway-two-1",
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "gateway-two"},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Ports: []discoveryv1.EndpointPort{{
+					Name: ptr.To("bogus"),
+					Port: ptr.To[int32](8080),
+				}, {
+					Name: ptr.To("real"),
+					Port: ptr.To[int32](90),
+				}},
+				Endpoints: []discoveryv1.Endpoint{{
+					Addresses: []string{"2.2.2.2"},
+				}, {
+					Addresses: []string{"2.2.2.3"},
 				}},
 			}},
 		},
@@ -1153,6 +1297,7 @@ func TestListProbeTargets(t *testing.T) {
 				Rules: []v1alpha1.IngressRule{{
 					Hosts: []string{
 						"foo.bar.com",
+						"foo.internal.bar.com",
 					},
 					Visibility: v1alpha1.IngressVisibilityExternalIP,
 				}},
@@ -1167,7 +1312,7 @@ func TestListProbeTargets(t *testing.T) {
 			PodIPs:  sets.New("2.2.2.2", "2.2.2.3"),
 			PodPort: "90",
 			Port:    "90",
-			URLs:    []*url.URL{{Scheme: "http", Host: "foo.bar.com:90"}},
+			URLs:    []*url.URL{{Scheme: "http", Host: "foo.internal.bar.com:90"}},
 		}},
 	}, {
 		name: "pick first host",
@@ -1196,20 +1341,20 @@ func TestListProbeTargets(t *testing.T) {
 				},
 			}},
 		},
-		endpointsLister: &fakeEndpointsLister{
-			endpointses: []*v1.Endpoints{{
+		endpointSliceLister: &fakeEndpointSliceLister{
+			slices: []*discoveryv1.EndpointSlice{{
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: "default",
-					Name:      "ingress-gateway",
+					Name:      "ingress-gateway-1",
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "ingress-gateway"},
 				},
-				Subsets: []v1.EndpointSubset{{
-					Ports: []v1.EndpointPort{{
-						Name: "real",
-						Port: 80,
-					}},
-					Addresses: []v1.EndpointAddress{{
-						IP: "1.1.1.1",
-					}},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Ports: []discoveryv1.EndpointPort{{
+					Name: ptr.To("real"),
+					Port: ptr.To[int32](80),
+				}},
+				Endpoints: []discoveryv1.Endpoint{{
+					Addresses: []string{"1.1.1.1"},
 				}},
 			}},
 		},
@@ -1305,42 +1450,42 @@ func TestListProbeTargets(t *testing.T) {
 				},
 			}},
 		},
-		endpointsLister: &fakeEndpointsLister{
-			endpointses: []*v1.Endpoints{{
+		endpointSliceLister: &fakeEndpointSliceLister{
+			slices: []*discoveryv1.EndpointSlice{{
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: "default",
-					Name:      "gateway",
-				},
-				Subsets: []v1.EndpointSubset{{
-					Ports: []v1.EndpointPort{{
-						Name: "bogus",
-						Port: 8080,
-					}, {
-						Name: "real",
-						Port: 80,
-					}},
-					Addresses: []v1.EndpointAddress{{
-						IP: "1.1.1.1",
-					}},
+					Name:      "gateway-1",
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "gateway"},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Ports: []discoveryv1.EndpointPort{{
+					Name: ptr.To("bogus"),
+					Port: ptr.To[int32](8080),
+				}, {
+					Name: ptr.To("real"),
+					Port: ptr.To[int32](80),
+				}},
+				Endpoints: []discoveryv1.Endpoint{{
+					Addresses: []string{"1.1.1.1"},
 				}},
 			}, {
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: "default",
-					Name:      "local-gateway",
-				},
-				Subsets: []v1.EndpointSubset{{
-					Ports: []v1.EndpointPort{{
-						Name: "bogus",
-						Port: 8080,
-					}, {
-						Name: "real",
-						Port: 80,
-					}},
-					Addresses: []v1.EndpointAddress{{
-						IP: "2.2.2.2",
-					}, {
-						IP: "2.2.2.3",
-					}},
+					Name:      "local-gateway-1",
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "local-gateway"},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Ports: []discoveryv1.EndpointPort{{
+					Name: ptr.To("bogus"),
+					Port: ptr.To[int32](8080),
+				}, {
+					Name: ptr.To("real"),
+					Port: ptr.To[int32](80),
+				}},
+				Endpoints: []discoveryv1.Endpoint{{
+					Addresses: []string{"2.2.2.2"},
+				}, {
+					Addresses: []string{"2.2.2.3"},
 				}},
 			}},
 		},
@@ -1437,23 +1582,23 @@ func TestListProbeTargets(t *testing.T) {
 				},
 			}},
 		},
-		endpointsLister: &fakeEndpointsLister{
-			endpointses: []*v1.Endpoints{{
+		endpointSliceLister: &fakeEndpointSliceLister{
+			slices: []*discoveryv1.EndpointSlice{{
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: "default",
-					Name:      "gateway",
-				},
-				Subsets: []v1.EndpointSubset{{
-					Ports: []v1.EndpointPort{{
-						Name: "bogus",
-						Port: 8080,
-					}, {
-						Name: "real",
-						Port: 80,
-					}},
-					Addresses: []v1.EndpointAddress{{
-						IP: "1.1.1.1",
-					}},
+					Name:      "gateway-1",
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "gateway"},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Ports: []discoveryv1.EndpointPort{{
+					Name: ptr.To("bogus"),
+					Port: ptr.To[int32](8080),
+				}, {
+					Name: ptr.To("real"),
+					Port: ptr.To[int32](80),
+				}},
+				Endpoints: []discoveryv1.Endpoint{{
+					Addresses: []string{"1.1.1.1"},
 				}},
 			}},
 		},
@@ -1557,23 +1702,23 @@ func TestListProbeTargets(t *testing.T) {
 				},
 			},
 		},
-		endpointsLister: &fakeEndpointsLister{
-			endpointses: []*v1.Endpoints{{
+		endpointSliceLister: &fakeEndpointSliceLister{
+			slices: []*discoveryv1.EndpointSlice{{
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: "default",
-					Name:      "gateway-matching",
-				},
-				Subsets: []v1.EndpointSubset{{
-					Ports: []v1.EndpointPort{{
-						Name: "bogus",
-						Port: 8081,
-					}, {
-						Name: "real",
-						Port: 8080,
-					}},
-					Addresses: []v1.EndpointAddress{{
-						IP: "1.1.1.1",
-					}},
+					Name:      "gateway-matching-1",
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "gateway-matching"},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Ports: []discoveryv1.EndpointPort{{
+					Name: ptr.To("bogus"),
+					Port: ptr.To[int32](8081),
+				}, {
+					Name: ptr.To("real"),
+					Port: ptr.To[int32](8080),
+				}},
+				Endpoints: []discoveryv1.Endpoint{{
+					Addresses: []string{"1.1.1.1"},
 				}},
 			}},
 		},
@@ -1625,10 +1770,10 @@ func TestListProbeTargets(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			lister := gatewayPodTargetLister{
-				logger:          zaptest.NewLogger(t).Sugar(),
-				gatewayLister:   test.gatewayLister,
-				endpointsLister: test.endpointsLister,
-				serviceLister:   test.serviceLister,
+				logger:              zaptest.NewLogger(t).Sugar(),
+				gatewayLister:       test.gatewayLister,
+				endpointSliceLister: test.endpointSliceLister,
+				serviceLister:       test.serviceLister,
 			}
 			ctx := config.ToContext(context.Background(), &config.Config{
 				Istio: &config.Istio{
@@ -1659,10 +1804,10 @@ func TestListProbeTargets(t *testing.T) {
 
 func TestListProbeTargets_GatewaysDisabledViaConfig(t *testing.T) {
 	lister := gatewayPodTargetLister{
-		logger:          zaptest.NewLogger(t).Sugar(),
-		gatewayLister:   &fakeGatewayLister{},
-		endpointsLister: &fakeEndpointsLister{},
-		serviceLister:   &fakeServiceLister{},
+		logger:              zaptest.NewLogger(t).Sugar(),
+		gatewayLister:       &fakeGatewayLister{},
+		endpointSliceLister: &fakeEndpointSliceLister{},
+		serviceLister:       &fakeServiceLister{},
 	}
 	ctx := config.ToContext(context.Background(), &config.Config{
 		Istio: &config.Istio{
@@ -1705,42 +1850,495 @@ func TestListProbeTargets_GatewaysDisabledViaConfig(t *testing.T) {
 	}
 }
 
-type fakeGatewayLister struct {
-	gateways []*v1beta1.Gateway
-	fails    bool
-}
-
-func (l *fakeGatewayLister) Gateways(namespace string) istiolisters.GatewayNamespaceLister {
-	if l.fails {
-		return &fakeGatewayNamespaceLister{fails: true}
-	}
-
-	var matches []*v1beta1.Gateway
-	for _, gateway := range l.gateways {
-		if gateway.Namespace == namespace {
-			matches = append(matches, gateway)
-		}
-	}
-	return &fakeGatewayNamespaceLister{
-		gateways: matches,
-	}
-}
-
-func (l *fakeGatewayLister) List(_ labels.Selector) ([]*v1beta1.Gateway, error) {
-	log.Panic("not implemented")
-	return nil, nil
+func TestListProbeTargets_ProbeHosts(t *testing.T) {
+	lister := gatewayPodTargetLister{
+		logger: zaptest.NewLogger(t).Sugar(),
+		gatewayLister: &fakeGatewayLister{
+			gateways: []*v1beta1.Gateway{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gateway"},
+				Spec: istiov1beta1.Gateway{
+					Servers: []*istiov1beta1.Server{{
+						Hosts: []string{"*"},
+						Port:  &istiov1beta1.Port{Name: "http", Number: 80, Protocol: "HTTP"},
+					}},
+					Selector: map[string]string{"gwt": "istio"},
+				},
+			}},
+		},
+		endpointSliceLister: &fakeEndpointSliceLister{
+			slices: []*discoveryv1.EndpointSlice{{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "gateway-1",
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "gateway"},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Ports:       []discoveryv1.EndpointPort{{Name: ptr.To("http"), Port: ptr.To[int32](8080)}},
+				Endpoints:   []discoveryv1.Endpoint{{Addresses: []string{"1.1.1.1"}}},
+			}},
+		},
+		serviceLister: &fakeServiceLister{
+			services: []*v1.Service{{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "gateway",
+					Labels:    map[string]string{"gwt": "istio"},
+				},
+				Spec: v1.ServiceSpec{
+					Ports: []v1.ServicePort{{Name: "http", Port: 80}},
+				},
+			}},
+		},
+	}
+	ing := &v1alpha1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "whatever"},
+		Spec: v1alpha1.IngressSpec{
+			Rules: []v1alpha1.IngressRule{{
+				Hosts:      []string{"foo.bar.com", "baz.bar.com", "qux.bar.com"},
+				Visibility: v1alpha1.IngressVisibilityExternalIP,
+			}},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		probeHosts int
+		want       []string
+	}{{
+		name: "single host",
+		want: []string{"baz.bar.com"},
+	}, {
+		name:       "all hosts",
+		probeHosts: config.ProbeAllHosts,
+		want:       []string{"baz.bar.com", "foo.bar.com", "qux.bar.com"},
+	}, {
+		name:       "more hosts than the Ingress has",
+		probeHosts: 5,
+		want:       []string{"baz.bar.com", "foo.bar.com", "qux.bar.com"},
+	}, {
+		name:       "sample of hosts",
+		probeHosts: 2,
+		want:       probedHosts(sets.New("foo.bar.com", "baz.bar.com", "qux.bar.com"), 2),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := config.ToContext(context.Background(), &config.Config{
+				Istio: &config.Istio{
+					IngressGateways: []config.Gateway{{Namespace: "default", Name: "gateway"}},
+					ProbeHosts:      test.probeHosts,
+				},
+			})
+			results, err := lister.ListProbeTargets(ctx, ing)
+			if err != nil {
+				t.Fatal("ListProbeTargets() =", err)
+			}
+			if len(results) != 1 {
+				t.Fatalf("ListProbeTargets() = %d targets, want 1", len(results))
+			}
+			got := make([]string, 0, len(results[0].URLs))
+			for _, u := range results[0].URLs {
+				got = append(got, u.Hostname())
+			}
+			if !cmp.Equal(got, test.want) {
+				t.Error("Probed hosts (-want, +got):", cmp.Diff(test.want, got))
+			}
+		})
+	}
 }
 
-type fakeGatewayNamespaceLister struct {
+func TestProbedHosts(t *testing.T) {
+	hosts := sets.New[string]()
+	for i := range 50 {
+		hosts.Insert(fmt.Sprintf("host-%d.example.com", i))
+	}
+
+	sample := probedHosts(hosts, 10)
+	if len(sample) != 10 {
+		t.Fatalf("probedHosts() = %d hosts, want 10", len(sample))
+	}
+	if !sort.StringsAreSorted(sample) {
+		t.Errorf("probedHosts() = %v, want sorted hosts", sample)
+	}
+	if !hosts.HasAll(sample...) {
+		t.Errorf("probedHosts() = %v, want a subset of the hosts", sample)
+	}
+	// The sample is stable, and doesn't change with the hosts that are not part of it.
+	others := hosts.Clone().Delete(sample...)
+	others.Delete(sets.List(others)[:20]...)
+	if got := probedHosts(others.Insert(sample...), 10); !cmp.Equal(got, sample) {
+		t.Error("Sample (-want, +got):", cmp.Diff(sample, got))
+	}
+}
+
+func TestListProbeTargets_HTTP3(t *testing.T) {
+	gatewayLister := &fakeGatewayLister{
+		gateways: []*v1beta1.Gateway{{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "gateway",
+			},
+			Spec: istiov1beta1.Gateway{
+				Servers: []*istiov1beta1.Server{{
+					Hosts: []string{"*"},
+					Port: &istiov1beta1.Port{
+						Number:   80,
+						Protocol: "HTTP",
+					},
+				}},
+				Selector: map[string]string{
+					"gwt": "istio",
+				},
+			},
+		}},
+	}
+	ing := &v1alpha1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "whatever",
+		},
+		Spec: v1alpha1.IngressSpec{
+			Rules: []v1alpha1.IngressRule{{
+				Hosts:      []string{"foo.bar.com"},
+				Visibility: v1alpha1.IngressVisibilityExternalIP,
+			}},
+			TLS: []v1alpha1.IngressTLS{{
+				Hosts:           []string{"foo.bar.com"},
+				SecretName:      "secret",
+				SecretNamespace: "default",
+			}},
+		},
+	}
+	service := func(ports ...v1.ServicePort) *fakeServiceLister {
+		return &fakeServiceLister{
+			services: []*v1.Service{{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "istio-system",
+					Name:      "gateway",
+					Labels: map[string]string{
+						"gwt": "istio",
+					},
+				},
+				Spec: v1.ServiceSpec{
+					Ports: append([]v1.ServicePort{{
+						Name: "http",
+						Port: 80,
+					}}, ports...),
+				},
+			}},
+		}
+	}
+	endpoints := func(ports ...discoveryv1.EndpointPort) *fakeEndpointSliceLister {
+		return &fakeEndpointSliceLister{
+			slices: []*discoveryv1.EndpointSlice{{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "istio-system",
+					Name:      "gateway-1",
+					Labels:    map[string]string{discoveryv1.LabelServiceName: "gateway"},
+				},
+				AddressType: discoveryv1.AddressTypeIPv4,
+				Ports: append([]discoveryv1.EndpointPort{{
+					Name: ptr.To("http"),
+					Port: ptr.To[int32](8080),
+				}}, ports...),
+				Endpoints: []discoveryv1.Endpoint{{
+					Addresses: []string{"1.1.1.1"},
+				}},
+			}},
+		}
+	}
+	results := []status.ProbeTarget{{
+		PodIPs:  sets.New("1.1.1.1"),
+		PodPort: "8080",
+		Port:    "80",
+		URLs:    []*url.URL{{Scheme: "http", Host: "foo.bar.com:80"}},
+	}}
+
+	tests := []struct {
+		name                string
+		enableHTTP3         bool
+		ingress             *v1alpha1.Ingress
+		endpointSliceLister discoveryv1listers.EndpointSliceLister
+		serviceLister       corev1listers.ServiceLister
+		errMessage          string
+		results             []status.ProbeTarget
+	}{{
+		name:                "http3 disabled",
+		ingress:             ing,
+		serviceLister:       service(),
+		endpointSliceLister: endpoints(),
+		results:             results,
+	}, {
+		name:                "no UDP service port",
+		enableHTTP3:         true,
+		ingress:             ing,
+		serviceLister:       service(v1.ServicePort{Name: "https", Port: 443, Protocol: v1.ProtocolTCP}),
+		endpointSliceLister: endpoints(),
+		errMessage:          "doesn't expose port 443 over UDP",
+	}, {
+		name:                "no UDP endpoint port",
+		enableHTTP3:         true,
+		ingress:             ing,
+		serviceLister:       service(v1.ServicePort{Name: "http3", Port: 443, Protocol: v1.ProtocolUDP}),
+		endpointSliceLister: endpoints(),
+		errMessage:          `listens on UDP port "http3"`,
+	}, {
+		name:                "UDP port exposed on ready endpoints",
+		enableHTTP3:         true,
+		ingress:             ing,
+		serviceLister:       service(v1.ServicePort{Name: "http3", Port: 443, Protocol: v1.ProtocolUDP}),
+		endpointSliceLister: endpoints(discoveryv1.EndpointPort{Name: ptr.To("http3"), Port: ptr.To[int32](8443), Protocol: ptr.To(v1.ProtocolUDP)}),
+		results:             results,
+	}, {
+		name:        "Ingress without TLS",
+		enableHTTP3: true,
+		ingress: func() *v1alpha1.Ingress {
+			ing := ing.DeepCopy()
+			ing.Spec.TLS = nil
+			return ing
+		}(),
+		serviceLister:       service(),
+		endpointSliceLister: endpoints(),
+		results:             results,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lister := gatewayPodTargetLister{
+				logger:              zaptest.NewLogger(t).Sugar(),
+				gatewayLister:       gatewayLister,
+				endpointSliceLister: test.endpointSliceLister,
+				serviceLister:       test.serviceLister,
+			}
+			ctx := config.ToContext(context.Background(), &config.Config{
+				Istio: &config.Istio{
+					IngressGateways: []config.Gateway{{
+						Name:      "gateway",
+						Namespace: "default",
+					}},
+					EnableHTTP3: test.enableHTTP3,
+				},
+			})
+			results, err := lister.ListProbeTargets(ctx, test.ingress)
+			if (err != nil) != (test.errMessage != "") {
+				t.Fatalf("ListProbeTargets() error = %v, want %q", err, test.errMessage)
+			}
+			if err != nil && !strings.Contains(err.Error(), test.errMessage) {
+				t.Fatalf("expected error message %q, saw %v", test.errMessage, err)
+			}
+			// The Ingress is reported not ready rather than failing its reconciliation.
+			if err != nil && !errors.As(err, new(*http3PortNotExposedError)) {
+				t.Errorf("ListProbeTargets() = %v, want an http3PortNotExposedError", err)
+			}
+			if diff := cmp.Diff(test.results, results, cmpopts.EquateEmpty()); diff != "" {
+				t.Error("Unexpected probe targets (-want +got):", diff)
+			}
+		})
+	}
+}
+
+func TestMarkHTTP3PortNotExposed(t *testing.T) {
+	ing := &v1alpha1.Ingress{}
+	ing.Status.InitializeConditions()
+	if markHTTP3PortNotExposed(ing, errors.New("failed to list Services")) {
+		t.Error("markHTTP3PortNotExposed() = true for another error")
+	}
+
+	err := fmt.Errorf("probing failed: %w", &http3PortNotExposedError{
+		Gateway: "istio-system/gateway",
+		Err:     errors.New("no UDP port"),
+	})
+	if !markHTTP3PortNotExposed(ing, err) {
+		t.Fatal("markHTTP3PortNotExposed() = false, want true")
+	}
+	cond := ing.Status.GetCondition(v1alpha1.IngressConditionLoadBalancerReady)
+	want := `the HTTP/3 port is not exposed for the pods of Gateway "istio-system/gateway": no UDP port`
+	if cond.Status != v1.ConditionUnknown || cond.Reason != http3PortNotExposedReason || cond.Message != want {
+		t.Errorf("LoadBalancerReady = %#v, want unknown with message %q", cond, want)
+	}
+}
+
+func TestListPassthroughProbeTargets(t *testing.T) {
+	ing := &v1alpha1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "whatever",
+			UID:       "whatever-uid",
+		},
+	}
+	passthroughGateway := &v1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "default",
+			Name:            "whatever-gateway",
+			Labels:          map[string]string{networking.IngressLabelKey: "whatever"},
+			OwnerReferences: []metav1.OwnerReference{*kmeta.NewControllerRef(ing)},
+		},
+		Spec: istiov1beta1.Gateway{
+			Servers: []*istiov1beta1.Server{{
+				Hosts: []string{"foo.bar.com", "baz.bar.com"},
+				Port: &istiov1beta1.Port{
+					Name:     "default/whatever:0",
+					Number:   443,
+					Protocol: "TLS",
+				},
+				Tls: &istiov1beta1.ServerTLSSettings{
+					Mode: istiov1beta1.ServerTLSSettings_PASSTHROUGH,
+				},
+			}, {
+				Hosts: []string{"*"},
+				Port: &istiov1beta1.Port{
+					Number:   80,
+					Protocol: "HTTP",
+				},
+			}},
+			Selector: map[string]string{
+				"gwt": "istio",
+			},
+		},
+	}
+	// Gateways carrying the label without being controlled by the Ingress are ignored.
+	foreignGateway := passthroughGateway.DeepCopy()
+	foreignGateway.Name = "foreign-gateway"
+	foreignGateway.OwnerReferences = nil
+
+	serviceLister := &fakeServiceLister{
+		services: []*v1.Service{{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "istio-system",
+				Name:      "gateway",
+				Labels: map[string]string{
+					"gwt": "istio",
+				},
+			},
+			Spec: v1.ServiceSpec{
+				Ports: []v1.ServicePort{{
+					Name: "http",
+					Port: 80,
+				}, {
+					Name: "https",
+					Port: 443,
+				}},
+			},
+		}},
+	}
+	endpointSliceLister := &fakeEndpointSliceLister{
+		slices: []*discoveryv1.EndpointSlice{{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "istio-system",
+				Name:      "gateway-1",
+				Labels:    map[string]string{discoveryv1.LabelServiceName: "gateway"},
+			},
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Ports: []discoveryv1.EndpointPort{{
+				Name: ptr.To("http"),
+				Port: ptr.To[int32](8080),
+			}, {
+				Name: ptr.To("https"),
+				Port: ptr.To[int32](8443),
+			}},
+			Endpoints: []discoveryv1.Endpoint{{
+				Addresses: []string{"1.1.1.1"},
+			}},
+		}},
+	}
+
+	tests := []struct {
+		name          string
+		gatewayLister istiolisters.GatewayLister
+		errMessage    string
+		results       []status.ProbeTarget
+	}{{
+		name:          "gateway error",
+		gatewayLister: &fakeGatewayLister{fails: true},
+		errMessage:    "failed to list Gateways",
+	}, {
+		name:          "no gateways",
+		gatewayLister: &fakeGatewayLister{},
+		results:       []status.ProbeTarget{},
+	}, {
+		name: "passthrough server",
+		gatewayLister: &fakeGatewayLister{
+			gateways: []*v1beta1.Gateway{passthroughGateway, foreignGateway},
+		},
+		results: []status.ProbeTarget{{
+			PodIPs:  sets.New("1.1.1.1"),
+			PodPort: "8443",
+			Port:    "443",
+			URLs: []*url.URL{
+				{Scheme: "tls", Host: "foo.bar.com:443"},
+				{Scheme: "tls", Host: "baz.bar.com:443"},
+			},
+		}},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lister := gatewayPodTargetLister{
+				logger:              zaptest.NewLogger(t).Sugar(),
+				gatewayLister:       test.gatewayLister,
+				endpointSliceLister: endpointSliceLister,
+				serviceLister:       serviceLister,
+			}
+			ctx := config.ToContext(context.Background(), &config.Config{Istio: &config.Istio{}})
+			results, err := lister.ListPassthroughProbeTargets(ctx, ing)
+			if (err != nil) != (test.errMessage != "") {
+				t.Fatalf("ListPassthroughProbeTargets() error = %v, want %q", err, test.errMessage)
+			}
+			if err != nil && !strings.Contains(err.Error(), test.errMessage) {
+				t.Fatalf("expected error message %q, saw %v", test.errMessage, err)
+			}
+			if diff := cmp.Diff(test.results, results); diff != "" {
+				t.Error("Unexpected probe targets (-want +got):", diff)
+			}
+		})
+	}
+}
+
+type fakeGatewayLister struct {
 	gateways []*v1beta1.Gateway
 	fails    bool
 }
 
-func (l *fakeGatewayNamespaceLister) List(_ labels.Selector) ([]*v1beta1.Gateway, error) {
+func (l *fakeGatewayLister) Gateways(namespace string) istiolisters.GatewayNamespaceLister {
+	if l.fails {
+		return &fakeGatewayNamespaceLister{fails: true}
+	}
+
+	var matches []*v1beta1.Gateway
+	for _, gateway := range l.gateways {
+		if gateway.Namespace == namespace {
+			matches = append(matches, gateway)
+		}
+	}
+	return &fakeGatewayNamespaceLister{
+		gateways: matches,
+	}
+}
+
+func (l *fakeGatewayLister) List(_ labels.Selector) ([]*v1beta1.Gateway, error) {
 	log.Panic("not implemented")
 	return nil, nil
 }
 
+type fakeGatewayNamespaceLister struct {
+	gateways []*v1beta1.Gateway
+	fails    bool
+}
+
+func (l *fakeGatewayNamespaceLister) List(selector labels.Selector) ([]*v1beta1.Gateway, error) {
+	if l.fails {
+		return nil, errors.New("failed to list Gateways")
+	}
+
+	results := []*v1beta1.Gateway{}
+	for _, gateway := range l.gateways {
+		if selector.Matches(labels.Set(gateway.Labels)) {
+			results = append(results, gateway)
+		}
+	}
+	return results, nil
+}
+
 func (l *fakeGatewayNamespaceLister) Get(name string) (*v1beta1.Gateway, error) {
 	if l.fails {
 		return nil, errors.New("failed to get Gateway")
@@ -1754,31 +2352,586 @@ func (l *fakeGatewayNamespaceLister) Get(name string) (*v1beta1.Gateway, error)
 	return nil, errors.New("not found")
 }
 
-type fakeEndpointsLister struct {
-	// Golum, golum.
-	endpointses []*v1.Endpoints
-	fails       bool
+func TestListTLSProbeTargets(t *testing.T) {
+	ing := &v1alpha1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "whatever",
+			UID:       "whatever-uid",
+		},
+	}
+	tlsGateway := &v1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "default",
+			Name:            "whatever-gateway",
+			Labels:          map[string]string{networking.IngressLabelKey: "whatever"},
+			OwnerReferences: []metav1.OwnerReference{*kmeta.NewControllerRef(ing)},
+		},
+		Spec: istiov1beta1.Gateway{
+			Servers: []*istiov1beta1.Server{{
+				Hosts: []string{"foo.bar.com"},
+				Port: &istiov1beta1.Port{
+					Name:     "default/whatever:0",
+					Number:   443,
+					Protocol: "HTTPS",
+				},
+				Tls: &istiov1beta1.ServerTLSSettings{
+					Mode:           istiov1beta1.ServerTLSSettings_SIMPLE,
+					CredentialName: "whatever-secret-uid",
+				},
+			}, {
+				Hosts: []string{"baz.bar.com"},
+				Port: &istiov1beta1.Port{
+					Name:     "default/whatever:1",
+					Number:   443,
+					Protocol: "HTTPS",
+				},
+				Tls: &istiov1beta1.ServerTLSSettings{
+					Mode:           istiov1beta1.ServerTLSSettings_SIMPLE,
+					CredentialName: "unwatched-secret",
+				},
+			}, {
+				Hosts: []string{"*"},
+				Port: &istiov1beta1.Port{
+					Name:     "http-server",
+					Number:   80,
+					Protocol: "HTTP",
+				},
+			}},
+			Selector: map[string]string{
+				"gwt": "istio",
+			},
+		},
+	}
+	// Gateways carrying the label without being controlled by the Ingress are ignored.
+	foreignGateway := tlsGateway.DeepCopy()
+	foreignGateway.Name = "foreign-gateway"
+	foreignGateway.OwnerReferences = nil
+
+	serviceLister := &fakeServiceLister{
+		services: []*v1.Service{{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "istio-system",
+				Name:      "gateway",
+				Labels: map[string]string{
+					"gwt": "istio",
+				},
+			},
+			Spec: v1.ServiceSpec{
+				Ports: []v1.ServicePort{{
+					Name: "http",
+					Port: 80,
+				}, {
+					Name: "https",
+					Port: 443,
+				}},
+			},
+		}},
+	}
+	endpointSliceLister := &fakeEndpointSliceLister{
+		slices: []*discoveryv1.EndpointSlice{{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "istio-system",
+				Name:      "gateway-1",
+				Labels:    map[string]string{discoveryv1.LabelServiceName: "gateway"},
+			},
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Ports: []discoveryv1.EndpointPort{{
+				Name: ptr.To("http"),
+				Port: ptr.To[int32](8080),
+			}, {
+				Name: ptr.To("https"),
+				Port: ptr.To[int32](8443),
+			}},
+			Endpoints: []discoveryv1.Endpoint{{
+				Addresses: []string{"1.1.1.1"},
+			}},
+		}},
+	}
+	secret, err := resources.GenerateCertificate([]string{"foo.bar.com"}, "whatever-secret-uid", "istio-system")
+	if err != nil {
+		t.Fatal("GenerateCertificate() =", err)
+	}
+	certificate, err := newTLSCertificate(secret)
+	if err != nil {
+		t.Fatal("newTLSCertificate() =", err)
+	}
+	secrets := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	secrets.Add(secret)
+	rotatedSecret, err := resources.GenerateCertificate([]string{"foo.bar.com"}, "whatever-secret-uid", "istio-system")
+	if err != nil {
+		t.Fatal("GenerateCertificate() =", err)
+	}
+	rotatedCertificate, err := newTLSCertificate(rotatedSecret)
+	if err != nil {
+		t.Fatal("newTLSCertificate() =", err)
+	}
+
+	tests := []struct {
+		name          string
+		gatewayLister istiolisters.GatewayLister
+		certSecrets   []*v1.Secret
+		errMessage    string
+		results       []tlsProbeTarget
+	}{{
+		name:          "gateway error",
+		gatewayLister: &fakeGatewayLister{fails: true},
+		errMessage:    "failed to list Gateways",
+	}, {
+		name:          "no gateways",
+		gatewayLister: &fakeGatewayLister{},
+		results:       []tlsProbeTarget{},
+	}, {
+		name: "https servers",
+		gatewayLister: &fakeGatewayLister{
+			gateways: []*v1beta1.Gateway{tlsGateway, foreignGateway},
+		},
+		results: []tlsProbeTarget{{
+			ProbeTarget: status.ProbeTarget{
+				PodIPs:  sets.New("1.1.1.1"),
+				PodPort: "8443",
+				Port:    "443",
+				URLs:    []*url.URL{{Scheme: "https", Host: "foo.bar.com:443"}},
+			},
+			Certificate: certificate,
+		}, {
+			// The certificates of Secrets which are not watched are not verified.
+			ProbeTarget: status.ProbeTarget{
+				PodIPs:  sets.New("1.1.1.1"),
+				PodPort: "8443",
+				Port:    "443",
+				URLs:    []*url.URL{{Scheme: "https", Host: "baz.bar.com:443"}},
+			},
+		}},
+	}, {
+		// The informer has not seen the update of the rotated copy yet.
+		name: "rotated certificate",
+		gatewayLister: &fakeGatewayLister{
+			gateways: []*v1beta1.Gateway{tlsGateway},
+		},
+		certSecrets: []*v1.Secret{rotatedSecret},
+		results: []tlsProbeTarget{{
+			ProbeTarget: status.ProbeTarget{
+				PodIPs:  sets.New("1.1.1.1"),
+				PodPort: "8443",
+				Port:    "443",
+				URLs:    []*url.URL{{Scheme: "https", Host: "foo.bar.com:443"}},
+			},
+			Certificate: rotatedCertificate,
+		}, {
+			ProbeTarget: status.ProbeTarget{
+				PodIPs:  sets.New("1.1.1.1"),
+				PodPort: "8443",
+				Port:    "443",
+				URLs:    []*url.URL{{Scheme: "https", Host: "baz.bar.com:443"}},
+			},
+		}},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lister := gatewayPodTargetLister{
+				logger:              zaptest.NewLogger(t).Sugar(),
+				gatewayLister:       test.gatewayLister,
+				endpointSliceLister: endpointSliceLister,
+				serviceLister:       serviceLister,
+				secretLister:        corev1listers.NewSecretLister(secrets),
+			}
+			ctx := config.ToContext(context.Background(), &config.Config{Istio: &config.Istio{}})
+			ctx = withCertSecrets(ctx, test.certSecrets)
+			results, err := lister.ListTLSProbeTargets(ctx, ing)
+			if (err != nil) != (test.errMessage != "") {
+				t.Fatalf("ListTLSProbeTargets() error = %v, want %q", err, test.errMessage)
+			}
+			if err != nil && !strings.Contains(err.Error(), test.errMessage) {
+				t.Fatalf("expected error message %q, saw %v", test.errMessage, err)
+			}
+			if diff := cmp.Diff(test.results, results); diff != "" {
+				t.Error("Unexpected probe targets (-want +got):", diff)
+			}
+		})
+	}
 }
 
-func (l *fakeEndpointsLister) List(_ labels.Selector) ([]*v1.Endpoints, error) {
-	log.Panic("not implemented")
-	return nil, nil
+func TestListProbeTargets_EndpointSlices(t *testing.T) {
+	slice := func(name string, addressType discoveryv1.AddressType, port int32, endpoints ...discoveryv1.Endpoint) *discoveryv1.EndpointSlice {
+		return &discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "istio-system",
+				Name:      name,
+				Labels:    map[string]string{discoveryv1.LabelServiceName: "gateway"},
+			},
+			AddressType: addressType,
+			Ports:       []discoveryv1.EndpointPort{{Name: ptr.To("http"), Port: ptr.To(port)}},
+			Endpoints:   endpoints,
+		}
+	}
+	// The slices of other Services are ignored.
+	otherSlice := slice("other-1", discoveryv1.AddressTypeIPv4, 8080, discoveryv1.Endpoint{Addresses: []string{"2.2.2.2"}})
+	otherSlice.Labels[discoveryv1.LabelServiceName] = "other"
+
+	lister := gatewayPodTargetLister{
+		logger: zaptest.NewLogger(t).Sugar(),
+		gatewayLister: &fakeGatewayLister{
+			gateways: []*v1beta1.Gateway{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "istio-system", Name: "gateway"},
+				Spec: istiov1beta1.Gateway{
+					Servers: []*istiov1beta1.Server{{
+						Hosts: []string{"*"},
+						Port:  &istiov1beta1.Port{Name: "http", Number: 80, Protocol: "HTTP"},
+					}},
+					Selector: map[string]string{"gwt": "istio"},
+				},
+			}},
+		},
+		endpointSliceLister: &fakeEndpointSliceLister{
+			slices: []*discoveryv1.EndpointSlice{
+				slice("gateway-1", discoveryv1.AddressTypeIPv4, 8080,
+					discoveryv1.Endpoint{Addresses: []string{"1.1.1.1"}},
+					discoveryv1.Endpoint{
+						Addresses:  []string{"1.1.1.2"},
+						Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(false)},
+					},
+					discoveryv1.Endpoint{
+						Addresses:  []string{"1.1.1.3"},
+						Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(true), Terminating: ptr.To(true)},
+					}),
+				slice("gateway-2", discoveryv1.AddressTypeIPv6, 8080,
+					discoveryv1.Endpoint{Addresses: []string{"2001:db8::1"}}),
+				slice("gateway-3", discoveryv1.AddressTypeIPv4, 8081,
+					discoveryv1.Endpoint{
+						Addresses:  []string{"1.1.1.4", "1.1.1.5"},
+						Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(true)},
+					}),
+				slice("gateway-4", discoveryv1.AddressTypeFQDN, 8080,
+					discoveryv1.Endpoint{Addresses: []string{"gateway.example.com"}}),
+				otherSlice,
+			},
+		},
+		serviceLister: &fakeServiceLister{
+			services: []*v1.Service{{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "istio-system",
+					Name:      "gateway",
+					Labels:    map[string]string{"gwt": "istio"},
+				},
+				Spec: v1.ServiceSpec{
+					Ports: []v1.ServicePort{{Name: "http", Port: 80}},
+				},
+			}},
+		},
+	}
+	ctx := config.ToContext(context.Background(), &config.Config{
+		Istio: &config.Istio{
+			IngressGateways: []config.Gateway{{Name: "gateway", Namespace: "istio-system"}},
+		},
+	})
+	ing := &v1alpha1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "whatever"},
+		Spec: v1alpha1.IngressSpec{
+			Rules: []v1alpha1.IngressRule{{
+				Hosts:      []string{"foo.bar.com"},
+				Visibility: v1alpha1.IngressVisibilityExternalIP,
+			}},
+		},
+	}
+	results, err := lister.ListProbeTargets(ctx, ing)
+	if err != nil {
+		t.Fatal("ListProbeTargets() =", err)
+	}
+	// The endpoints are merged by port number and IP family. Those which are not ready
+	// or terminating, and FQDN endpoints, are not probed.
+	want := []status.ProbeTarget{{
+		PodIPs:  sets.New("1.1.1.1"),
+		PodPort: "8080",
+		Port:    "80",
+		URLs:    []*url.URL{{Scheme: "http", Host: "foo.bar.com:80"}},
+	}, {
+		PodIPs:  sets.New("2001:db8::1"),
+		PodPort: "8080",
+		Port:    "80",
+		URLs:    []*url.URL{{Scheme: "http", Host: "foo.bar.com:80"}},
+	}, {
+		PodIPs:  sets.New("1.1.1.4"),
+		PodPort: "8081",
+		Port:    "80",
+		URLs:    []*url.URL{{Scheme: "http", Host: "foo.bar.com:80"}},
+	}}
+	if diff := cmp.Diff(want, results); diff != "" {
+		t.Error("Unexpected probe targets (-want +got):", diff)
+	}
 }
 
-func (l *fakeEndpointsLister) Endpoints(_ string) corev1listers.EndpointsNamespaceLister {
-	return l
+func TestListProbeTargets_IPFamilies(t *testing.T) {
+	slice := func(name string, addressType discoveryv1.AddressType, ready bool, address string) *discoveryv1.EndpointSlice {
+		return &discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "istio-system",
+				Name:      name,
+				Labels:    map[string]string{discoveryv1.LabelServiceName: "gateway"},
+			},
+			AddressType: addressType,
+			Ports:       []discoveryv1.EndpointPort{{Name: ptr.To("http"), Port: ptr.To[int32](8080)}},
+			Endpoints: []discoveryv1.Endpoint{{
+				Addresses:  []string{address},
+				Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(ready)},
+			}},
+		}
+	}
+	target := func(ip string) status.ProbeTarget {
+		return status.ProbeTarget{
+			PodIPs:  sets.New(ip),
+			PodPort: "8080",
+			Port:    "80",
+			URLs:    []*url.URL{{Scheme: "http", Host: "foo.bar.com:80"}},
+		}
+	}
+	ipv4 := slice("gateway-ipv4", discoveryv1.AddressTypeIPv4, true, "1.1.1.1")
+	ipv6 := slice("gateway-ipv6", discoveryv1.AddressTypeIPv6, true, "2001:db8::1")
+	ipv6NotReady := slice("gateway-ipv6", discoveryv1.AddressTypeIPv6, false, "2001:db8::1")
+
+	tests := []struct {
+		name       string
+		families   []v1.IPFamily
+		probed     config.ProbeIPFamilies
+		slices     []*discoveryv1.EndpointSlice
+		want       []status.ProbeTarget
+		errMessage string
+	}{{
+		name:   "families not stated",
+		slices: []*discoveryv1.EndpointSlice{ipv4, ipv6},
+		want:   []status.ProbeTarget{target("1.1.1.1"), target("2001:db8::1")},
+	}, {
+		name:     "IPv6 only",
+		families: []v1.IPFamily{v1.IPv6Protocol},
+		slices:   []*discoveryv1.EndpointSlice{ipv6},
+		want:     []status.ProbeTarget{target("2001:db8::1")},
+	}, {
+		name:     "dual-stack, primary family",
+		families: []v1.IPFamily{v1.IPv6Protocol, v1.IPv4Protocol},
+		slices:   []*discoveryv1.EndpointSlice{ipv4, ipv6},
+		want:     []status.ProbeTarget{target("2001:db8::1")},
+	}, {
+		name:     "dual-stack, all families",
+		families: []v1.IPFamily{v1.IPv6Protocol, v1.IPv4Protocol},
+		probed:   config.ProbeIPFamiliesAll,
+		slices:   []*discoveryv1.EndpointSlice{ipv4, ipv6},
+		want:     []status.ProbeTarget{target("1.1.1.1"), target("2001:db8::1")},
+	}, {
+		name:       "dual-stack, all families, one not ready",
+		families:   []v1.IPFamily{v1.IPv4Protocol, v1.IPv6Protocol},
+		probed:     config.ProbeIPFamiliesAll,
+		slices:     []*discoveryv1.EndpointSlice{ipv4, ipv6NotReady},
+		errMessage: "no ready endpoint of Service istio-system/gateway for IP family IPv6",
+	}, {
+		name:     "dual-stack, all families, none ready",
+		families: []v1.IPFamily{v1.IPv4Protocol, v1.IPv6Protocol},
+		probed:   config.ProbeIPFamiliesAll,
+		slices:   []*discoveryv1.EndpointSlice{ipv6NotReady},
+		want:     []status.ProbeTarget{{PodIPs: sets.New[string](), PodPort: "8080", Port: "80", URLs: []*url.URL{{Scheme: "http", Host: "foo.bar.com:80"}}}},
+	}, {
+		name:     "single-stack, all families",
+		families: []v1.IPFamily{v1.IPv4Protocol},
+		probed:   config.ProbeIPFamiliesAll,
+		slices:   []*discoveryv1.EndpointSlice{ipv4, ipv6NotReady},
+		want:     []status.ProbeTarget{target("1.1.1.1")},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lister := gatewayPodTargetLister{
+				logger: zaptest.NewLogger(t).Sugar(),
+				gatewayLister: &fakeGatewayLister{
+					gateways: []*v1beta1.Gateway{{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "istio-system", Name: "gateway"},
+						Spec: istiov1beta1.Gateway{
+							Servers: []*istiov1beta1.Server{{
+								Hosts: []string{"*"},
+								Port:  &istiov1beta1.Port{Name: "http", Number: 80, Protocol: "HTTP"},
+							}},
+							Selector: map[string]string{"gwt": "istio"},
+						},
+					}},
+				},
+				endpointSliceLister: &fakeEndpointSliceLister{slices: test.slices},
+				serviceLister: &fakeServiceLister{
+					services: []*v1.Service{{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: "istio-system",
+							Name:      "gateway",
+							Labels:    map[string]string{"gwt": "istio"},
+						},
+						Spec: v1.ServiceSpec{
+							Ports:      []v1.ServicePort{{Name: "http", Port: 80}},
+							IPFamilies: test.families,
+						},
+					}},
+				},
+			}
+			ctx := config.ToContext(context.Background(), &config.Config{
+				Istio: &config.Istio{
+					IngressGateways: []config.Gateway{{Name: "gateway", Namespace: "istio-system"}},
+					ProbeIPFamilies: test.probed,
+				},
+			})
+			ing := &v1alpha1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "whatever"},
+				Spec: v1alpha1.IngressSpec{
+					Rules: []v1alpha1.IngressRule{{
+						Hosts:      []string{"foo.bar.com"},
+						Visibility: v1alpha1.IngressVisibilityExternalIP,
+					}},
+				},
+			}
+
+			results, err := lister.ListProbeTargets(ctx, ing)
+			if (err != nil) != (test.errMessage != "") {
+				t.Fatalf("ListProbeTargets() error = %v, want %q", err, test.errMessage)
+			}
+			if err != nil && !strings.Contains(err.Error(), test.errMessage) {
+				t.Fatalf("expected error message %q, saw %v", test.errMessage, err)
+			}
+			if diff := cmp.Diff(test.want, results); err == nil && diff != "" {
+				t.Error("Unexpected probe targets (-want +got):", diff)
+			}
+		})
+	}
+}
+
+func TestReadyAddresses(t *testing.T) {
+	slice := &discoveryv1.EndpointSlice{
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Endpoints: []discoveryv1.Endpoint{{
+			// The readiness is unknown, which means ready.
+			Addresses: []string{"1.1.1.1"},
+		}, {
+			Addresses:  []string{"1.1.1.2"},
+			Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(true)},
+		}, {
+			Addresses:  []string{"1.1.1.3"},
+			Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(false)},
+		}, {
+			Addresses:  []string{"1.1.1.4"},
+			Conditions: discoveryv1.EndpointConditions{Terminating: ptr.To(true)},
+		}, {
+			Addresses: []string{},
+		}},
+	}
+	if want, got := []string{"1.1.1.1", "1.1.1.2"}, readyAddresses(slice); !cmp.Equal(want, got) {
+		t.Errorf("readyAddresses() = %v, want %v", got, want)
+	}
+
+	slice.AddressType = discoveryv1.AddressTypeFQDN
+	if got := readyAddresses(slice); len(got) != 0 {
+		t.Errorf("readyAddresses(FQDN) = %v, want none", got)
+	}
+}
+
+func TestDepartedPods(t *testing.T) {
+	slice := func(name string, endpoints ...discoveryv1.Endpoint) *discoveryv1.EndpointSlice {
+		return &discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "istio-system",
+				Name:      name,
+				Labels:    map[string]string{discoveryv1.LabelServiceName: "gateway"},
+			},
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Endpoints:   endpoints,
+		}
+	}
+	endpoint := func(address string, conditions discoveryv1.EndpointConditions) discoveryv1.Endpoint {
+		return discoveryv1.Endpoint{Addresses: []string{address}, Conditions: conditions}
+	}
+	terminating := discoveryv1.EndpointConditions{Ready: ptr.To(false), Terminating: ptr.To(true)}
+
+	old := slice("gateway-1",
+		endpoint("1.1.1.1", discoveryv1.EndpointConditions{}),
+		endpoint("1.1.1.2", discoveryv1.EndpointConditions{}),
+		endpoint("1.1.1.3", discoveryv1.EndpointConditions{}))
+	updated := slice("gateway-1",
+		endpoint("1.1.1.1", discoveryv1.EndpointConditions{}),
+		endpoint("1.1.1.2", terminating),
+		endpoint("1.1.1.4", discoveryv1.EndpointConditions{Terminating: ptr.To(false)}))
+	// 1.1.1.1 moved to another EndpointSlice of the Service, terminating 1.1.1.3 did not.
+	sibling := slice("gateway-2",
+		endpoint("1.1.1.1", discoveryv1.EndpointConditions{}),
+		endpoint("1.1.1.3", terminating))
+	other := slice("other-1", endpoint("1.1.1.1", discoveryv1.EndpointConditions{}))
+	other.Labels[discoveryv1.LabelServiceName] = "other"
+
+	pods := func(ips ...string) []*v1.Pod {
+		var ret []*v1.Pod
+		for _, ip := range ips {
+			ret = append(ret, &v1.Pod{Status: v1.PodStatus{PodIP: ip}})
+		}
+		return ret
+	}
+
+	tests := []struct {
+		name     string
+		siblings []*discoveryv1.EndpointSlice
+		oldObj   interface{}
+		newObj   interface{}
+		want     []*v1.Pod
+	}{{
+		name:   "added",
+		newObj: updated,
+		want:   pods("1.1.1.2"),
+	}, {
+		name:   "updated",
+		oldObj: old,
+		newObj: updated,
+		want:   pods("1.1.1.2", "1.1.1.3"),
+	}, {
+		name:     "deleted",
+		siblings: []*discoveryv1.EndpointSlice{sibling, other},
+		oldObj:   old,
+		want:     pods("1.1.1.2", "1.1.1.3"),
+	}, {
+		name:   "deleted while disconnected",
+		oldObj: cache.DeletedFinalStateUnknown{Key: "istio-system/gateway-1", Obj: old},
+		want:   pods("1.1.1.1", "1.1.1.2", "1.1.1.3"),
+	}, {
+		name:   "not an EndpointSlice",
+		oldObj: &v1.Pod{},
+		newObj: &v1.Pod{},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lister := &fakeEndpointSliceLister{slices: test.siblings}
+			if diff := cmp.Diff(test.want, departedPods(lister, test.oldObj, test.newObj), cmpopts.EquateEmpty()); diff != "" {
+				t.Error("departedPods() (-want +got):", diff)
+			}
+		})
+	}
+}
+
+type fakeEndpointSliceLister struct {
+	slices []*discoveryv1.EndpointSlice
+	fails  bool
 }
 
-func (l *fakeEndpointsLister) Get(name string) (*v1.Endpoints, error) {
+func (l *fakeEndpointSliceLister) List(selector labels.Selector) ([]*discoveryv1.EndpointSlice, error) {
 	if l.fails {
-		return nil, errors.New("failed to get Endpoints")
+		return nil, errors.New("failed to list EndpointSlices")
 	}
-	for _, ep := range l.endpointses {
-		if ep.Name == name {
-			return ep, nil
+	results := []*discoveryv1.EndpointSlice{}
+	for _, slice := range l.slices {
+		if selector.Matches(labels.Set(slice.Labels)) {
+			results = append(results, slice)
 		}
 	}
-	return nil, errors.New("not found")
+	return results, nil
+}
+
+func (l *fakeEndpointSliceLister) EndpointSlices(_ string) discoveryv1listers.EndpointSliceNamespaceLister {
+	return l
+}
+
+func (l *fakeEndpointSliceLister) Get(_ string) (*discoveryv1.EndpointSlice, error) {
+	log.Panic("not implemented")
+	return nil, nil
 }
 
 type fakeServiceLister struct {
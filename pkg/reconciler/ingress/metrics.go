@@ -0,0 +1,364 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/metric"
+	istiolisters "istio.io/client-go/pkg/listers/networking/v1beta1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/net-istio/pkg/reconciler/ingress/config"
+	"knative.dev/networking/pkg/apis/networking"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+	"knative.dev/networking/pkg/status"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/observability/attributekey"
+)
+
+const scopeName = "knative.dev/net-istio/pkg/reconciler/ingress"
+
+// The phases of the reconciliation of an Ingress.
+const (
+	phaseTLS             = "tls"
+	phaseGateways        = "gateways"
+	phaseVirtualServices = "virtualservices"
+	phaseProbing         = "probing"
+)
+
+// The outcomes of the probing of an Ingress.
+const (
+	probeReady      = "ready"
+	probeFailed     = "failed"
+	probeError      = "error"
+	probeSuperseded = "superseded"
+	probeCancelled  = "cancelled"
+)
+
+var (
+	phaseAttr   = attributekey.String("kn.netistio.reconcile.phase")
+	kindAttr    = attributekey.String("kn.netistio.resource.kind")
+	gatewayAttr = attributekey.String("kn.netistio.gateway")
+	outcomeAttr = attributekey.String("kn.netistio.probe.outcome")
+
+	durationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120}
+)
+
+// metrics are the metrics of the Ingress reconciler. The methods do nothing on nil
+// metrics, e.g. in tests.
+type metrics struct {
+	phaseDuration metric.Float64Histogram
+	probeDuration metric.Float64Histogram
+	notOwned      metric.Int64Counter
+
+	meter metric.Meter
+}
+
+func newMetrics(provider metric.MeterProvider) *metrics {
+	var (
+		m   = metrics{meter: provider.Meter(scopeName)}
+		err error
+	)
+
+	m.phaseDuration, err = m.meter.Float64Histogram(
+		"kn.netistio.reconcile.phase.duration",
+		metric.WithDescription("The duration of the phases of the reconciliation of an Ingress."),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(durationBuckets...),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	m.probeDuration, err = m.meter.Float64Histogram(
+		"kn.netistio.probe.duration",
+		metric.WithDescription("The duration from the first probe of a generation of an Ingress until its outcomes."),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(durationBuckets...),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	m.notOwned, err = m.meter.Int64Counter(
+		"kn.netistio.notowned.conflicts",
+		metric.WithDescription("The number of resources the reconciler did not update because they are owned by another object."),
+		metric.WithUnit("{conflict}"),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	return &m
+}
+
+func (m *metrics) recordPhase(ctx context.Context, phase string, d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.phaseDuration.Record(ctx, d.Seconds(), metric.WithAttributes(phaseAttr.With(phase)))
+}
+
+func (m *metrics) recordNotOwned(ctx context.Context, kind string) {
+	if m == nil {
+		return
+	}
+	m.notOwned.Add(ctx, 1, metric.WithAttributes(kindAttr.With(kind)))
+}
+
+func (m *metrics) recordProbe(ctx context.Context, outcome string, d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.probeDuration.Record(ctx, d.Seconds(), metric.WithAttributes(outcomeAttr.With(outcome)))
+}
+
+// observeResources registers the gauges of the resources managed by the reconciler and
+// of the servers of the shared Gateways. The managed resources are counted as the given
+// informers see them, so that they are not listed on every collection.
+func (m *metrics) observeResources(
+	vsInformer cache.SharedInformer,
+	gatewayInformer cache.SharedInformer,
+	secretInformer cache.SharedInformer,
+	gatewayLister istiolisters.GatewayLister,
+	istio func() *config.Istio,
+) error {
+	managed, err := m.meter.Int64ObservableGauge(
+		"kn.netistio.managed.resources",
+		metric.WithDescription("The number of VirtualServices, Gateways and copied Secrets managed for Ingresses."),
+		metric.WithUnit("{resource}"),
+	)
+	if err != nil {
+		return err
+	}
+	servers, err := m.meter.Int64ObservableGauge(
+		"kn.netistio.gateway.servers",
+		metric.WithDescription("The number of servers of the shared Gateways."),
+		metric.WithUnit("{server}"),
+	)
+	if err != nil {
+		return err
+	}
+
+	counters := make(map[string]*resourceCounter, 3)
+	for kind, counted := range map[string]struct {
+		informer cache.SharedInformer
+		matches  func(metav1.Object) bool
+	}{
+		"VirtualService": {vsInformer, hasLabel(networking.IngressLabelKey)},
+		"Gateway":        {gatewayInformer, isManagedGateway},
+		"Secret":         {secretInformer, hasLabel(networking.OriginSecretNamespaceLabelKey)},
+	} {
+		counter := &resourceCounter{matches: counted.matches}
+		if _, err := counted.informer.AddEventHandler(counter.handler()); err != nil {
+			return err
+		}
+		counters[kind] = counter
+	}
+
+	_, err = m.meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		for kind, counter := range counters {
+			o.ObserveInt64(managed, counter.count.Load(), metric.WithAttributes(kindAttr.With(kind)))
+		}
+
+		cfg := istio()
+		if cfg == nil {
+			return nil
+		}
+		for _, gws := range [][]config.Gateway{cfg.IngressGateways, cfg.LocalGateways} {
+			for _, gw := range gws {
+				gateway, err := gatewayLister.Gateways(gw.Namespace).Get(gw.Name)
+				if apierrs.IsNotFound(err) {
+					continue
+				} else if err != nil {
+					return err
+				}
+				o.ObserveInt64(servers, int64(len(gateway.Spec.GetServers())),
+					metric.WithAttributes(gatewayAttr.With(gw.QualifiedName())))
+			}
+		}
+		return nil
+	}, managed, servers)
+	return err
+}
+
+// resourceCounter counts the objects of an informer matching a predicate.
+type resourceCounter struct {
+	matches func(metav1.Object) bool
+	count   atomic.Int64
+}
+
+// handler returns the event handler keeping the count up to date.
+func (c *resourceCounter) handler() cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.count.Add(c.value(obj))
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			c.count.Add(c.value(newObj) - c.value(oldObj))
+		},
+		DeleteFunc: func(obj interface{}) {
+			c.count.Add(-c.value(obj))
+		},
+	}
+}
+
+// value returns 1 if the given object, possibly a tombstone, is counted, 0 otherwise.
+func (c *resourceCounter) value(obj interface{}) int64 {
+	acc, err := kmeta.DeletionHandlingAccessor(obj)
+	if err != nil || !c.matches(acc) {
+		return 0
+	}
+	return 1
+}
+
+// hasLabel returns a predicate matching the objects with the given label.
+func hasLabel(key string) func(metav1.Object) bool {
+	return func(obj metav1.Object) bool {
+		_, ok := obj.GetLabels()[key]
+		return ok
+	}
+}
+
+// isManagedGateway returns whether the Gateway was created for an Ingress, or is a
+// wildcard Gateway created for a certificate.
+func isManagedGateway(gateway metav1.Object) bool {
+	if _, ok := gateway.GetLabels()[networking.IngressLabelKey]; ok {
+		return true
+	}
+	owner := metav1.GetControllerOf(gateway)
+	return owner != nil && owner.Kind == "Secret" && owner.APIVersion == "v1"
+}
+
+// measuredStatusManager records the outcomes of the probing of every generation of an
+// Ingress, and how long it took. The probers only report whether the Ingress is ready on
+// all its Gateways, so the outcomes are not broken down by Gateway.
+type measuredStatusManager struct {
+	status.Manager
+
+	metrics *metrics
+
+	mu      sync.Mutex
+	pending map[types.NamespacedName]pendingProbe
+}
+
+type pendingProbe struct {
+	generation int64
+	start      time.Time
+	// failed is true once the failure of the probing was recorded.
+	failed bool
+}
+
+func newMeasuredStatusManager(manager status.Manager, m *metrics) *measuredStatusManager {
+	return &measuredStatusManager{
+		Manager: manager,
+		metrics: m,
+		pending: make(map[types.NamespacedName]pendingProbe),
+	}
+}
+
+// IsReady implements status.Manager. A generation whose probing fails on some hosts is
+// recorded as failed once, and again with its outcome once the probing ends.
+func (m *measuredStatusManager) IsReady(ctx context.Context, ing *v1alpha1.Ingress) (bool, error) {
+	ready, err := m.Manager.IsReady(ctx, ing)
+
+	key := types.NamespacedName{Namespace: ing.Namespace, Name: ing.Name}
+	now := time.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	probe, ok := m.pending[key]
+	if ok && probe.generation != ing.Generation {
+		m.metrics.recordProbe(ctx, probeSuperseded, now.Sub(probe.start))
+		ok = false
+	}
+
+	switch {
+	case err != nil:
+		if ok {
+			m.metrics.recordProbe(ctx, probeError, now.Sub(probe.start))
+		}
+		delete(m.pending, key)
+	case ready:
+		// Ingresses found ready right away, e.g. after a resync, were not probed.
+		if ok {
+			m.metrics.recordProbe(ctx, probeReady, now.Sub(probe.start))
+		}
+		delete(m.pending, key)
+	default:
+		if !ok {
+			probe = pendingProbe{generation: ing.Generation, start: now}
+		}
+		if !probe.failed && m.hasFailingHosts(ctx, ing) {
+			m.metrics.recordProbe(ctx, probeFailed, now.Sub(probe.start))
+			probe.failed = true
+		}
+		m.pending[key] = probe
+	}
+	return ready, err
+}
+
+// hasFailingHosts returns true if the wrapped manager reports hosts of the given Ingress
+// that failed to be probed.
+func (m *measuredStatusManager) hasFailingHosts(ctx context.Context, ing *v1alpha1.Ingress) bool {
+	reporter, ok := m.Manager.(hostFailureReporter)
+	return ok && len(reporter.FailingHosts(ctx, ing)) > 0
+}
+
+// FailingHosts implements hostFailureReporter.
+func (m *measuredStatusManager) FailingHosts(ctx context.Context, ing *v1alpha1.Ingress) []hostFailure {
+	if reporter, ok := m.Manager.(hostFailureReporter); ok {
+		return reporter.FailingHosts(ctx, ing)
+	}
+	return nil
+}
+
+// ProbeCoverage implements probeCoverageReporter.
+func (m *measuredStatusManager) ProbeCoverage(ctx context.Context, ing *v1alpha1.Ingress) (probeCoverage, bool) {
+	if reporter, ok := m.Manager.(probeCoverageReporter); ok {
+		return reporter.ProbeCoverage(ctx, ing)
+	}
+	return probeCoverage{}, false
+}
+
+// CancelIngressProbing cancels probing of the provided Ingress by the wrapped manager.
+func (m *measuredStatusManager) CancelIngressProbing(obj interface{}) {
+	if canceler, ok := m.Manager.(ingressProbeCanceler); ok {
+		canceler.CancelIngressProbing(obj)
+	}
+}
+
+// cancelProbing records the cancellation of the probing of a deleted Ingress.
+func (m *measuredStatusManager) cancelProbing(obj interface{}) {
+	acc, err := kmeta.DeletionHandlingAccessor(obj)
+	if err != nil {
+		return
+	}
+	key := types.NamespacedName{Namespace: acc.GetNamespace(), Name: acc.GetName()}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if probe, ok := m.pending[key]; ok {
+		m.metrics.recordProbe(context.Background(), probeCancelled, time.Since(probe.start))
+		delete(m.pending, key)
+	}
+}
@@ -0,0 +1,280 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	istiov1beta1 "istio.io/api/networking/v1beta1"
+	"istio.io/client-go/pkg/apis/networking/v1beta1"
+	istiolisters "istio.io/client-go/pkg/listers/networking/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
+	"knative.dev/net-istio/pkg/reconciler/ingress/config"
+	"knative.dev/networking/pkg/apis/networking"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+	"knative.dev/networking/pkg/status"
+)
+
+// fakeProber returns the given results of IsReady in order, and reports the given failing
+// hosts.
+type fakeProber struct {
+	status.Manager
+	results []fakeResult
+	failing []hostFailure
+}
+
+type fakeResult struct {
+	ready bool
+	err   error
+}
+
+func (p *fakeProber) IsReady(context.Context, *v1alpha1.Ingress) (bool, error) {
+	result := p.results[0]
+	p.results = p.results[1:]
+	return result.ready, result.err
+}
+
+func (p *fakeProber) FailingHosts(context.Context, *v1alpha1.Ingress) []hostFailure {
+	return p.failing
+}
+
+// collect returns the data points of the given metric by their attributes.
+func collect(t *testing.T, reader sdkmetric.Reader, name string) map[string]int64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal("Collect() =", err)
+	}
+	ret := make(map[string]int64)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			switch data := m.Data.(type) {
+			case metricdata.Histogram[float64]:
+				for _, point := range data.DataPoints {
+					ret[attributesKey(point.Attributes)] = int64(point.Count)
+				}
+			case metricdata.Gauge[int64]:
+				for _, point := range data.DataPoints {
+					ret[attributesKey(point.Attributes)] = point.Value
+				}
+			case metricdata.Sum[int64]:
+				for _, point := range data.DataPoints {
+					ret[attributesKey(point.Attributes)] = point.Value
+				}
+			}
+		}
+	}
+	return ret
+}
+
+func attributesKey(set attribute.Set) string {
+	var key string
+	for _, kv := range set.ToSlice() {
+		if key != "" {
+			key += ","
+		}
+		key += kv.Value.AsString()
+	}
+	return key
+}
+
+func probedIngress(generation int64) *v1alpha1.Ingress {
+	return &v1alpha1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ingress", Generation: generation},
+	}
+}
+
+func TestMeasuredStatusManager(t *testing.T) {
+	errProbe := errors.New("probe failed")
+	tests := []struct {
+		name    string
+		results []fakeResult
+		// generations are the generations of the Ingress passed to IsReady.
+		generations []int64
+		failing     []hostFailure
+		cancel      bool
+		want        map[string]int64
+	}{{
+		name:        "ready after probing",
+		results:     []fakeResult{{}, {}, {ready: true}},
+		generations: []int64{1, 1, 1},
+		want:        map[string]int64{"ready": 1},
+	}, {
+		name:        "ready right away",
+		results:     []fakeResult{{ready: true}},
+		generations: []int64{1},
+		want:        map[string]int64{},
+	}, {
+		name:        "failed before ready",
+		results:     []fakeResult{{}, {}, {}, {ready: true}},
+		failing:     []hostFailure{{Host: "example.com", Pods: 1, Err: "connection refused"}},
+		generations: []int64{1, 1, 1, 1},
+		want:        map[string]int64{"failed": 1, "ready": 1},
+	}, {
+		name:        "error",
+		results:     []fakeResult{{}, {err: errProbe}},
+		generations: []int64{1, 1},
+		want:        map[string]int64{"error": 1},
+	}, {
+		name:        "superseded by a new generation",
+		results:     []fakeResult{{}, {}, {ready: true}},
+		generations: []int64{1, 2, 2},
+		want:        map[string]int64{"superseded": 1, "ready": 1},
+	}, {
+		name:        "cancelled",
+		results:     []fakeResult{{}},
+		generations: []int64{1},
+		cancel:      true,
+		want:        map[string]int64{"cancelled": 1},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reader := sdkmetric.NewManualReader()
+			m := newMetrics(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+			manager := newMeasuredStatusManager(&fakeProber{results: test.results, failing: test.failing}, m)
+
+			for _, generation := range test.generations {
+				manager.IsReady(context.Background(), probedIngress(generation))
+			}
+			if test.cancel {
+				manager.cancelProbing(probedIngress(1))
+			}
+
+			if got := collect(t, reader, "kn.netistio.probe.duration"); !cmp.Equal(got, test.want) {
+				t.Error("Probe outcomes (-want, +got):", cmp.Diff(test.want, got))
+			}
+			if len(manager.pending) != 0 && !test.cancel {
+				// The last probe of every test is an outcome.
+				t.Errorf("Pending probes = %v, want none", manager.pending)
+			}
+		})
+	}
+}
+
+// fakeInformer records the event handlers added to it.
+type fakeInformer struct {
+	cache.SharedIndexInformer
+	handlers []cache.ResourceEventHandler
+}
+
+func (i *fakeInformer) AddEventHandler(handler cache.ResourceEventHandler) (cache.ResourceEventHandlerRegistration, error) {
+	i.handlers = append(i.handlers, handler)
+	return nil, nil
+}
+
+func (i *fakeInformer) add(objs ...interface{}) {
+	for _, obj := range objs {
+		for _, handler := range i.handlers {
+			handler.OnAdd(obj, true)
+		}
+	}
+}
+
+func TestObserveResources(t *testing.T) {
+	vsInformer, gatewayInformer, secretInformer := &fakeInformer{}, &fakeInformer{}, &fakeInformer{}
+	gatewayIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+
+	reader := sdkmetric.NewManualReader()
+	m := newMetrics(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	istio := &config.Istio{
+		IngressGateways: []config.Gateway{{Namespace: "knative-serving", Name: "knative-ingress-gateway"}},
+		LocalGateways:   []config.Gateway{{Namespace: "knative-serving", Name: "knative-local-gateway"}},
+	}
+	if err := m.observeResources(vsInformer, gatewayInformer, secretInformer,
+		istiolisters.NewGatewayLister(gatewayIndexer),
+		func() *config.Istio { return istio },
+	); err != nil {
+		t.Fatal("observeResources() =", err)
+	}
+
+	ingressLabels := map[string]string{networking.IngressLabelKey: "ingress"}
+	ingressVS := &v1beta1.VirtualService{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ingress-ingress", Labels: ingressLabels},
+	}
+	meshVS := &v1beta1.VirtualService{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ingress-mesh", Labels: ingressLabels},
+	}
+	vsInformer.add(ingressVS, meshVS, &v1beta1.VirtualService{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "unrelated"},
+	})
+	sharedGateway := &v1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "knative-serving", Name: "knative-ingress-gateway"},
+		Spec: istiov1beta1.Gateway{Servers: []*istiov1beta1.Server{{
+			Hosts: []string{"*"},
+		}, {
+			Hosts: []string{"example.com"},
+		}}},
+	}
+	gatewayIndexer.Add(sharedGateway)
+	gatewayInformer.add(sharedGateway, &v1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "knative-serving", Name: "ingress-gateway", Labels: ingressLabels},
+	}, &v1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "istio-system",
+			Name:      "wildcard",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "v1",
+				Kind:       "Secret",
+				Name:       "wildcard",
+				Controller: ptr.To(true),
+			}},
+		},
+	})
+	secretInformer.add(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "istio-system",
+			Name:      "copied",
+			Labels:    map[string]string{networking.OriginSecretNamespaceLabelKey: "default"},
+		},
+	})
+
+	want := map[string]int64{"VirtualService": 2, "Gateway": 2, "Secret": 1}
+	if got := collect(t, reader, "kn.netistio.managed.resources"); !cmp.Equal(got, want) {
+		t.Error("Managed resources (-want, +got):", cmp.Diff(want, got))
+	}
+	// The missing local gateway is not reported.
+	want = map[string]int64{"knative-serving/knative-ingress-gateway": 2}
+	if got := collect(t, reader, "kn.netistio.gateway.servers"); !cmp.Equal(got, want) {
+		t.Error("Gateway servers (-want, +got):", cmp.Diff(want, got))
+	}
+
+	// A VirtualService losing its label and another deleted without its final state are
+	// not counted anymore.
+	unlabeled := ingressVS.DeepCopy()
+	unlabeled.Labels = nil
+	for _, handler := range vsInformer.handlers {
+		handler.OnUpdate(ingressVS, unlabeled)
+		handler.OnDelete(cache.DeletedFinalStateUnknown{Key: "default/ingress-mesh", Obj: meshVS})
+	}
+	want = map[string]int64{"VirtualService": 0, "Gateway": 2, "Secret": 1}
+	if got := collect(t, reader, "kn.netistio.managed.resources"); !cmp.Equal(got, want) {
+		t.Error("Managed resources after the updates (-want, +got):", cmp.Diff(want, got))
+	}
+}
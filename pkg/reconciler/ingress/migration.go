@@ -0,0 +1,153 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	istiov1beta1 "istio.io/api/networking/v1beta1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"knative.dev/net-istio/pkg/reconciler/ingress/resources"
+	"knative.dev/net-istio/pkg/reconciler/ingress/resources/names"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+	"knative.dev/pkg/logging"
+)
+
+// ingressProbeCanceler is implemented by the status managers caching the probing
+// results of Ingresses.
+type ingressProbeCanceler interface {
+	CancelIngressProbing(obj interface{})
+}
+
+// programmedGateways returns the qualified names of the Gateways the given Ingress was
+// programmed on, as recorded in its status. The second return value is false if the
+// Ingress has not recorded them yet, e.g. because it was reconciled by an older version.
+func programmedGateways(ing *v1alpha1.Ingress) (sets.Set[string], bool) {
+	value, ok := ing.Status.Annotations[resources.GatewaysStatusAnnotationKey]
+	if !ok {
+		return nil, false
+	}
+	gateways := sets.New[string]()
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			gateways.Insert(name)
+		}
+	}
+	return gateways, true
+}
+
+// setProgrammedGateways records the qualified names of the Gateways the given Ingress is
+// programmed on in its status.
+func setProgrammedGateways(ing *v1alpha1.Ingress, gateways sets.Set[string]) {
+	if ing.Status.Annotations == nil {
+		ing.Status.Annotations = make(map[string]string, 1)
+	}
+	ing.Status.Annotations[resources.GatewaysStatusAnnotationKey] = strings.Join(sets.List(gateways), ",")
+}
+
+// ingressGatewayNames returns the names of the Gateways with the given qualified names that
+// are in the namespace of the given Ingress, where its per-Ingress Gateways live.
+func ingressGatewayNames(ing *v1alpha1.Ingress, gateways sets.Set[string]) sets.Set[string] {
+	inNamespace := sets.New[string]()
+	for qualifiedName := range gateways {
+		if namespace, name, ok := strings.Cut(qualifiedName, "/"); ok && namespace == ing.Namespace {
+			inNamespace.Insert(name)
+		}
+	}
+	return inNamespace
+}
+
+// cleanupGatewayServers removes the servers of the given Ingress from the Gateways with
+// the given qualified names. Gateways that do not exist anymore and per-Ingress Gateways,
+// which are deleted as a whole by cleanupIngressGateways, are skipped.
+func (r *Reconciler) cleanupGatewayServers(ctx context.Context, ing *v1alpha1.Ingress, gateways sets.Set[string]) error {
+	logger := logging.FromContext(ctx)
+	for _, qualifiedName := range sets.List(gateways) {
+		namespace, name, ok := strings.Cut(qualifiedName, "/")
+		if !ok {
+			logger.Warnf("Ignoring invalid Gateway name %q", qualifiedName)
+			continue
+		}
+		gateway, err := r.gatewayLister.Gateways(namespace).Get(name)
+		if apierrs.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("failed to get Gateway %s: %w", qualifiedName, err)
+		}
+		if metav1.IsControlledBy(gateway, ing) {
+			continue
+		}
+		existing := resources.GetServers(gateway, ing)
+		if len(existing) == 0 {
+			continue
+		}
+		logger.Infof("Removing the servers of the Ingress from Gateway %s", qualifiedName)
+		if err := r.reconcileGateway(ctx, ing, gateway, existing, []*istiov1beta1.Server{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateGateways starts moving the given Ingress to the desired Gateways if it was
+// previously programmed on others, e.g. after the Gateways in config-istio changed. It
+// returns the Gateways the Ingress is leaving, which keep serving it until it was probed
+// Ready on the desired ones, and true if the Ingress is migrating, in which case its
+// readiness has to be probed again.
+func (r *Reconciler) migrateGateways(ctx context.Context, ing *v1alpha1.Ingress, desired sets.Set[string]) (sets.Set[string], bool) {
+	previous, ok := programmedGateways(ing)
+	if !ok || previous.Equal(desired) {
+		return sets.New[string](), false
+	}
+
+	if !previous.IsSuperset(desired) {
+		logging.FromContext(ctx).Infof("Migrating Ingress from Gateways %v to %v", sets.List(previous), sets.List(desired))
+		// The cached probing results do not cover the new Gateways.
+		if canceler, ok := r.statusManager.(ingressProbeCanceler); ok {
+			canceler.CancelIngressProbing(ing)
+		}
+	}
+	return previous.Difference(desired), true
+}
+
+// withLeavingGateways returns the given Gateways by visibility, together with the Gateways
+// the Ingress is leaving that its ingress VirtualService still routes it through, so that
+// they keep routing it while it is migrating.
+func (r *Reconciler) withLeavingGateways(ing *v1alpha1.Ingress, gateways map[v1alpha1.IngressVisibility]sets.Set[string], leaving sets.Set[string]) (map[v1alpha1.IngressVisibility]sets.Set[string], error) {
+	if leaving.Len() == 0 {
+		return gateways, nil
+	}
+	vs, err := r.virtualServiceLister.VirtualServices(resources.VirtualServiceNamespace(ing)).Get(names.IngressVirtualService(ing))
+	if apierrs.IsNotFound(err) {
+		return gateways, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get VirtualService %s: %w", names.IngressVirtualService(ing), err)
+	}
+
+	routed := make(map[v1alpha1.IngressVisibility]sets.Set[string], len(gateways))
+	for visibility, visibleGateways := range gateways {
+		routed[visibility] = visibleGateways.Clone()
+	}
+	for visibility, visibleGateways := range resources.RoutedGateways(ing, vs) {
+		routed[visibility] = visibleGateways.Intersection(leaving).Union(routed[visibility])
+	}
+	return routed, nil
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+	"knative.dev/networking/pkg/ingress"
+	"knative.dev/networking/pkg/status"
+	"knative.dev/pkg/kmeta"
+)
+
+const (
+	// passthroughProbeConcurrency defines how many TLS handshakes can be issued simultaneously.
+	passthroughProbeConcurrency = 15
+	// passthroughProbeTimeout defines the maximum amount of time a TLS handshake can take.
+	passthroughProbeTimeout = 1 * time.Second
+)
+
+// passthroughProbeBackoff defines the delays between retries of failed TLS handshakes.
+var passthroughProbeBackoff = wait.Backoff{
+	Duration: 200 * time.Millisecond,
+	Factor:   2,
+	Cap:      30 * time.Second,
+	Steps:    math.MaxInt32,
+}
+
+// passthroughTargetLister lists the targets to probe for Ingresses using TLS passthrough.
+type passthroughTargetLister interface {
+	// ListPassthroughProbeTargets returns the targets to probe with TLS handshakes.
+	ListPassthroughProbeTargets(ctx context.Context, ing *v1alpha1.Ingress) ([]status.ProbeTarget, error)
+}
+
+// passthroughProber checks if the gateway pods route the traffic of Ingresses using TLS passthrough.
+// Since the workloads terminate TLS themselves, the gateways cannot be probed with HTTP requests.
+// Instead, the prober completes a TLS handshake with every gateway pod presenting each host as SNI,
+// and checks that the certificate presented is valid for that host. This only succeeds once the
+// gateway forwards the traffic for that host to a workload terminating TLS for it, and not e.g.
+// when it is forwarded to a port serving plain HTTP.
+//
+// Unlike the HTTP probes, a handshake cannot tell the generations of an Ingress apart: once a host
+// is routed, the handshakes succeed even if the gateway still forwards its traffic as configured for
+// a previous generation, e.g. to the former backends of an updated Ingress. The "both" readiness
+// strategy waits for istiod to distribute the VirtualServices of the current generation as well.
+type passthroughProber struct {
+	logger *zap.SugaredLogger
+
+	targetLister  passthroughTargetLister
+	readyCallback func(*v1alpha1.Ingress)
+
+	// handshake completes a TLS handshake with addr presenting serverName as SNI, and checks
+	// the certificate presented is valid for serverName.
+	handshake func(ctx context.Context, addr, serverName string) error
+
+	// mu guards ingressStates
+	mu            sync.Mutex
+	ingressStates map[types.NamespacedName]*passthroughState
+}
+
+// passthroughState represents the probing state of an Ingress.
+type passthroughState struct {
+	hash   string
+	ready  bool
+	cancel func()
+}
+
+func newPassthroughProber(
+	logger *zap.SugaredLogger,
+	targetLister passthroughTargetLister,
+	readyCallback func(*v1alpha1.Ingress),
+) *passthroughProber {
+	return &passthroughProber{
+		logger:        logger,
+		targetLister:  targetLister,
+		readyCallback: readyCallback,
+		handshake:     tlsHandshake,
+		ingressStates: make(map[types.NamespacedName]*passthroughState),
+	}
+}
+
+// IsReady checks if the gateway pods route the TLS traffic of the provided Ingress. If the
+// Ingress has not been probed yet, probing starts in the background and readyCallback is
+// called once all the probes succeeded.
+func (p *passthroughProber) IsReady(ctx context.Context, ing *v1alpha1.Ingress) (bool, error) {
+	key := types.NamespacedName{Namespace: ing.Namespace, Name: ing.Name}
+
+	bytes, err := ingress.ComputeHash(ing)
+	if err != nil {
+		return false, fmt.Errorf("failed to compute the hash of the Ingress: %w", err)
+	}
+	hash := hex.EncodeToString(bytes[:])
+
+	if ready, ok := func() (bool, bool) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if state, ok := p.ingressStates[key]; ok {
+			if state.hash == hash {
+				return state.ready, true
+			}
+			// Cancel the probing of the outdated version.
+			state.cancel()
+			delete(p.ingressStates, key)
+		}
+		return false, false
+	}(); ok {
+		return ready, nil
+	}
+
+	targets, err := p.targetLister.ListPassthroughProbeTargets(ctx, ing)
+	if err != nil {
+		return false, err
+	}
+
+	probeCtx, cancel := context.WithCancel(context.Background())
+	ready := countProbes(targets) == 0
+	state := &passthroughState{
+		hash:   hash,
+		ready:  ready,
+		cancel: cancel,
+	}
+
+	p.mu.Lock()
+	p.ingressStates[key] = state
+	p.mu.Unlock()
+
+	if !ready {
+		go p.probe(probeCtx, ing, state, targets)
+	}
+	return ready, nil
+}
+
+// CancelIngressProbing cancels probing of the provided Ingress.
+func (p *passthroughProber) CancelIngressProbing(obj interface{}) {
+	acc, err := kmeta.DeletionHandlingAccessor(obj)
+	if err != nil {
+		return
+	}
+
+	key := types.NamespacedName{Namespace: acc.GetNamespace(), Name: acc.GetName()}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if state, ok := p.ingressStates[key]; ok {
+		state.cancel()
+		delete(p.ingressStates, key)
+	}
+}
+
+func (p *passthroughProber) probe(ctx context.Context, ing *v1alpha1.Ingress, state *passthroughState, targets []status.ProbeTarget) {
+	sem := make(chan struct{}, passthroughProbeConcurrency)
+	var wg sync.WaitGroup
+	for _, target := range targets {
+		for ip := range target.PodIPs {
+			for _, u := range target.URLs {
+				addr := net.JoinHostPort(ip, target.PodPort)
+				serverName := u.Hostname()
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					// The error is only ever the cancellation of ctx, which is checked below.
+					_ = wait.ExponentialBackoffWithContext(ctx, passthroughProbeBackoff, func(ctx context.Context) (bool, error) {
+						select {
+						case sem <- struct{}{}:
+						case <-ctx.Done():
+							return false, ctx.Err()
+						}
+						defer func() { <-sem }()
+
+						if err := p.handshake(ctx, addr, serverName); err != nil {
+							p.logger.Errorf("TLS probing of %s failed, IP: %s: %v", serverName, addr, err)
+							return false, nil
+						}
+						return true, nil
+					})
+				}()
+			}
+		}
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return
+	}
+	p.mu.Lock()
+	state.ready = true
+	p.mu.Unlock()
+	p.readyCallback(ing)
+}
+
+func countProbes(targets []status.ProbeTarget) int {
+	count := 0
+	for _, target := range targets {
+		count += target.PodIPs.Len() * len(target.URLs)
+	}
+	return count
+}
+
+func tlsHandshake(ctx context.Context, addr, serverName string) error {
+	ctx, cancel := context.WithTimeout(ctx, passthroughProbeTimeout)
+	defer cancel()
+
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{},
+		Config: &tls.Config{
+			ServerName: serverName,
+			//nolint:gosec
+			// We only want to know that the Gateway routes the SNI host to a workload serving
+			// it, not that the certificate of the workload is trusted.
+			InsecureSkipVerify: true,
+		},
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	certs := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return fmt.Errorf("no certificate presented for %s", serverName)
+	}
+	return certs[0].VerifyHostname(serverName)
+}
+
+// Make sure the lister used by the controller can list passthrough targets.
+var _ passthroughTargetLister = (*gatewayPodTargetLister)(nil)
@@ -0,0 +1,217 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"knative.dev/net-istio/pkg/reconciler/ingress/resources"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+	"knative.dev/networking/pkg/status"
+)
+
+type fakePassthroughTargetLister struct {
+	targets []status.ProbeTarget
+	fails   bool
+}
+
+func (l *fakePassthroughTargetLister) ListPassthroughProbeTargets(context.Context, *v1alpha1.Ingress) ([]status.ProbeTarget, error) {
+	if l.fails {
+		return nil, errors.New("failed to list targets")
+	}
+	return l.targets, nil
+}
+
+func passthroughIngress(host string) *v1alpha1.Ingress {
+	return &v1alpha1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "default",
+			Name:        "whatever",
+			Annotations: map[string]string{resources.TLSModeAnnotationKey: resources.TLSModePassthrough},
+		},
+		Spec: v1alpha1.IngressSpec{
+			Rules: []v1alpha1.IngressRule{{
+				Hosts:      []string{host},
+				Visibility: v1alpha1.IngressVisibilityExternalIP,
+			}},
+		},
+	}
+}
+
+func passthroughTarget(hosts ...string) status.ProbeTarget {
+	target := status.ProbeTarget{
+		PodIPs:  sets.New("1.1.1.1", "2.2.2.2"),
+		PodPort: "8443",
+		Port:    "443",
+	}
+	for _, host := range hosts {
+		target.URLs = append(target.URLs, &url.URL{Scheme: "tls", Host: host + ":443"})
+	}
+	return target
+}
+
+func TestPassthroughProberNoTargets(t *testing.T) {
+	prober := newPassthroughProber(zaptest.NewLogger(t).Sugar(), &fakePassthroughTargetLister{},
+		func(*v1alpha1.Ingress) { t.Error("Unexpected ready callback") })
+	prober.handshake = func(context.Context, string, string) error {
+		t.Error("Unexpected handshake")
+		return nil
+	}
+
+	ready, err := prober.IsReady(context.Background(), passthroughIngress("foo.bar.com"))
+	if err != nil {
+		t.Fatal("IsReady() =", err)
+	}
+	if !ready {
+		t.Error("IsReady() = false, want true")
+	}
+}
+
+func TestPassthroughProberListError(t *testing.T) {
+	prober := newPassthroughProber(zaptest.NewLogger(t).Sugar(), &fakePassthroughTargetLister{fails: true},
+		func(*v1alpha1.Ingress) {})
+
+	if _, err := prober.IsReady(context.Background(), passthroughIngress("foo.bar.com")); err == nil {
+		t.Error("IsReady() = nil, wanted an error")
+	}
+}
+
+func TestPassthroughProberReady(t *testing.T) {
+	readyCh := make(chan *v1alpha1.Ingress, 1)
+	prober := newPassthroughProber(zaptest.NewLogger(t).Sugar(),
+		&fakePassthroughTargetLister{targets: []status.ProbeTarget{passthroughTarget("foo.bar.com", "baz.bar.com")}},
+		func(ing *v1alpha1.Ingress) { readyCh <- ing })
+
+	var mu sync.Mutex
+	attempts := map[string]int{}
+	prober.handshake = func(_ context.Context, addr, serverName string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		key := addr + "/" + serverName
+		attempts[key]++
+		// Fail the first attempt of every probe to exercise the retries.
+		if attempts[key] == 1 {
+			return errors.New("connection reset by peer")
+		}
+		return nil
+	}
+
+	ing := passthroughIngress("foo.bar.com")
+	ready, err := prober.IsReady(context.Background(), ing)
+	if err != nil {
+		t.Fatal("IsReady() =", err)
+	}
+	if ready {
+		t.Error("IsReady() = true, want false before probing completed")
+	}
+
+	select {
+	case got := <-readyCh:
+		if got != ing {
+			t.Errorf("Ready callback called with %v, want %v", got, ing)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the ready callback")
+	}
+
+	mu.Lock()
+	want := sets.New(
+		"1.1.1.1:8443/foo.bar.com", "1.1.1.1:8443/baz.bar.com",
+		"2.2.2.2:8443/foo.bar.com", "2.2.2.2:8443/baz.bar.com")
+	if got := sets.KeySet(attempts); !got.Equal(want) {
+		t.Errorf("Probed %v, want %v", sets.List(got), sets.List(want))
+	}
+	mu.Unlock()
+
+	if ready, err := prober.IsReady(context.Background(), ing); err != nil || !ready {
+		t.Errorf("IsReady() = %v, %v, want true, nil", ready, err)
+	}
+}
+
+func TestPassthroughProberCancellation(t *testing.T) {
+	// The cancelled probes may still log after the test completed.
+	prober := newPassthroughProber(zap.NewNop().Sugar(),
+		&fakePassthroughTargetLister{targets: []status.ProbeTarget{passthroughTarget("foo.bar.com")}},
+		func(*v1alpha1.Ingress) { t.Error("Unexpected ready callback") })
+
+	handshakes := make(chan struct{}, 100)
+	prober.handshake = func(context.Context, string, string) error {
+		select {
+		case handshakes <- struct{}{}:
+		default:
+		}
+		return errors.New("no route to host")
+	}
+
+	ing := passthroughIngress("foo.bar.com")
+	if ready, err := prober.IsReady(context.Background(), ing); err != nil || ready {
+		t.Fatalf("IsReady() = %v, %v, want false, nil", ready, err)
+	}
+	<-handshakes
+
+	// A new version of the Ingress restarts probing.
+	updated := passthroughIngress("baz.bar.com")
+	if ready, err := prober.IsReady(context.Background(), updated); err != nil || ready {
+		t.Fatalf("IsReady() = %v, %v, want false, nil", ready, err)
+	}
+
+	prober.CancelIngressProbing(updated)
+	prober.mu.Lock()
+	defer prober.mu.Unlock()
+	if len(prober.ingressStates) != 0 {
+		t.Errorf("ingressStates = %v, want empty", prober.ingressStates)
+	}
+}
+
+func TestTLSHandshake(t *testing.T) {
+	server := httptest.NewTLSServer(nil)
+	defer server.Close()
+
+	// The certificate of the test server is valid for example.com.
+	addr := strings.TrimPrefix(server.URL, "https://")
+	if err := tlsHandshake(context.Background(), addr, "example.com"); err != nil {
+		t.Error("tlsHandshake() =", err)
+	}
+
+	// The server does not serve foo.bar.com, e.g. it is another workload.
+	if err := tlsHandshake(context.Background(), addr, "foo.bar.com"); err == nil {
+		t.Error("tlsHandshake(foo.bar.com) = nil, wanted an error")
+	}
+
+	server.Close()
+	if err := tlsHandshake(context.Background(), addr, "example.com"); err == nil {
+		t.Error("tlsHandshake() = nil, wanted an error")
+	}
+
+	// The traffic is forwarded to a port serving plain HTTP.
+	httpServer := httptest.NewServer(nil)
+	defer httpServer.Close()
+	if err := tlsHandshake(context.Background(), strings.TrimPrefix(httpServer.URL, "http://"), "example.com"); err == nil {
+		t.Error("tlsHandshake(HTTP server) = nil, wanted an error")
+	}
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"istio.io/client-go/pkg/apis/networking/v1beta1"
+	fakeistioclientset "istio.io/client-go/pkg/clientset/versioned/fake"
+	istiolisters "istio.io/client-go/pkg/listers/networking/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	fakekubeclientset "k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/net-istio/pkg/reconciler/ingress/config"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+	networkinglisters "knative.dev/networking/pkg/client/listers/networking/v1alpha1"
+	fakestatusmanager "knative.dev/networking/pkg/testing/status"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/tracker"
+)
+
+// Render returns the VirtualServices, Gateways and Secrets the reconciler produces for the
+// given Ingresses with the given configuration, without a cluster. The given objects are
+// the Gateways, Secrets, Services and Namespaces the reconciler reads. The configured
+// gateways not among them are rendered as if they were installed without servers.
+func Render(ctx context.Context, cfg *config.Config, ingresses []*v1alpha1.Ingress, objects []runtime.Object) ([]runtime.Object, error) {
+	istioObjects := make([]runtime.Object, 0, len(objects))
+	kubeObjects := make([]runtime.Object, 0, len(objects))
+	existing := sets.New[string]()
+	for _, obj := range objects {
+		switch o := obj.(type) {
+		case *v1beta1.Gateway:
+			existing.Insert(o.Namespace + "/" + o.Name)
+			istioObjects = append(istioObjects, o)
+		case *v1beta1.VirtualService:
+			istioObjects = append(istioObjects, o)
+		case *corev1.Secret, *corev1.Service, *corev1.Namespace:
+			kubeObjects = append(kubeObjects, obj)
+		default:
+			return nil, fmt.Errorf("unsupported object %T", obj)
+		}
+	}
+	for _, gateways := range [][]config.Gateway{cfg.Istio.IngressGateways, cfg.Istio.LocalGateways} {
+		for _, gtw := range gateways {
+			if !existing.Has(gtw.QualifiedName()) {
+				existing.Insert(gtw.QualifiedName())
+				istioObjects = append(istioObjects, &v1beta1.Gateway{
+					ObjectMeta: metav1.ObjectMeta{Namespace: gtw.Namespace, Name: gtw.Name},
+				})
+			}
+		}
+	}
+
+	// The Istio objects are created through the client, as the tracker of the fake clientset
+	// files typed objects under the first version the scheme knows them by.
+	istioClient := fakeistioclientset.NewSimpleClientset()
+	for _, obj := range istioObjects {
+		var err error
+		switch o := obj.(type) {
+		case *v1beta1.Gateway:
+			_, err = istioClient.NetworkingV1beta1().Gateways(o.Namespace).Create(ctx, o, metav1.CreateOptions{})
+		case *v1beta1.VirtualService:
+			_, err = istioClient.NetworkingV1beta1().VirtualServices(o.Namespace).Create(ctx, o, metav1.CreateOptions{})
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	kubeClient := fakekubeclientset.NewClientset(kubeObjects...)
+	inputSecrets := sets.New[string]()
+	for _, obj := range kubeObjects {
+		if secret, ok := obj.(*corev1.Secret); ok {
+			inputSecrets.Insert(secret.Namespace + "/" + secret.Name)
+		}
+	}
+
+	// The Ingresses are rendered in the order they claimed their hosts, so that the later
+	// ones see the VirtualServices of the earlier ones, see findHostConflicts.
+	ingresses = slices.Clone(ingresses)
+	sort.SliceStable(ingresses, func(i, j int) bool {
+		return claimedBefore(ingresses[i], ingresses[j])
+	})
+
+	ctx = config.ToContext(ctx, cfg)
+	ctx = controller.WithEventRecorder(ctx, &record.FakeRecorder{})
+	for _, ing := range ingresses {
+		// The listers are rebuilt for every Ingress to see the changes made for the previous ones.
+		r := &Reconciler{
+			kubeclient:     kubeClient,
+			istioClientSet: istioClient,
+			tracker:        tracker.New(func(types.NamespacedName) {}, time.Minute),
+			statusManager: &fakestatusmanager.FakeStatusManager{
+				FakeIsReady: func(context.Context, *v1alpha1.Ingress) (bool, error) {
+					return true, nil
+				},
+			},
+		}
+		if err := r.loadListers(ctx, istioClient, kubeClient, ingresses); err != nil {
+			return nil, err
+		}
+		if err := r.ReconcileKind(ctx, ing.DeepCopy()); err != nil {
+			return nil, fmt.Errorf("failed to render Ingress %s/%s: %w", ing.Namespace, ing.Name, err)
+		}
+	}
+
+	vses, err := istioClient.NetworkingV1beta1().VirtualServices(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	gateways, err := istioClient.NetworkingV1beta1().Gateways(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	secrets, err := kubeClient.CoreV1().Secrets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]runtime.Object, 0, len(vses.Items)+len(gateways.Items)+len(secrets.Items))
+	for _, vs := range vses.Items {
+		vs.TypeMeta = metav1.TypeMeta{APIVersion: v1beta1.SchemeGroupVersion.String(), Kind: "VirtualService"}
+		ret = append(ret, vs)
+	}
+	for _, gw := range gateways.Items {
+		gw.TypeMeta = metav1.TypeMeta{APIVersion: v1beta1.SchemeGroupVersion.String(), Kind: "Gateway"}
+		ret = append(ret, gw)
+	}
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if inputSecrets.Has(secret.Namespace + "/" + secret.Name) {
+			continue
+		}
+		secret.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"}
+		ret = append(ret, secret)
+	}
+
+	for _, obj := range ret {
+		obj.(metav1.Object).SetResourceVersion("")
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		a, b := ret[i].(metav1.Object), ret[j].(metav1.Object)
+		ka, kb := ret[i].GetObjectKind().GroupVersionKind().Kind, ret[j].GetObjectKind().GroupVersionKind().Kind
+		if ka != kb {
+			return ka < kb
+		}
+		if a.GetNamespace() != b.GetNamespace() {
+			return a.GetNamespace() < b.GetNamespace()
+		}
+		return a.GetName() < b.GetName()
+	})
+	return ret, nil
+}
+
+// loadListers points the listers of the reconciler to the current content of the clients
+// and to the rendered Ingresses.
+func (r *Reconciler) loadListers(ctx context.Context, istioClient *fakeistioclientset.Clientset, kubeClient *fakekubeclientset.Clientset, ingresses []*v1alpha1.Ingress) error {
+	vses, err := istioClient.NetworkingV1beta1().VirtualServices(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	gateways, err := istioClient.NetworkingV1beta1().Gateways(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	secrets, err := kubeClient.CoreV1().Secrets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	services, err := kubeClient.CoreV1().Services(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	namespaces, err := kubeClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	vsIndexer, err := newIndexer(vses.Items)
+	if err != nil {
+		return err
+	}
+	if err := vsIndexer.AddIndexers(virtualServiceHostIndexers); err != nil {
+		return err
+	}
+	gatewayIndexer, err := newIndexer(gateways.Items)
+	if err != nil {
+		return err
+	}
+	secretIndexer, err := newIndexer(pointers(secrets.Items))
+	if err != nil {
+		return err
+	}
+	serviceIndexer, err := newIndexer(pointers(services.Items))
+	if err != nil {
+		return err
+	}
+	namespaceIndexer, err := newIndexer(pointers(namespaces.Items))
+	if err != nil {
+		return err
+	}
+	ingressIndexer, err := newIndexer(ingresses)
+	if err != nil {
+		return err
+	}
+
+	r.virtualServiceLister = istiolisters.NewVirtualServiceLister(vsIndexer)
+	r.virtualServiceIndexer = vsIndexer
+	r.gatewayLister = istiolisters.NewGatewayLister(gatewayIndexer)
+	r.secretLister = corev1listers.NewSecretLister(secretIndexer)
+	r.svcLister = corev1listers.NewServiceLister(serviceIndexer)
+	r.namespaceLister = corev1listers.NewNamespaceLister(namespaceIndexer)
+	r.ingressLister = networkinglisters.NewIngressLister(ingressIndexer)
+	return nil
+}
+
+func newIndexer[T any](objs []T) (cache.Indexer, error) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, obj := range objs {
+		if err := indexer.Add(obj); err != nil {
+			return nil, err
+		}
+	}
+	return indexer, nil
+}
+
+func pointers[T any](items []T) []*T {
+	ret := make([]*T, 0, len(items))
+	for i := range items {
+		ret = append(ret, &items[i])
+	}
+	return ret
+}
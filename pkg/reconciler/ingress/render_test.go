@@ -0,0 +1,166 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/net-istio/pkg/reconciler/ingress/config"
+	"knative.dev/net-istio/pkg/reconciler/ingress/resources"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+	netconfig "knative.dev/networking/pkg/config"
+	pkgnet "knative.dev/pkg/network"
+	"knative.dev/pkg/system"
+
+	logtesting "knative.dev/pkg/logging/testing"
+	_ "knative.dev/pkg/system/testing"
+)
+
+func TestRender(t *testing.T) {
+	externalDomainTLSConfig := &config.Config{
+		Istio: &config.Istio{
+			IngressGateways: []config.Gateway{{
+				Namespace:  system.Namespace(),
+				Name:       config.KnativeIngressGateway,
+				ServiceURL: pkgnet.GetServiceHostname("istio-ingressgateway", "istio-system"),
+			}},
+		},
+		Network: &netconfig.Config{
+			HTTPProtocol:      netconfig.HTTPDisabled,
+			ExternalDomainTLS: true,
+		},
+	}
+
+	tests := []struct {
+		name      string
+		cfg       *config.Config
+		ingresses []*v1alpha1.Ingress
+		objects   []runtime.Object
+		want      []string
+		wantErr   bool
+	}{{
+		name:      "configured gateways are not given",
+		cfg:       ReconcilerTestConfig(),
+		ingresses: []*v1alpha1.Ingress{ing("hello")},
+		want: []string{
+			"Gateway knative-testing/knative-ingress-gateway",
+			"Gateway knative-testing/knative-test-gateway",
+			"VirtualService test-ns/hello-ingress",
+			"VirtualService test-ns/hello-mesh",
+		},
+	}, {
+		name:      "several ingresses",
+		cfg:       ReconcilerTestConfig(),
+		ingresses: []*v1alpha1.Ingress{ing("hello"), withRoute(ing("world"), "other-route")},
+		want: []string{
+			"Gateway knative-testing/knative-ingress-gateway",
+			"Gateway knative-testing/knative-test-gateway",
+			"VirtualService test-ns/hello-ingress",
+			"VirtualService test-ns/hello-mesh",
+			"VirtualService test-ns/world-ingress",
+			"VirtualService test-ns/world-mesh",
+		},
+	}, {
+		name:      "secret copied to the gateway namespace",
+		cfg:       externalDomainTLSConfig,
+		ingresses: []*v1alpha1.Ingress{ingressWithTLS("reconciling-ingress", ingressTLSWithSecretNamespace("knative-serving"))},
+		objects: []runtime.Object{
+			originSecret("knative-serving", "secret0"),
+			ingressService,
+		},
+		want: []string{
+			"Gateway knative-testing/knative-ingress-gateway",
+			"Gateway test-ns/" + externalIngressTLSGatewayName,
+			"Secret istio-system/" + targetSecretName,
+			"VirtualService test-ns/reconciling-ingress-ingress",
+			"VirtualService test-ns/reconciling-ingress-mesh",
+		},
+	}, {
+		// Ingresses created at the same time claimed their hosts in the order of their namespaces.
+		name:      "host claimed by an Ingress of another namespace",
+		cfg:       ReconcilerTestConfig(),
+		ingresses: []*v1alpha1.Ingress{ing("hello"), ingressInNamespace("a-ns", ing("hello"))},
+		want: []string{
+			"Gateway knative-testing/knative-ingress-gateway",
+			"Gateway knative-testing/knative-test-gateway",
+			"VirtualService a-ns/hello-ingress",
+			"VirtualService a-ns/hello-mesh",
+		},
+	}, {
+		name: "no server nor secret for a host claimed by an Ingress of another namespace",
+		cfg:  externalDomainTLSConfig,
+		ingresses: []*v1alpha1.Ingress{
+			ingressWithTLS("hijacking-ingress", ingressTLSWithSecretNamespace("knative-serving")),
+			ingressInNamespace("a-ns", ingressWithTLS("reconciling-ingress", ingressTLSWithSecretNamespace("knative-serving"))),
+		},
+		objects: []runtime.Object{
+			originSecret("knative-serving", "secret0"),
+			ingressService,
+		},
+		// Only the resources of the Ingress that claimed the hosts first are rendered.
+		want: []string{
+			"Gateway a-ns/" + resources.GatewayName(ingressInNamespace("a-ns", ing("reconciling-ingress")), v1alpha1.IngressVisibilityExternalIP, ingressService),
+			"Gateway knative-testing/knative-ingress-gateway",
+			"Secret istio-system/" + targetSecretName,
+			"VirtualService a-ns/reconciling-ingress-ingress",
+			"VirtualService a-ns/reconciling-ingress-mesh",
+		},
+	}, {
+		name:      "unsupported object",
+		cfg:       ReconcilerTestConfig(),
+		ingresses: []*v1alpha1.Ingress{ing("hello")},
+		objects:   []runtime.Object{&corev1.Pod{}},
+		wantErr:   true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := logtesting.TestContextWithLogger(t)
+			objs, err := Render(ctx, test.cfg, test.ingresses, test.objects)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("Render() = %v, wantErr = %v", err, test.wantErr)
+			}
+
+			got := make([]string, 0, len(objs))
+			for _, obj := range objs {
+				meta := obj.(metav1.Object)
+				if meta.GetResourceVersion() != "" {
+					t.Errorf("ResourceVersion of %s/%s = %q, want empty", meta.GetNamespace(), meta.GetName(), meta.GetResourceVersion())
+				}
+				got = append(got, obj.GetObjectKind().GroupVersionKind().Kind+" "+meta.GetNamespace()+"/"+meta.GetName())
+			}
+			if !cmp.Equal(got, test.want, cmpopts.EquateEmpty()) {
+				t.Error("Render() (-want, +got):", cmp.Diff(test.want, got, cmpopts.EquateEmpty()))
+			}
+		})
+	}
+}
+
+func withRoute(ing *v1alpha1.Ingress, route string) *v1alpha1.Ingress {
+	ing.Labels[resources.RouteLabelKey] = route
+	return ing
+}
+
+func ingressInNamespace(namespace string, ing *v1alpha1.Ingress) *v1alpha1.Ingress {
+	ing.Namespace = namespace
+	return ing
+}
@@ -0,0 +1,307 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	istiov1beta1 "istio.io/api/networking/v1beta1"
+	"istio.io/client-go/pkg/apis/networking/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"knative.dev/net-istio/pkg/reconciler/ingress/config"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+)
+
+// externalGatewaysByHost places each public host of the given Ingress on the external gateways
+// with the most specific domain matching it. Hosts not matching any domain are placed on the
+// given gateways selected for the whole Ingress.
+func externalGatewaysByHost(gtws []config.Gateway, ing *v1alpha1.Ingress, selected []config.Gateway) map[string][]config.Gateway {
+	ret := make(map[string][]config.Gateway)
+	for _, rule := range getPublicIngressRules(ing) {
+		for _, host := range rule.Hosts {
+			if _, ok := ret[host]; ok {
+				continue
+			}
+			ret[host] = selected
+			longest := 0
+			for _, gtw := range gtws {
+				switch match := gtw.MatchDomain(host); {
+				case match == 0 || match < longest:
+				case match > longest:
+					longest = match
+					ret[host] = []config.Gateway{gtw}
+				default:
+					ret[host] = append(ret[host], gtw)
+				}
+			}
+		}
+	}
+	return ret
+}
+
+// unionGateways returns the gateways of the given hosts, in the order of the given gateways.
+func unionGateways(gtws []config.Gateway, byHost map[string][]config.Gateway) []config.Gateway {
+	names := sets.New[string]()
+	for _, hostGateways := range byHost {
+		for _, gtw := range hostGateways {
+			names.Insert(gtw.QualifiedName())
+		}
+	}
+	ret := make([]config.Gateway, 0, names.Len())
+	for _, gtw := range gtws {
+		if names.Has(gtw.QualifiedName()) {
+			ret = append(ret, gtw)
+		}
+	}
+	return ret
+}
+
+// ExternalGatewaysByHost returns the external gateways serving each public host of the given
+// Ingress. It returns nil if no gateway selects hosts by domain, in which case all the public
+// hosts are served by all the external gateways of the Ingress.
+func ExternalGatewaysByHost(ctx context.Context, ing *v1alpha1.Ingress) (map[string][]config.Gateway, error) {
+	istioConfig := config.FromContext(ctx).Istio
+	if !istioConfig.UsesDomains() {
+		return nil, nil
+	}
+	selected, err := selectExternalGateways(istioConfig, ing.GetLabels(), namespaceLabelsFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return externalGatewaysByHost(istioConfig.IngressGateways, ing, selected), nil
+}
+
+// gatewayServiceHosts returns the public hosts of the given Ingress served by each external
+// gateway service, keyed by `namespace/name`. It returns nil if all the public hosts are served
+// by all the gateway services.
+func gatewayServiceHosts(ctx context.Context, ing *v1alpha1.Ingress) (map[string]sets.Set[string], error) {
+	byHost, err := ExternalGatewaysByHost(ctx, ing)
+	if err != nil || byHost == nil {
+		return nil, err
+	}
+	ret := make(map[string]sets.Set[string])
+	for host, gtws := range byHost {
+		for _, gtw := range gtws {
+			meta, err := parseIngressGatewayConfig(gtw)
+			if err != nil {
+				return nil, err
+			}
+			key := meta.Namespace + "/" + meta.Name
+			if _, ok := ret[key]; !ok {
+				ret[key] = sets.New[string]()
+			}
+			ret[key].Insert(host)
+		}
+	}
+	return ret, nil
+}
+
+// gatewayServiceOf returns the key of the gateway service, out of the given ones, the given
+// per-Ingress or wildcard Gateway was made for.
+func gatewayServiceOf(ing *v1alpha1.Ingress, gateway *v1beta1.Gateway, serviceKeys sets.Set[string]) (string, bool) {
+	for _, key := range sets.List(serviceKeys) {
+		namespace, name, _ := strings.Cut(key, "/")
+		svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+		if gateway.Name == GatewayName(ing, v1alpha1.IngressVisibilityExternalIP, svc) {
+			return key, true
+		}
+		if owner := metav1.GetControllerOf(gateway); owner != nil && owner.Kind == "Secret" &&
+			gateway.Name == WildcardGatewayName(owner.Name, namespace, name) {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// servedHosts returns the given hosts served by one of the servers of the given Gateway.
+func servedHosts(gateway *v1beta1.Gateway, hosts sets.Set[string]) sets.Set[string] {
+	ret := sets.New[string]()
+	for host := range hosts {
+		for _, server := range gateway.Spec.GetServers() {
+			if coveredByWildcard(host, server.GetHosts()) {
+				ret.Insert(host)
+				break
+			}
+		}
+	}
+	return ret
+}
+
+// coveredByWildcard returns true if the host is matched by one of the given wildcard hosts.
+// Following certificate name matching rules, a wildcard only matches a single DNS label,
+// e.g. *.example.com matches foo.example.com but neither example.com nor foo.bar.example.com.
+func coveredByWildcard(host string, wildcardHosts []string) bool {
+	for _, wildcard := range wildcardHosts {
+		if host == wildcard {
+			return true
+		}
+		if i := strings.Index(host, "."); i > 0 && host[i:] == strings.TrimPrefix(wildcard, "*") {
+			return true
+		}
+	}
+	return false
+}
+
+// RestrictGatewaysToHosts restricts the servers of the given external per-Ingress Gateways to
+// the public hosts of the Ingress placed on their gateway service by domain, and drops the
+// Gateways left without servers. The servers of the shared wildcard Gateways are left as they
+// are, but the wildcard Gateways not serving any of those hosts are dropped as well.
+func RestrictGatewaysToHosts(ctx context.Context, ing *v1alpha1.Ingress, gateways []*v1beta1.Gateway) ([]*v1beta1.Gateway, error) {
+	serviceHosts, err := gatewayServiceHosts(ctx, ing)
+	if err != nil || serviceHosts == nil {
+		return gateways, err
+	}
+	ret := make([]*v1beta1.Gateway, 0, len(gateways))
+	for _, gateway := range gateways {
+		key, ok := gatewayServiceOf(ing, gateway, sets.KeySet(serviceHosts))
+		if !ok {
+			// The gateway service does not serve any of the public hosts.
+			continue
+		}
+		hosts := serviceHosts[key]
+		if !metav1.IsControlledBy(gateway, ing) {
+			if servedHosts(gateway, hosts).Len() > 0 {
+				ret = append(ret, gateway)
+			}
+			continue
+		}
+		servers := make([]*istiov1beta1.Server, 0, len(gateway.Spec.GetServers()))
+		for _, server := range gateway.Spec.GetServers() {
+			serverHosts := slices.DeleteFunc(slices.Clone(server.GetHosts()), func(host string) bool {
+				return !hosts.Has(host)
+			})
+			if len(serverHosts) == 0 {
+				continue
+			}
+			// The servers may be shared by the Gateways of several gateway services.
+			server = proto.Clone(server).(*istiov1beta1.Server)
+			server.Hosts = serverHosts
+			servers = append(servers, server)
+		}
+		if len(servers) > 0 {
+			gateway.Spec.Servers = servers
+			ret = append(ret, gateway)
+		}
+	}
+	return ret, nil
+}
+
+// ExternalGatewayHosts returns the public hosts of the given Ingress served by each external
+// Gateway, keyed by the qualified Gateway name. This covers the gateways of config-istio as well
+// as the given per-Ingress and wildcard Gateways. It returns nil if all the public hosts are
+// served by all the external Gateways.
+func ExternalGatewayHosts(ctx context.Context, ing *v1alpha1.Ingress, gateways []*v1beta1.Gateway) (map[string]sets.Set[string], error) {
+	byHost, err := ExternalGatewaysByHost(ctx, ing)
+	if err != nil || byHost == nil {
+		return nil, err
+	}
+	ret := make(map[string]sets.Set[string])
+	for host, gtws := range byHost {
+		for _, gtw := range gtws {
+			if _, ok := ret[gtw.QualifiedName()]; !ok {
+				ret[gtw.QualifiedName()] = sets.New[string]()
+			}
+			ret[gtw.QualifiedName()].Insert(host)
+		}
+	}
+
+	if len(gateways) == 0 {
+		return ret, nil
+	}
+	serviceHosts, err := gatewayServiceHosts(ctx, ing)
+	if err != nil {
+		return nil, err
+	}
+	for _, gateway := range gateways {
+		hosts := sets.New[string]()
+		if key, ok := gatewayServiceOf(ing, gateway, sets.KeySet(serviceHosts)); ok {
+			hosts = servedHosts(gateway, serviceHosts[key])
+		}
+		ret[gateway.Namespace+"/"+gateway.Name] = hosts
+	}
+	return ret, nil
+}
+
+// RestrictToGatewayHosts removes the Gateways from the matches of the given VirtualService that
+// do not serve any of the hosts matched, following the hosts served by each Gateway as returned
+// by ExternalGatewayHosts. Gateways missing from gatewayHosts are kept. Matches left without
+// Gateways are removed, as well as routes left without matches.
+func RestrictToGatewayHosts(vs *v1beta1.VirtualService, gatewayHosts map[string]sets.Set[string]) {
+	if gatewayHosts == nil {
+		return
+	}
+	restrict := func(gateways []string, matches func(host string) bool) []string {
+		return slices.DeleteFunc(slices.Clone(gateways), func(gateway string) bool {
+			hosts, ok := gatewayHosts[gateway]
+			if !ok {
+				return false
+			}
+			for host := range hosts {
+				if matches(host) {
+					return false
+				}
+			}
+			return true
+		})
+	}
+
+	used := sets.New[string]()
+	httpRoutes := make([]*istiov1beta1.HTTPRoute, 0, len(vs.Spec.GetHttp()))
+	for _, route := range vs.Spec.GetHttp() {
+		matches := make([]*istiov1beta1.HTTPMatchRequest, 0, len(route.GetMatch()))
+		for _, match := range route.GetMatch() {
+			prefix := match.GetAuthority().GetPrefix()
+			match.Gateways = restrict(match.GetGateways(), func(host string) bool {
+				return strings.HasPrefix(host, prefix)
+			})
+			if len(match.GetGateways()) > 0 {
+				matches = append(matches, match)
+				used.Insert(match.GetGateways()...)
+			}
+		}
+		if len(matches) > 0 {
+			route.Match = matches
+			httpRoutes = append(httpRoutes, route)
+		}
+	}
+	vs.Spec.Http = httpRoutes
+
+	tlsRoutes := make([]*istiov1beta1.TLSRoute, 0, len(vs.Spec.GetTls()))
+	for _, route := range vs.Spec.GetTls() {
+		matches := make([]*istiov1beta1.TLSMatchAttributes, 0, len(route.GetMatch()))
+		for _, match := range route.GetMatch() {
+			sniHosts := sets.New(match.GetSniHosts()...)
+			match.Gateways = restrict(match.GetGateways(), sniHosts.Has)
+			if len(match.GetGateways()) > 0 {
+				matches = append(matches, match)
+				used.Insert(match.GetGateways()...)
+			}
+		}
+		if len(matches) > 0 {
+			route.Match = matches
+			tlsRoutes = append(tlsRoutes, route)
+		}
+	}
+	vs.Spec.Tls = tlsRoutes
+
+	vs.Spec.Gateways = sets.List(used)
+}
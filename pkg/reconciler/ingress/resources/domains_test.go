@@ -0,0 +1,206 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+	istiov1beta1 "istio.io/api/networking/v1beta1"
+	"istio.io/client-go/pkg/apis/networking/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"knative.dev/net-istio/pkg/reconciler/ingress/config"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+)
+
+var (
+	domainsConfig = &config.Config{
+		Istio: &config.Istio{
+			IngressGateways: []config.Gateway{{
+				Namespace:  "knative-serving",
+				Name:       "knative-ingress-gateway",
+				ServiceURL: "istio-ingressgateway.istio-system.svc.cluster.local",
+			}, {
+				Namespace:  "knative-serving",
+				Name:       "internal-gateway",
+				ServiceURL: "internal-ingressgateway.istio-system.svc.cluster.local",
+				Domains:    []string{"*.internal.example.com"},
+			}},
+		},
+	}
+
+	domainsIngress = &v1alpha1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: "ingress", Namespace: "default"},
+		Spec: v1alpha1.IngressSpec{Rules: []v1alpha1.IngressRule{{
+			Hosts:      []string{"foo.example.com", "foo.internal.example.com"},
+			Visibility: v1alpha1.IngressVisibilityExternalIP,
+		}}},
+	}
+
+	defaultDomainsService  = &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "istio-system", Name: "istio-ingressgateway"}}
+	internalDomainsService = &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "istio-system", Name: "internal-ingressgateway"}}
+)
+
+func TestRestrictGatewaysToHosts(t *testing.T) {
+	ctx := config.ToContext(context.Background(), domainsConfig)
+	server := MakeHTTPServer(v1alpha1.HTTPOptionEnabled, []string{"foo.example.com", "foo.internal.example.com"})
+	gateways := []*v1beta1.Gateway{
+		makeIngressGateway(domainsIngress, v1alpha1.IngressVisibilityExternalIP, selector, []*istiov1beta1.Server{server}, defaultDomainsService),
+		makeIngressGateway(domainsIngress, v1alpha1.IngressVisibilityExternalIP, selector, []*istiov1beta1.Server{server}, internalDomainsService),
+	}
+
+	got, err := RestrictGatewaysToHosts(ctx, domainsIngress, gateways)
+	if err != nil {
+		t.Fatal("RestrictGatewaysToHosts() =", err)
+	}
+
+	want := []*v1beta1.Gateway{
+		makeIngressGateway(domainsIngress, v1alpha1.IngressVisibilityExternalIP, selector,
+			[]*istiov1beta1.Server{MakeHTTPServer(v1alpha1.HTTPOptionEnabled, []string{"foo.example.com"})}, defaultDomainsService),
+		makeIngressGateway(domainsIngress, v1alpha1.IngressVisibilityExternalIP, selector,
+			[]*istiov1beta1.Server{MakeHTTPServer(v1alpha1.HTTPOptionEnabled, []string{"foo.internal.example.com"})}, internalDomainsService),
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Error("Unexpected Gateways (-want, +got):", diff)
+	}
+	if got := server.GetHosts(); len(got) != 2 {
+		t.Errorf("The shared server was modified, hosts = %v", got)
+	}
+}
+
+func TestRestrictGatewaysToHostsWithoutDomains(t *testing.T) {
+	server := MakeHTTPServer(v1alpha1.HTTPOptionEnabled, []string{"foo.example.com", "foo.internal.example.com"})
+	gateways := []*v1beta1.Gateway{
+		makeIngressGateway(domainsIngress, v1alpha1.IngressVisibilityExternalIP, selector, []*istiov1beta1.Server{server}, defaultDomainsService),
+	}
+
+	got, err := RestrictGatewaysToHosts(config.ToContext(context.Background(), configDefaultGateway), domainsIngress, gateways)
+	if err != nil {
+		t.Fatal("RestrictGatewaysToHosts() =", err)
+	}
+	if diff := cmp.Diff(gateways, got, protocmp.Transform()); diff != "" {
+		t.Error("Unexpected Gateways (-want, +got):", diff)
+	}
+}
+
+func TestExternalGatewayHosts(t *testing.T) {
+	ctx := config.ToContext(context.Background(), domainsConfig)
+	perIngress := makeIngressGateway(domainsIngress, v1alpha1.IngressVisibilityExternalIP, selector,
+		[]*istiov1beta1.Server{MakeHTTPServer(v1alpha1.HTTPOptionEnabled, []string{"foo.internal.example.com"})}, internalDomainsService)
+
+	got, err := ExternalGatewayHosts(ctx, domainsIngress, []*v1beta1.Gateway{perIngress})
+	if err != nil {
+		t.Fatal("ExternalGatewayHosts() =", err)
+	}
+
+	want := map[string]sets.Set[string]{
+		"knative-serving/knative-ingress-gateway": sets.New("foo.example.com"),
+		"knative-serving/internal-gateway":        sets.New("foo.internal.example.com"),
+		"default/" + perIngress.Name:              sets.New("foo.internal.example.com"),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error("Unexpected hosts (-want, +got):", diff)
+	}
+}
+
+func TestCoveredByWildcard(t *testing.T) {
+	wildcardHosts := []string{"*.example.com", "*.ns.example.com"}
+	cases := []struct {
+		host string
+		want bool
+	}{{
+		host: "foo.example.com",
+		want: true,
+	}, {
+		host: "foo.ns.example.com",
+		want: true,
+	}, {
+		host: "*.example.com",
+		want: true,
+	}, {
+		host: "example.com",
+		want: false,
+	}, {
+		host: "foo.bar.example.com",
+		want: false,
+	}, {
+		host: "foo.example.org",
+		want: false,
+	}}
+	for _, c := range cases {
+		if got := coveredByWildcard(c.host, wildcardHosts); got != c.want {
+			t.Errorf("coveredByWildcard(%q) = %v, want %v", c.host, got, c.want)
+		}
+	}
+}
+
+func TestRestrictToGatewayHosts(t *testing.T) {
+	vs := &v1beta1.VirtualService{
+		Spec: istiov1beta1.VirtualService{
+			Gateways: []string{"knative-serving/internal-gateway", "knative-serving/knative-ingress-gateway", "mesh"},
+			Http: []*istiov1beta1.HTTPRoute{{
+				Match: []*istiov1beta1.HTTPMatchRequest{{
+					Gateways:  []string{"knative-serving/internal-gateway", "knative-serving/knative-ingress-gateway"},
+					Authority: &istiov1beta1.StringMatch{MatchType: &istiov1beta1.StringMatch_Prefix{Prefix: "foo.example.com"}},
+				}, {
+					Gateways:  []string{"knative-serving/internal-gateway", "knative-serving/knative-ingress-gateway"},
+					Authority: &istiov1beta1.StringMatch{MatchType: &istiov1beta1.StringMatch_Prefix{Prefix: "foo.internal.example.com"}},
+				}},
+			}, {
+				Match: []*istiov1beta1.HTTPMatchRequest{{
+					Gateways:  []string{"mesh"},
+					Authority: &istiov1beta1.StringMatch{MatchType: &istiov1beta1.StringMatch_Prefix{Prefix: "foo.default"}},
+				}},
+			}, {
+				Match: []*istiov1beta1.HTTPMatchRequest{{
+					Gateways:  []string{"knative-serving/internal-gateway"},
+					Authority: &istiov1beta1.StringMatch{MatchType: &istiov1beta1.StringMatch_Prefix{Prefix: "foo.example.com"}},
+				}},
+			}},
+		},
+	}
+
+	RestrictToGatewayHosts(vs, map[string]sets.Set[string]{
+		"knative-serving/knative-ingress-gateway": sets.New("foo.example.com"),
+		"knative-serving/internal-gateway":        sets.New("foo.internal.example.com"),
+	})
+
+	want := istiov1beta1.VirtualService{
+		Gateways: []string{"knative-serving/internal-gateway", "knative-serving/knative-ingress-gateway", "mesh"},
+		Http: []*istiov1beta1.HTTPRoute{{
+			Match: []*istiov1beta1.HTTPMatchRequest{{
+				Gateways:  []string{"knative-serving/knative-ingress-gateway"},
+				Authority: &istiov1beta1.StringMatch{MatchType: &istiov1beta1.StringMatch_Prefix{Prefix: "foo.example.com"}},
+			}, {
+				Gateways:  []string{"knative-serving/internal-gateway"},
+				Authority: &istiov1beta1.StringMatch{MatchType: &istiov1beta1.StringMatch_Prefix{Prefix: "foo.internal.example.com"}},
+			}},
+		}, {
+			Match: []*istiov1beta1.HTTPMatchRequest{{
+				Gateways:  []string{"mesh"},
+				Authority: &istiov1beta1.StringMatch{MatchType: &istiov1beta1.StringMatch_Prefix{Prefix: "foo.default"}},
+			}},
+		}},
+	}
+	if diff := cmp.Diff(&want, &vs.Spec, protocmp.Transform()); diff != "" {
+		t.Error("Unexpected VirtualService (-want, +got):", diff)
+	}
+}
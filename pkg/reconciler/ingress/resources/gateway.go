@@ -91,13 +91,26 @@ func GetHTTPServer(gateway *v1beta1.Gateway) *istiov1beta1.Server {
 }
 
 func belongsToIngress(server *istiov1beta1.Server, ing *v1alpha1.Ingress) bool {
+	prefix, ok := ServerPortNamePrefix(server)
+	return ok && prefix == IngressPortNamePrefix(ing)
+}
+
+// ServerPortNamePrefix returns the part of the port name of the server that identifies
+// the Ingress the server was made for, and false if the server was not made for an Ingress.
+func ServerPortNamePrefix(server *istiov1beta1.Server) (string, bool) {
 	// The format of the portName should be "<namespace>/<ingress_name>:<number>".
 	// For example, default/routetest:0.
 	portNameSplits := strings.Split(server.GetPort().GetName(), ":")
-	if len(portNameSplits) != 2 {
-		return false
+	if len(portNameSplits) != 2 || !strings.Contains(portNameSplits[0], "/") {
+		return "", false
 	}
-	return portNameSplits[0] == portNamePrefix(ing.GetNamespace(), ing.GetName())
+	return portNameSplits[0], true
+}
+
+// IngressPortNamePrefix returns the prefix of the port names of the servers made for the
+// given Ingress.
+func IngressPortNamePrefix(ing kmeta.Accessor) string {
+	return portNamePrefix(ing.GetNamespace(), ing.GetName())
 }
 
 // SortServers sorts `Server` according to its port name.
@@ -173,6 +186,11 @@ func makeWildcardTLSGateways(originWildcardSecrets map[string]*corev1.Secret,
 ) ([]*v1beta1.Gateway, error) {
 	gateways := make([]*v1beta1.Gateway, 0, len(originWildcardSecrets))
 	for _, secret := range originWildcardSecrets {
+		// All the hosts of the certificate are served by the shared server, including the
+		// explicit ones next to its wildcard hosts (e.g. example.com next to *.example.com).
+		// Serving them from per-Ingress servers would make two servers share the certificate,
+		// and HTTP/2 clients reusing a connection for the other hosts of the certificate get
+		// 404s from the gateway.
 		hosts, err := GetHostsFromCertSecret(secret)
 		if err != nil {
 			return nil, err
@@ -379,6 +397,22 @@ func MakeHTTPServer(httpOption v1alpha1.HTTPOption, hosts []string) *istiov1beta
 	return server
 }
 
+// MakePassthroughServer creates a Gateway `Server` that passes the TLS traffic for the given
+// hosts through to the workloads of the given Ingress without terminating it.
+func MakePassthroughServer(ing *v1alpha1.Ingress, hosts []string) *istiov1beta1.Server {
+	return &istiov1beta1.Server{
+		Hosts: hosts,
+		Port: &istiov1beta1.Port{
+			Name:     portNamePrefix(ing.GetNamespace(), ing.GetName()) + ":0",
+			Number:   ExternalGatewayHTTPSPort,
+			Protocol: "TLS",
+		},
+		Tls: &istiov1beta1.ServerTLSSettings{
+			Mode: istiov1beta1.ServerTLSSettings_PASSTHROUGH,
+		},
+	}
+}
+
 // GetNonWildcardIngressTLS gets Ingress TLS that do not reference wildcard certificates.
 func GetNonWildcardIngressTLS(ingressTLS []v1alpha1.IngressTLS, nonWildcardSecrets map[string]*corev1.Secret) []v1alpha1.IngressTLS {
 	result := []v1alpha1.IngressTLS{}
@@ -484,26 +518,42 @@ func QualifiedGatewayNamesFromContext(ctx context.Context, obj kmeta.Accessor) (
 	return ret, nil
 }
 
+type namespaceLabelsKey struct{}
+
+// WithNamespaceLabels attaches the labels of the namespace of the Ingress being reconciled
+// to the context, so that gateways can select the Ingress by namespace.
+func WithNamespaceLabels(ctx context.Context, namespaceLabels map[string]string) context.Context {
+	return context.WithValue(ctx, namespaceLabelsKey{}, namespaceLabels)
+}
+
+func namespaceLabelsFromContext(ctx context.Context) map[string]string {
+	namespaceLabels, _ := ctx.Value(namespaceLabelsKey{}).(map[string]string)
+	return namespaceLabels
+}
+
 // GatewaysFromContext get gateways relevant to this ingress from context.
 func GatewaysFromContext(ctx context.Context, obj kmeta.Accessor) (map[v1alpha1.IngressVisibility][]config.Gateway, error) {
 	ret := make(map[v1alpha1.IngressVisibility][]config.Gateway)
 
 	istioConfig := config.FromContext(ctx).Istio
+	namespaceLabels := namespaceLabelsFromContext(ctx)
 
 	// External gateways selection
-	externalGateways, err := filterGateway(istioConfig.IngressGateways, obj.GetLabels())
+	externalGateways, err := selectExternalGateways(istioConfig, obj.GetLabels(), namespaceLabels)
 	if err != nil {
-		return ret, fmt.Errorf("failed to filter external gateways: %w", err)
+		return ret, err
 	}
 
-	if len(externalGateways) == 0 {
-		externalGateways = istioConfig.DefaultExternalGateways()
+	// Hosts matching the domains of a gateway are served by that gateway instead.
+	if ing, ok := obj.(*v1alpha1.Ingress); ok && istioConfig.UsesDomains() && len(getPublicIngressRules(ing)) > 0 {
+		externalGateways = unionGateways(istioConfig.IngressGateways,
+			externalGatewaysByHost(istioConfig.IngressGateways, ing, externalGateways))
 	}
 
 	ret[v1alpha1.IngressVisibilityExternalIP] = externalGateways
 
 	// Local gateways selection
-	localGateways, err := filterGateway(istioConfig.LocalGateways, obj.GetLabels())
+	localGateways, err := filterGateway(istioConfig.LocalGateways, obj.GetLabels(), namespaceLabels)
 	if err != nil {
 		return ret, fmt.Errorf("failed to filter local gateways: %w", err)
 	}
@@ -517,21 +567,52 @@ func GatewaysFromContext(ctx context.Context, obj kmeta.Accessor) (map[v1alpha1.
 	return ret, nil
 }
 
-func filterGateway(gtws []config.Gateway, ingressLabels map[string]string) ([]config.Gateway, error) {
+// selectExternalGateways returns the external gateways selecting an Ingress with the given
+// labels in a namespace with the given labels, or the default external gateway if none does.
+func selectExternalGateways(istioConfig *config.Istio, ingressLabels, namespaceLabels map[string]string) ([]config.Gateway, error) {
+	externalGateways, err := filterGateway(istioConfig.IngressGateways, ingressLabels, namespaceLabels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter external gateways: %w", err)
+	}
+
+	if len(externalGateways) == 0 {
+		externalGateways = istioConfig.DefaultExternalGateways()
+	}
+
+	return externalGateways, nil
+}
+
+// filterGateway returns the gateways selecting an Ingress with the given labels in a namespace
+// with the given labels. A gateway with both a label and a namespace selector only selects
+// the Ingress if both match.
+func filterGateway(gtws []config.Gateway, ingressLabels, namespaceLabels map[string]string) ([]config.Gateway, error) {
 	ret := make([]config.Gateway, 0, 1)
 
 	for _, gtw := range gtws {
-		if gtw.LabelSelector == nil { // default value
+		if gtw.LabelSelector == nil && gtw.NamespaceSelector == nil { // default value
 			continue
 		}
 
-		selector, err := metav1.LabelSelectorAsSelector(gtw.LabelSelector)
-		if err != nil {
-			return ret, fmt.Errorf("failed to create selector from gateway (%s) label selector: %w", gtw.QualifiedName(), err)
+		if gtw.LabelSelector != nil {
+			selector, err := metav1.LabelSelectorAsSelector(gtw.LabelSelector)
+			if err != nil {
+				return ret, fmt.Errorf("failed to create selector from gateway (%s) label selector: %w", gtw.QualifiedName(), err)
+			}
+
+			if !selector.Matches(labels.Set(ingressLabels)) {
+				continue
+			}
 		}
 
-		if !selector.Matches(labels.Set(ingressLabels)) {
-			continue
+		if gtw.NamespaceSelector != nil {
+			selector, err := metav1.LabelSelectorAsSelector(gtw.NamespaceSelector)
+			if err != nil {
+				return ret, fmt.Errorf("failed to create selector from gateway (%s) namespace selector: %w", gtw.QualifiedName(), err)
+			}
+
+			if !selector.Matches(labels.Set(namespaceLabels)) {
+				continue
+			}
 		}
 
 		ret = append(ret, gtw)
@@ -63,6 +63,8 @@ var wildcardSecrets = map[string]*corev1.Secret{
 	system.Namespace() + "/secret0": wildcardSecret,
 }
 
+var mixedWildcardSecret, _ = GenerateCertificate([]string{"example.com", "*.example.com", "*.ns.example.com", "foo.example.com"}, "secret0", system.Namespace())
+
 var originSecrets = map[string]*corev1.Secret{
 	system.Namespace() + "/secret0": &secret,
 }
@@ -205,6 +207,36 @@ func TestGetServers(t *testing.T) {
 	}
 }
 
+func TestServerPortNamePrefix(t *testing.T) {
+	tests := []struct {
+		portName   string
+		wantPrefix string
+		wantOK     bool
+	}{{
+		portName:   "test-ns/ingress:0",
+		wantPrefix: "test-ns/ingress",
+		wantOK:     true,
+	}, {
+		portName: httpServerPortName,
+	}, {
+		portName: "https:0",
+	}, {
+		portName: "test-ns/ingress",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.portName, func(t *testing.T) {
+			prefix, ok := ServerPortNamePrefix(&istiov1beta1.Server{Port: &istiov1beta1.Port{Name: test.portName}})
+			if prefix != test.wantPrefix || ok != test.wantOK {
+				t.Errorf("ServerPortNamePrefix() = %q, %t, want %q, %t", prefix, ok, test.wantPrefix, test.wantOK)
+			}
+		})
+	}
+	if got, want := IngressPortNamePrefix(&ingressResource), "test-ns/ingress"; got != want {
+		t.Errorf("IngressPortNamePrefix() = %q, want %q", got, want)
+	}
+}
+
 func TestGetHTTPServer(t *testing.T) {
 	newGateway := gateway.DeepCopy()
 	newGateway.Spec.Servers = append(newGateway.Spec.Servers, &httpServer)
@@ -581,6 +613,42 @@ func TestMakeWildcardGateways(t *testing.T) {
 				}},
 			},
 		}},
+	}, {
+		name: "certificate with mixed hosts: all the hosts are served by one server",
+		wildcardSecrets: map[string]*corev1.Secret{
+			system.Namespace() + "/secret0": mixedWildcardSecret,
+		},
+		gatewayService: &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "istio-ingressgateway",
+				Namespace: "istio-system",
+			},
+			Spec: corev1.ServiceSpec{
+				Selector: selector,
+			},
+		},
+		want: []*v1beta1.Gateway{{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            WildcardGatewayName(mixedWildcardSecret.Name, "istio-system", "istio-ingressgateway"),
+				Namespace:       system.Namespace(),
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(mixedWildcardSecret, secretGVK)},
+			},
+			Spec: istiov1beta1.Gateway{
+				Selector: selector,
+				Servers: []*istiov1beta1.Server{{
+					Hosts: []string{"example.com", "*.example.com", "*.ns.example.com", "foo.example.com"},
+					Port: &istiov1beta1.Port{
+						Name:     "https",
+						Number:   ExternalGatewayHTTPSPort,
+						Protocol: "HTTPS",
+					},
+					Tls: &istiov1beta1.ServerTLSSettings{
+						Mode:           istiov1beta1.ServerTLSSettings_SIMPLE,
+						CredentialName: targetWildcardSecretName(mixedWildcardSecret.Name, mixedWildcardSecret.Namespace),
+					},
+				}},
+			},
+		}},
 	}, {
 		name:            "error to make gateway because of incorrect originSecrets",
 		wildcardSecrets: map[string]*corev1.Secret{"": &secret},
@@ -623,6 +691,34 @@ func TestMakeWildcardGateways(t *testing.T) {
 	}
 }
 
+func TestMakePassthroughServer(t *testing.T) {
+	ing := &v1alpha1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ingress",
+			Namespace: "test-ns",
+		},
+	}
+	want := &istiov1beta1.Server{
+		Hosts: []string{"host1.example.com", "host2.example.com"},
+		Port: &istiov1beta1.Port{
+			Name:     "test-ns/ingress:0",
+			Number:   ExternalGatewayHTTPSPort,
+			Protocol: "TLS",
+		},
+		Tls: &istiov1beta1.ServerTLSSettings{
+			Mode: istiov1beta1.ServerTLSSettings_PASSTHROUGH,
+		},
+	}
+	got := MakePassthroughServer(ing, []string{"host1.example.com", "host2.example.com"})
+	if diff := cmp.Diff(want, got, defaultGatewayCmpOpts); diff != "" {
+		t.Error("Unexpected server (-want, +got):", diff)
+	}
+	// The server must be recognized as belonging to the Ingress.
+	if !belongsToIngress(got, ing) {
+		t.Error("belongsToIngress() = false, want true")
+	}
+}
+
 func TestGatewayRef(t *testing.T) {
 	gw := &v1beta1.Gateway{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1113,11 +1209,12 @@ func TestParseIngressGatewayConfig(t *testing.T) {
 
 func TestQualifiedGatewayNamesFromContext(t *testing.T) {
 	cases := []struct {
-		name       string
-		cfg        *config.Istio
-		ingress    *v1alpha1.Ingress
-		want       map[v1alpha1.IngressVisibility]sets.Set[string]
-		shouldFail bool
+		name            string
+		cfg             *config.Istio
+		ingress         *v1alpha1.Ingress
+		namespaceLabels map[string]string
+		want            map[v1alpha1.IngressVisibility]sets.Set[string]
+		shouldFail      bool
 	}{
 		{
 			name: "All match",
@@ -1196,6 +1293,108 @@ func TestQualifiedGatewayNamesFromContext(t *testing.T) {
 				v1alpha1.IngressVisibilityClusterLocal: sets.New[string]("ns1/gtw2"),
 			},
 		},
+		{
+			name: "Namespace match",
+			cfg: &config.Istio{
+				IngressGateways: []config.Gateway{
+					{Namespace: "ns1", Name: "gtw1"},
+					{Namespace: "ns1", Name: "internal", NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "internal"}}},
+				},
+				LocalGateways: []config.Gateway{
+					{Namespace: "ns1", Name: "gtw2"},
+				},
+			},
+			ingress:         &v1alpha1.Ingress{},
+			namespaceLabels: map[string]string{"tier": "internal"},
+			want: map[v1alpha1.IngressVisibility]sets.Set[string]{
+				v1alpha1.IngressVisibilityExternalIP:   sets.New[string]("ns1/internal"),
+				v1alpha1.IngressVisibilityClusterLocal: sets.New[string]("ns1/gtw2"),
+			},
+		},
+		{
+			name: "Namespace mismatch",
+			cfg: &config.Istio{
+				IngressGateways: []config.Gateway{
+					{Namespace: "ns1", Name: "gtw1"},
+					{Namespace: "ns1", Name: "internal", NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "internal"}}},
+				},
+				LocalGateways: []config.Gateway{
+					{Namespace: "ns1", Name: "gtw2"},
+				},
+			},
+			ingress:         &v1alpha1.Ingress{},
+			namespaceLabels: map[string]string{"tier": "public"},
+			want: map[v1alpha1.IngressVisibility]sets.Set[string]{
+				v1alpha1.IngressVisibilityExternalIP:   sets.New[string]("ns1/gtw1"),
+				v1alpha1.IngressVisibilityClusterLocal: sets.New[string]("ns1/gtw2"),
+			},
+		},
+		{
+			name: "Label and namespace selectors must both match",
+			cfg: &config.Istio{
+				IngressGateways: []config.Gateway{
+					{Namespace: "ns1", Name: "gtw1"},
+					{
+						Namespace:         "ns1",
+						Name:              "internal-expo",
+						LabelSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{"expo": "my-value"}},
+						NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "internal"}},
+					},
+					{Namespace: "ns1", Name: "internal", NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "internal"}}},
+				},
+				LocalGateways: []config.Gateway{
+					{Namespace: "ns1", Name: "gtw2"},
+					{Namespace: "ns1", Name: "internal-local", NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "internal"}}},
+				},
+			},
+			ingress:         &v1alpha1.Ingress{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"other": "value"}}},
+			namespaceLabels: map[string]string{"tier": "internal"},
+			want: map[v1alpha1.IngressVisibility]sets.Set[string]{
+				v1alpha1.IngressVisibilityExternalIP:   sets.New[string]("ns1/internal"),
+				v1alpha1.IngressVisibilityClusterLocal: sets.New[string]("ns1/internal-local"),
+			},
+		},
+		{
+			name: "Hosts split across domain gateways",
+			cfg: &config.Istio{
+				IngressGateways: []config.Gateway{
+					{Namespace: "ns1", Name: "gtw1"},
+					{Namespace: "ns1", Name: "internal", Domains: []string{"*.internal.example.com"}},
+					{Namespace: "ns1", Name: "unused", Domains: []string{"*.example.org"}},
+				},
+				LocalGateways: []config.Gateway{
+					{Namespace: "ns1", Name: "gtw2"},
+				},
+			},
+			ingress: &v1alpha1.Ingress{Spec: v1alpha1.IngressSpec{Rules: []v1alpha1.IngressRule{{
+				Hosts:      []string{"foo.example.com", "foo.internal.example.com"},
+				Visibility: v1alpha1.IngressVisibilityExternalIP,
+			}}}},
+			want: map[v1alpha1.IngressVisibility]sets.Set[string]{
+				v1alpha1.IngressVisibilityExternalIP:   sets.New[string]("ns1/gtw1", "ns1/internal"),
+				v1alpha1.IngressVisibilityClusterLocal: sets.New[string]("ns1/gtw2"),
+			},
+		},
+		{
+			name: "All hosts on a domain gateway",
+			cfg: &config.Istio{
+				IngressGateways: []config.Gateway{
+					{Namespace: "ns1", Name: "gtw1"},
+					{Namespace: "ns1", Name: "internal", Domains: []string{"*.internal.example.com"}},
+				},
+				LocalGateways: []config.Gateway{
+					{Namespace: "ns1", Name: "gtw2"},
+				},
+			},
+			ingress: &v1alpha1.Ingress{Spec: v1alpha1.IngressSpec{Rules: []v1alpha1.IngressRule{{
+				Hosts:      []string{"foo.internal.example.com"},
+				Visibility: v1alpha1.IngressVisibilityExternalIP,
+			}}}},
+			want: map[v1alpha1.IngressVisibility]sets.Set[string]{
+				v1alpha1.IngressVisibilityExternalIP:   sets.New[string]("ns1/internal"),
+				v1alpha1.IngressVisibilityClusterLocal: sets.New[string]("ns1/gtw2"),
+			},
+		},
 		{
 			name: "No annotation",
 			cfg: &config.Istio{
@@ -1217,6 +1416,7 @@ func TestQualifiedGatewayNamesFromContext(t *testing.T) {
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
 			ctx := config.ToContext(context.Background(), &config.Config{Istio: c.cfg})
+			ctx = WithNamespaceLabels(ctx, c.namespaceLabels)
 
 			got, err := QualifiedGatewayNamesFromContext(ctx, c.ingress)
 			if c.shouldFail && (err == nil) {
@@ -0,0 +1,117 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	istiov1beta1 "istio.io/api/networking/v1beta1"
+	"istio.io/client-go/pkg/apis/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"knative.dev/net-istio/pkg/reconciler/ingress/config"
+	"knative.dev/networking/pkg/http/header"
+	"knative.dev/pkg/kmeta"
+)
+
+// HTTPSRedirectFromContext returns the HTTPS redirect settings of the given Ingress, that is
+// the settings of config-istio overridden by the annotations of the Ingress, e.g.
+// `istio.networking.knative.dev/https-redirect-code: "308"`.
+func HTTPSRedirectFromContext(ctx context.Context, ing kmeta.Accessor) (config.HTTPSRedirect, error) {
+	redirect, err := config.ParseHTTPSRedirect(ing.GetAnnotations(), IstioAnnotationPrefix, config.FromContext(ctx).Istio.HTTPSRedirect)
+	if err != nil {
+		return redirect, fmt.Errorf("invalid HTTPS redirect annotations: %w", err)
+	}
+	return redirect, nil
+}
+
+// RedirectToHTTPS makes the routes of the given VirtualService that are served by the given
+// Gateways redirect the requests received on the plain HTTP port to HTTPS. This replaces the
+// `httpsRedirect` flag of the Gateway servers, which only supports 301 redirects to port 443.
+//
+// The requests matching one of the exempt path prefixes are still routed to the backends, as
+// well as the probes of the gateways. Paths of the VirtualService routes matching a path on
+// their own are not exempted.
+func RedirectToHTTPS(vs *v1beta1.VirtualService, redirect config.HTTPSRedirect, gateways sets.Set[string]) {
+	code, port := redirect.Code, redirect.Port
+	if code == 0 {
+		code = http.StatusMovedPermanently
+	}
+	if port == 0 {
+		port = ExternalGatewayHTTPSPort
+	}
+	redirectRoute := &istiov1beta1.HTTPRoute{
+		Redirect: &istiov1beta1.HTTPRedirect{
+			Scheme:       "https",
+			RedirectCode: code,
+			RedirectPort: &istiov1beta1.HTTPRedirect_Port{Port: port},
+		},
+	}
+
+	exemptRoutes := []*istiov1beta1.HTTPRoute{}
+	seen := sets.New[string]()
+	for _, route := range vs.Spec.GetHttp() {
+		if !routeServedBy(route, gateways) {
+			continue
+		}
+		for _, prefix := range redirect.ExemptPaths {
+			exemptRoute := proto.Clone(route).(*istiov1beta1.HTTPRoute)
+			exemptRoute.Match = nil
+			for _, match := range route.GetMatch() {
+				if match.GetUri() != nil {
+					continue
+				}
+				exemptMatch := proto.Clone(match).(*istiov1beta1.HTTPMatchRequest)
+				exemptMatch.Uri = &istiov1beta1.StringMatch{
+					MatchType: &istiov1beta1.StringMatch_Prefix{Prefix: prefix},
+				}
+				exemptMatch.Port = GatewayHTTPPort
+				exemptRoute.Match = append(exemptRoute.Match, exemptMatch)
+			}
+			if len(exemptRoute.Match) > 0 {
+				exemptRoutes = append(exemptRoutes, exemptRoute)
+			}
+		}
+		for _, match := range route.GetMatch() {
+			// Routes only differing by their paths or headers share the same redirect.
+			key := match.GetAuthority().GetPrefix() + "|" + strings.Join(match.GetGateways(), ",")
+			if seen.Has(key) {
+				continue
+			}
+			seen.Insert(key)
+			redirectRoute.Match = append(redirectRoute.Match, &istiov1beta1.HTTPMatchRequest{
+				Gateways:  match.GetGateways(),
+				Authority: match.GetAuthority(),
+				Port:      GatewayHTTPPort,
+				WithoutHeaders: map[string]*istiov1beta1.StringMatch{
+					header.ProbeKey: {MatchType: &istiov1beta1.StringMatch_Exact{Exact: header.ProbeValue}},
+				},
+			})
+		}
+	}
+	if len(redirectRoute.Match) == 0 {
+		return
+	}
+
+	routes := make([]*istiov1beta1.HTTPRoute, 0, len(exemptRoutes)+1+len(vs.Spec.GetHttp()))
+	routes = append(routes, exemptRoutes...)
+	routes = append(routes, redirectRoute)
+	vs.Spec.Http = append(routes, vs.Spec.GetHttp()...)
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+	istiov1beta1 "istio.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"knative.dev/net-istio/pkg/reconciler/ingress/config"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+	"knative.dev/pkg/system"
+)
+
+func TestHTTPSRedirectFromContext(t *testing.T) {
+	ctx := config.ToContext(context.Background(), &config.Config{
+		Istio: &config.Istio{
+			HTTPSRedirect: config.HTTPSRedirect{
+				Code:        308,
+				ExemptPaths: []string{"/healthz"},
+			},
+		},
+	})
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        config.HTTPSRedirect
+		wantErr     bool
+	}{{
+		name: "config-istio",
+		want: config.HTTPSRedirect{
+			Code:        308,
+			ExemptPaths: []string{"/healthz"},
+		},
+	}, {
+		name: "annotations",
+		annotations: map[string]string{
+			"istio.networking.knative.dev/https-redirect-code":         "307",
+			"istio.networking.knative.dev/https-redirect-port":         "8443",
+			"istio.networking.knative.dev/https-redirect-exempt-paths": "/.well-known/acme-challenge/",
+		},
+		want: config.HTTPSRedirect{
+			Code:        307,
+			Port:        8443,
+			ExemptPaths: []string{"/.well-known/acme-challenge/"},
+		},
+	}, {
+		name: "invalid annotation",
+		annotations: map[string]string{
+			"istio.networking.knative.dev/https-redirect-code": "200",
+		},
+		wantErr: true,
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ing := &v1alpha1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "ingress",
+					Namespace:   "test-ns",
+					Annotations: tc.annotations,
+				},
+			}
+			got, err := HTTPSRedirectFromContext(ctx, ing)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("HTTPSRedirectFromContext() error = %v, WantErr %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Error("HTTPSRedirectFromContext() (-want, +got):", diff)
+			}
+		})
+	}
+}
+
+func TestRedirectToHTTPS(t *testing.T) {
+	ci := &v1alpha1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-ingress",
+			Namespace: system.Namespace(),
+		},
+		Spec: v1alpha1.IngressSpec{
+			Rules: []v1alpha1.IngressRule{{
+				Hosts: []string{"domain.com"},
+				HTTP: &v1alpha1.HTTPIngressRuleValue{
+					Paths: []v1alpha1.HTTPIngressPath{{
+						Headers: map[string]v1alpha1.HeaderMatch{"K-Network-Hash": {Exact: "override"}},
+						Splits: []v1alpha1.IngressBackendSplit{{
+							IngressBackend: v1alpha1.IngressBackend{
+								ServiceNamespace: "test-ns",
+								ServiceName:      "v1-service",
+								ServicePort:      intstr.FromInt(80),
+							},
+							Percent: 100,
+						}},
+					}, {
+						Splits: []v1alpha1.IngressBackendSplit{{
+							IngressBackend: v1alpha1.IngressBackend{
+								ServiceNamespace: "test-ns",
+								ServiceName:      "v1-service",
+								ServicePort:      intstr.FromInt(80),
+							},
+							Percent: 100,
+						}},
+					}},
+				},
+				Visibility: v1alpha1.IngressVisibilityExternalIP,
+			}, {
+				Hosts: []string{"test-route.test-ns.svc.cluster.local"},
+				HTTP: &v1alpha1.HTTPIngressRuleValue{
+					Paths: []v1alpha1.HTTPIngressPath{{
+						Splits: []v1alpha1.IngressBackendSplit{{
+							IngressBackend: v1alpha1.IngressBackend{
+								ServiceNamespace: "test-ns",
+								ServiceName:      "v1-service",
+								ServicePort:      intstr.FromInt(80),
+							},
+							Percent: 100,
+						}},
+					}},
+				},
+				Visibility: v1alpha1.IngressVisibilityClusterLocal,
+			}},
+		},
+	}
+	gateways := makeGatewayMap([]string{"gateway.public"}, []string{"gateway.private"})
+
+	t.Run("custom code and port", func(t *testing.T) {
+		vs := MakeIngressVirtualService(ci, gateways)
+		routes := vs.Spec.GetHttp()
+		RedirectToHTTPS(vs, config.HTTPSRedirect{Code: 308, Port: 8443}, sets.New("gateway.public"))
+
+		want := &istiov1beta1.HTTPRoute{
+			Match: []*istiov1beta1.HTTPMatchRequest{{
+				Gateways: []string{"gateway.public"},
+				Authority: &istiov1beta1.StringMatch{
+					MatchType: &istiov1beta1.StringMatch_Prefix{Prefix: "domain.com"},
+				},
+				Port: GatewayHTTPPort,
+				WithoutHeaders: map[string]*istiov1beta1.StringMatch{
+					"K-Network-Probe": {MatchType: &istiov1beta1.StringMatch_Exact{Exact: "probe"}},
+				},
+			}},
+			Redirect: &istiov1beta1.HTTPRedirect{
+				Scheme:       "https",
+				RedirectCode: 308,
+				RedirectPort: &istiov1beta1.HTTPRedirect_Port{Port: 8443},
+			},
+		}
+		if got := len(vs.Spec.GetHttp()); got != len(routes)+1 {
+			t.Fatalf("len(Http) = %d, want %d", got, len(routes)+1)
+		}
+		if diff := cmp.Diff(want, vs.Spec.GetHttp()[0], protocmp.Transform()); diff != "" {
+			t.Error("Unexpected redirect route (-want, +got):", diff)
+		}
+		// The original routes follow the redirect route.
+		if diff := cmp.Diff(routes, vs.Spec.GetHttp()[1:], protocmp.Transform()); diff != "" {
+			t.Error("Unexpected routes (-want, +got):", diff)
+		}
+	})
+
+	t.Run("exempt paths", func(t *testing.T) {
+		vs := MakeIngressVirtualService(ci, gateways)
+		routes := vs.Spec.GetHttp()
+		RedirectToHTTPS(vs, config.HTTPSRedirect{ExemptPaths: []string{"/.well-known/acme-challenge/", "/healthz"}}, sets.New("gateway.public"))
+
+		// Two exempt routes for each of the two public routes, the redirect route and the original routes.
+		if got, want := len(vs.Spec.GetHttp()), 4+1+len(routes); got != want {
+			t.Fatalf("len(Http) = %d, want %d", got, want)
+		}
+		wantPrefixes := []string{"/.well-known/acme-challenge/", "/healthz", "/.well-known/acme-challenge/", "/healthz"}
+		for i, prefix := range wantPrefixes {
+			route := vs.Spec.GetHttp()[i]
+			if route.GetRedirect() != nil {
+				t.Fatalf("Route %d is a redirect, want an exempt route", i)
+			}
+			for _, match := range route.GetMatch() {
+				if got := match.GetUri().GetPrefix(); got != prefix {
+					t.Errorf("Route %d URI prefix = %q, want %q", i, got, prefix)
+				}
+				if got := match.GetPort(); got != GatewayHTTPPort {
+					t.Errorf("Route %d port = %d, want %d", i, got, GatewayHTTPPort)
+				}
+			}
+		}
+		// The exempt routes keep the header matches of the original routes.
+		if diff := cmp.Diff(routes[0].GetMatch()[0].GetHeaders(), vs.Spec.GetHttp()[0].GetMatch()[0].GetHeaders(), protocmp.Transform()); diff != "" {
+			t.Error("Unexpected headers of the exempt route (-want, +got):", diff)
+		}
+		redirect := vs.Spec.GetHttp()[4].GetRedirect()
+		if redirect.GetRedirectCode() != 301 || redirect.GetPort() != 443 {
+			t.Errorf("Redirect = %v, want a 301 to port 443", redirect)
+		}
+	})
+
+	t.Run("no routes served by the gateways", func(t *testing.T) {
+		vs := MakeIngressVirtualService(ci, gateways)
+		routes := vs.Spec.GetHttp()
+		RedirectToHTTPS(vs, config.HTTPSRedirect{Code: 308}, sets.New("gateway.other"))
+		if diff := cmp.Diff(routes, vs.Spec.GetHttp(), protocmp.Transform()); diff != "" {
+			t.Error("Unexpected routes (-want, +got):", diff)
+		}
+	})
+}
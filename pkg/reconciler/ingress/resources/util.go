@@ -26,10 +26,13 @@ import (
 	"fmt"
 	"math/big"
 	"net"
+	"strconv"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/kmeta"
 )
 
 const (
@@ -49,8 +52,52 @@ const (
 	// RouteNamespaceLabelKey is the label key attached to a Ingress
 	// by a Route to indicate which namespace the Route was created in.
 	RouteNamespaceLabelKey = ServingGroupName + "/routeNamespace"
+
+	// IstioAnnotationPrefix is the prefix of the annotations configuring how an Ingress is
+	// programmed on the Istio gateways.
+	IstioAnnotationPrefix = "istio.networking.knative.dev/"
+
+	// TLSModeAnnotationKey is the annotation key on an Ingress to configure how TLS
+	// traffic for its public hosts is handled by the Istio gateways.
+	TLSModeAnnotationKey = IstioAnnotationPrefix + "tls-mode"
+	// TLSModePassthrough is the TLSModeAnnotationKey value to pass TLS traffic
+	// through the gateways untouched and route it by SNI, so that the workload
+	// terminates TLS itself.
+	TLSModePassthrough = "passthrough"
+	// TLSPassthroughPortAnnotationKey is the annotation key on an Ingress using TLS passthrough
+	// to configure the port of its backend Services its TLS traffic is routed to. The backends
+	// must terminate TLS on that port, unlike on the service ports of the Ingress, which serve
+	// plain HTTP.
+	TLSPassthroughPortAnnotationKey = IstioAnnotationPrefix + "tls-passthrough-port"
+	// DefaultTLSPassthroughPort is the port the TLS traffic of Ingresses using TLS passthrough
+	// is routed to when TLSPassthroughPortAnnotationKey is not set.
+	DefaultTLSPassthroughPort = 443
+
+	// GatewaysStatusAnnotationKey is the status annotation key on an Ingress recording the
+	// comma-separated qualified names of the Gateways the Ingress is programmed on.
+	GatewaysStatusAnnotationKey = IstioAnnotationPrefix + "gateways"
 )
 
+// IsTLSPassthrough returns true if the given Ingress requests TLS passthrough
+// for its public hosts.
+func IsTLSPassthrough(ing kmeta.Accessor) bool {
+	return strings.EqualFold(ing.GetAnnotations()[TLSModeAnnotationKey], TLSModePassthrough)
+}
+
+// TLSPassthroughPort returns the port of the backend Services the TLS traffic of the given
+// Ingress using TLS passthrough is routed to.
+func TLSPassthroughPort(ing kmeta.Accessor) (uint32, error) {
+	value, ok := ing.GetAnnotations()[TLSPassthroughPortAnnotationKey]
+	if !ok {
+		return DefaultTLSPassthroughPort, nil
+	}
+	port, err := strconv.ParseUint(value, 10, 16)
+	if err != nil || port == 0 {
+		return 0, fmt.Errorf("invalid %s annotation %q: must be a port number between 1 and 65535", TLSPassthroughPortAnnotationKey, value)
+	}
+	return uint32(port), nil
+}
+
 func GenerateCertificate(hosts []string, secretName string, namespace string) (*corev1.Secret, error) {
 	priv, err := rsa.GenerateKey(rand.Reader, 2048)
 	if err != nil {
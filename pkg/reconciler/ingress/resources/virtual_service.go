@@ -32,8 +32,17 @@ import (
 	"knative.dev/pkg/kmeta"
 	"knative.dev/pkg/network"
 	"knative.dev/pkg/system"
+	"knative.dev/pkg/tracker"
 )
 
+var virtualServiceGvk = v1beta1.SchemeGroupVersion.WithKind("VirtualService")
+
+// AltSvcHeaderName is the name of the header advertising alternative services like HTTP/3.
+const AltSvcHeaderName = "alt-svc"
+
+// http3AltSvc advertises HTTP/3 on the external HTTPS port for 24 hours.
+var http3AltSvc = fmt.Sprintf(`h3=":%d"; ma=86400`, ExternalGatewayHTTPSPort)
+
 // VirtualServiceNamespace gives the namespace of the child
 // VirtualServices for a given Ingress.
 func VirtualServiceNamespace(ing *v1alpha1.Ingress) string {
@@ -53,7 +62,7 @@ func MakeIngressVirtualService(ing *v1alpha1.Ingress, gateways map[v1alpha1.Ingr
 			OwnerReferences: []metav1.OwnerReference{*kmeta.NewControllerRef(ing)},
 			Annotations:     ing.GetAnnotations(),
 		},
-		Spec: *makeVirtualServiceSpec(ing, gateways, ingress.ExpandedHosts(getHosts(ing))),
+		Spec: *makeVirtualServiceSpec(ing, gateways, ingress.ExpandedHosts(getHosts(ing)), IsTLSPassthrough(ing)),
 	}
 
 	// Populate the Ingress labels.
@@ -85,7 +94,7 @@ func MakeMeshVirtualService(ing *v1alpha1.Ingress, gateways map[v1alpha1.Ingress
 		Spec: *makeVirtualServiceSpec(ing, map[v1alpha1.IngressVisibility]sets.Set[string]{
 			v1alpha1.IngressVisibilityExternalIP:   sets.New("mesh"),
 			v1alpha1.IngressVisibilityClusterLocal: sets.New("mesh"),
-		}, hosts),
+		}, hosts, false),
 	}
 	// Populate the Ingress labels.
 	vs.Labels = kmeta.FilterMap(ing.GetLabels(), func(k string) bool {
@@ -122,13 +131,114 @@ func MakeVirtualServices(ing *v1alpha1.Ingress, gateways map[v1alpha1.IngressVis
 	return vss, nil
 }
 
-func makeVirtualServiceSpec(ing *v1alpha1.Ingress, gateways map[v1alpha1.IngressVisibility]sets.Set[string], hosts sets.Set[string]) *istiov1beta1.VirtualService {
+// VirtualServiceRef returns the Reference for a given VirtualService.
+func VirtualServiceRef(vs *v1beta1.VirtualService) tracker.Reference {
+	apiVersion, kind := virtualServiceGvk.ToAPIVersionAndKind()
+	return tracker.Reference{
+		APIVersion: apiVersion,
+		Kind:       kind,
+		Name:       vs.Name,
+		Namespace:  vs.Namespace,
+	}
+}
+
+// AdvertiseHTTP3 adds an `alt-svc` response header advertising HTTP/3 on the external HTTPS
+// port to the routes of the given VirtualService that are served by one of the given Gateways
+// over HTTPS, i.e. whose hosts all have a certificate in the given TLS hosts. HTTP/3 is not an
+// alternative to plain HTTP, so the routes of other hosts are left alone.
+func AdvertiseHTTP3(vs *v1beta1.VirtualService, gateways, tlsHosts sets.Set[string]) {
+	for _, route := range vs.Spec.GetHttp() {
+		if route.GetRedirect() != nil || !routeServedBy(route, gateways) || !routeServedOverHTTPS(route, gateways, tlsHosts) {
+			continue
+		}
+		if route.GetHeaders() == nil {
+			route.Headers = &istiov1beta1.Headers{}
+		}
+		response := route.GetHeaders().GetResponse()
+		if response == nil {
+			response = &istiov1beta1.Headers_HeaderOperations{}
+			route.Headers.Response = response
+		}
+		if response.GetSet() == nil {
+			response.Set = make(map[string]string, 1)
+		}
+		response.Set[AltSvcHeaderName] = http3AltSvc
+	}
+}
+
+// routeServedOverHTTPS returns true if the hosts matched by the given route on the given
+// Gateways are all in the given TLS hosts.
+func routeServedOverHTTPS(route *istiov1beta1.HTTPRoute, gateways, tlsHosts sets.Set[string]) bool {
+	for _, match := range route.GetMatch() {
+		if gateways.HasAny(match.GetGateways()...) && !tlsHosts.Has(match.GetAuthority().GetPrefix()) {
+			return false
+		}
+	}
+	return true
+}
+
+func routeServedBy(route *istiov1beta1.HTTPRoute, gateways sets.Set[string]) bool {
+	for _, match := range route.GetMatch() {
+		if gateways.HasAny(match.GetGateways()...) {
+			return true
+		}
+	}
+	return false
+}
+
+// RoutedGateways returns the Gateways the routes of the given VirtualService are bound to, by
+// the visibility of the rules of the given Ingress matching their hosts.
+func RoutedGateways(ing *v1alpha1.Ingress, vs *v1beta1.VirtualService) map[v1alpha1.IngressVisibility]sets.Set[string] {
+	gateways := make(map[v1alpha1.IngressVisibility]sets.Set[string], 2)
+	for _, rule := range ing.Spec.Rules {
+		hosts := sets.New(rule.Hosts...)
+		prefixes := getDistinctHostPrefixes(hosts)
+		routed := sets.New[string]()
+		for _, route := range vs.Spec.GetHttp() {
+			for _, match := range route.GetMatch() {
+				if prefixes.Has(match.GetAuthority().GetPrefix()) {
+					routed.Insert(match.GetGateways()...)
+				}
+			}
+		}
+		for _, route := range vs.Spec.GetTls() {
+			for _, match := range route.GetMatch() {
+				if hosts.HasAny(match.GetSniHosts()...) {
+					routed.Insert(match.GetGateways()...)
+				}
+			}
+		}
+		gateways[rule.Visibility] = routed.Union(gateways[rule.Visibility])
+	}
+	return gateways
+}
+
+func makeVirtualServiceSpec(ing *v1alpha1.Ingress, gateways map[v1alpha1.IngressVisibility]sets.Set[string], hosts sets.Set[string], tlsPassthrough bool) *istiov1beta1.VirtualService {
 	spec := istiov1beta1.VirtualService{
 		Hosts: sets.List(hosts),
 	}
 
+	var passthroughPort uint32
+	if tlsPassthrough {
+		// Ingresses with an invalid port are rejected by the reconciler before their
+		// VirtualServices are made.
+		passthroughPort, _ = TLSPassthroughPort(ing)
+	}
+
 	gw := sets.New[string]()
 	for _, rule := range ing.Spec.Rules {
+		// With TLS passthrough, the public hosts are routed by SNI instead of by HTTP attributes.
+		if tlsPassthrough && rule.Visibility == v1alpha1.IngressVisibilityExternalIP {
+			hosts := hosts.Intersection(sets.New(rule.Hosts...))
+			if hosts.Len() != 0 {
+				tls := makeVirtualServiceTLSRoute(hosts, rule.HTTP, gateways[rule.Visibility], passthroughPort)
+				for _, m := range tls.GetMatch() {
+					gw = gw.Union(sets.New(m.GetGateways()...))
+				}
+				spec.Tls = append(spec.Tls, tls)
+			}
+			continue
+		}
 		for i := range rule.HTTP.Paths {
 			p := rule.HTTP.Paths[i]
 			hosts := hosts.Intersection(sets.New(rule.Hosts...))
@@ -211,6 +321,48 @@ func makeVirtualServiceRoute(hosts sets.Set[string], http *v1alpha1.HTTPIngressP
 	return route
 }
 
+// makeVirtualServiceTLSRoute creates a TLS route matching the given hosts by SNI. TLS routes cannot
+// match paths or headers, so the backends of the first path without header matches are used, which
+// skips the paths added for probing. The traffic is routed to the given port of the backends
+// instead of their service port, which serves plain HTTP.
+func makeVirtualServiceTLSRoute(hosts sets.Set[string], http *v1alpha1.HTTPIngressRuleValue, gateways sets.Set[string], port uint32) *istiov1beta1.TLSRoute {
+	var path *v1alpha1.HTTPIngressPath
+	for i := range http.Paths {
+		if len(http.Paths[i].Headers) == 0 {
+			path = &http.Paths[i]
+			break
+		}
+	}
+	if path == nil && len(http.Paths) > 0 {
+		path = &http.Paths[0]
+	}
+
+	weights := []*istiov1beta1.RouteDestination{}
+	if path != nil {
+		for _, split := range path.Splits {
+			weights = append(weights, &istiov1beta1.RouteDestination{
+				Destination: &istiov1beta1.Destination{
+					Host: network.GetServiceHostname(
+						split.ServiceName, split.ServiceNamespace),
+					Port: &istiov1beta1.PortSelector{
+						Number: port,
+					},
+				},
+				//nolint:gosec // ignore integer overflow - percent is always >0 & <100
+				Weight: int32(split.Percent),
+			})
+		}
+	}
+
+	return &istiov1beta1.TLSRoute{
+		Match: []*istiov1beta1.TLSMatchAttributes{{
+			SniHosts: sets.List(hosts),
+			Gateways: sets.List(gateways),
+		}},
+		Route: weights,
+	}
+}
+
 // getDistinctHostPrefixes deduplicate a set of prefix matches. For example, the set {a, aabb} can be
 // reduced to {a}, as a prefix match on {a} accepts all the same inputs as {a, aabb}.
 func getDistinctHostPrefixes(hosts sets.Set[string]) sets.Set[string] {
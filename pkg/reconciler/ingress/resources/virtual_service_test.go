@@ -22,6 +22,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"google.golang.org/protobuf/testing/protocmp"
 	istiov1beta1 "istio.io/api/networking/v1beta1"
+	"istio.io/client-go/pkg/apis/networking/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -30,6 +31,7 @@ import (
 	"knative.dev/pkg/kmeta"
 	"knative.dev/pkg/system"
 	_ "knative.dev/pkg/system/testing"
+	"knative.dev/pkg/tracker"
 )
 
 var (
@@ -285,7 +287,7 @@ func TestMakeVirtualServicesSpec_CorrectGateways(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			vs := makeVirtualServiceSpec(tc.ingress, tc.gateways, getHosts(tc.ingress))
+			vs := makeVirtualServiceSpec(tc.ingress, tc.gateways, getHosts(tc.ingress), false)
 			actualGateways := sets.New(vs.Gateways...)
 			if !actualGateways.Equal(tc.expectedGateways) {
 				t.Fatalf("Got gateways %v, expected %v", sets.List(actualGateways), sets.List(tc.expectedGateways))
@@ -588,6 +590,297 @@ func TestMakeIngressVirtualServiceSpec_CorrectRoutes(t *testing.T) {
 	}
 }
 
+func TestMakeIngressVirtualServiceSpec_TLSPassthrough(t *testing.T) {
+	ci := &v1alpha1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-ingress",
+			Namespace:   system.Namespace(),
+			Annotations: map[string]string{TLSModeAnnotationKey: TLSModePassthrough},
+		},
+		Spec: v1alpha1.IngressSpec{
+			Rules: []v1alpha1.IngressRule{{
+				Hosts: []string{"domain.com"},
+				HTTP: &v1alpha1.HTTPIngressRuleValue{
+					Paths: []v1alpha1.HTTPIngressPath{{
+						// Probe paths are skipped as they match headers.
+						Headers: map[string]v1alpha1.HeaderMatch{"K-Network-Hash": {Exact: "override"}},
+						Splits: []v1alpha1.IngressBackendSplit{{
+							IngressBackend: v1alpha1.IngressBackend{
+								ServiceNamespace: "test-ns",
+								ServiceName:      "probe-service",
+								ServicePort:      intstr.FromInt(80),
+							},
+							Percent: 100,
+						}},
+					}, {
+						Splits: []v1alpha1.IngressBackendSplit{{
+							IngressBackend: v1alpha1.IngressBackend{
+								ServiceNamespace: "test-ns",
+								ServiceName:      "v1-service",
+								ServicePort:      intstr.FromInt(80),
+							},
+							Percent: 90,
+						}, {
+							IngressBackend: v1alpha1.IngressBackend{
+								ServiceNamespace: "test-ns",
+								ServiceName:      "v2-service",
+								ServicePort:      intstr.FromInt(80),
+							},
+							Percent: 10,
+						}},
+					}},
+				},
+				Visibility: v1alpha1.IngressVisibilityExternalIP,
+			}, {
+				Hosts: []string{"test-route.test-ns.svc.cluster.local"},
+				HTTP: &v1alpha1.HTTPIngressRuleValue{
+					Paths: []v1alpha1.HTTPIngressPath{{
+						Splits: []v1alpha1.IngressBackendSplit{{
+							IngressBackend: v1alpha1.IngressBackend{
+								ServiceNamespace: "test-ns",
+								ServiceName:      "v1-service",
+								ServicePort:      intstr.FromInt(80),
+							},
+							Percent: 100,
+						}},
+					}},
+				},
+				Visibility: v1alpha1.IngressVisibilityClusterLocal,
+			}},
+		},
+	}
+
+	expectedTLS := []*istiov1beta1.TLSRoute{{
+		Match: []*istiov1beta1.TLSMatchAttributes{{
+			SniHosts: []string{"domain.com"},
+			Gateways: []string{"gateway.public"},
+		}},
+		Route: []*istiov1beta1.RouteDestination{{
+			Destination: &istiov1beta1.Destination{
+				Host: "v1-service.test-ns.svc.cluster.local",
+				Port: &istiov1beta1.PortSelector{Number: 443},
+			},
+			Weight: 90,
+		}, {
+			Destination: &istiov1beta1.Destination{
+				Host: "v2-service.test-ns.svc.cluster.local",
+				Port: &istiov1beta1.PortSelector{Number: 443},
+			},
+			Weight: 10,
+		}},
+	}}
+
+	// The TLS traffic is routed to the HTTPS port of the backends, not to their service port
+	// serving plain HTTP.
+	vs := MakeIngressVirtualService(ci, makeGatewayMap([]string{"gateway.public"}, []string{"gateway.private"}))
+	if diff := cmp.Diff(expectedTLS, vs.Spec.Tls, protocmp.Transform()); diff != "" {
+		t.Error("Unexpected TLS routes (-want +got):", diff)
+	}
+	// The cluster-local rule is still routed by HTTP attributes.
+	if got := len(vs.Spec.Http); got != 1 {
+		t.Errorf("len(Http) = %d, want 1", got)
+	}
+	if diff := cmp.Diff([]string{"gateway.private", "gateway.public"}, vs.Spec.Gateways); diff != "" {
+		t.Error("Unexpected gateways (-want +got):", diff)
+	}
+
+	// The port can be configured by annotation.
+	ci.Annotations[TLSPassthroughPortAnnotationKey] = "8443"
+	for _, route := range expectedTLS[0].Route {
+		route.Destination.Port.Number = 8443
+	}
+	vs = MakeIngressVirtualService(ci, makeGatewayMap([]string{"gateway.public"}, []string{"gateway.private"}))
+	if diff := cmp.Diff(expectedTLS, vs.Spec.Tls, protocmp.Transform()); diff != "" {
+		t.Error("Unexpected TLS routes with a configured port (-want +got):", diff)
+	}
+}
+
+func TestTLSPassthroughPort(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        uint32
+		wantErr     bool
+	}{{
+		name: "default",
+		want: DefaultTLSPassthroughPort,
+	}, {
+		name:        "configured",
+		annotations: map[string]string{TLSPassthroughPortAnnotationKey: "8443"},
+		want:        8443,
+	}, {
+		name:        "not a number",
+		annotations: map[string]string{TLSPassthroughPortAnnotationKey: "https"},
+		wantErr:     true,
+	}, {
+		name:        "zero",
+		annotations: map[string]string{TLSPassthroughPortAnnotationKey: "0"},
+		wantErr:     true,
+	}, {
+		name:        "out of range",
+		annotations: map[string]string{TLSPassthroughPortAnnotationKey: "65536"},
+		wantErr:     true,
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ing := &v1alpha1.Ingress{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			got, err := TLSPassthroughPort(ing)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("TLSPassthroughPort() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("TLSPassthroughPort() = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAdvertiseHTTP3(t *testing.T) {
+	ci := &v1alpha1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-ingress",
+			Namespace: system.Namespace(),
+		},
+		Spec: v1alpha1.IngressSpec{
+			Rules: []v1alpha1.IngressRule{{
+				Hosts: []string{"domain.com"},
+				HTTP: &v1alpha1.HTTPIngressRuleValue{
+					Paths: []v1alpha1.HTTPIngressPath{{
+						AppendHeaders: map[string]string{"foo": "bar"},
+						Splits: []v1alpha1.IngressBackendSplit{{
+							IngressBackend: v1alpha1.IngressBackend{
+								ServiceNamespace: "test-ns",
+								ServiceName:      "v1-service",
+								ServicePort:      intstr.FromInt(80),
+							},
+							Percent: 100,
+						}},
+					}},
+				},
+				Visibility: v1alpha1.IngressVisibilityExternalIP,
+			}, {
+				Hosts: []string{"test-route.test-ns.svc.cluster.local"},
+				HTTP: &v1alpha1.HTTPIngressRuleValue{
+					Paths: []v1alpha1.HTTPIngressPath{{
+						Splits: []v1alpha1.IngressBackendSplit{{
+							IngressBackend: v1alpha1.IngressBackend{
+								ServiceNamespace: "test-ns",
+								ServiceName:      "v1-service",
+								ServicePort:      intstr.FromInt(80),
+							},
+							Percent: 100,
+						}},
+					}},
+				},
+				Visibility: v1alpha1.IngressVisibilityClusterLocal,
+			}, {
+				Hosts: []string{"plain.com"},
+				HTTP: &v1alpha1.HTTPIngressRuleValue{
+					Paths: []v1alpha1.HTTPIngressPath{{
+						Splits: []v1alpha1.IngressBackendSplit{{
+							IngressBackend: v1alpha1.IngressBackend{
+								ServiceNamespace: "test-ns",
+								ServiceName:      "v1-service",
+								ServicePort:      intstr.FromInt(80),
+							},
+							Percent: 100,
+						}},
+					}},
+				},
+				Visibility: v1alpha1.IngressVisibilityExternalIP,
+			}},
+		},
+	}
+
+	vs := MakeIngressVirtualService(ci, makeGatewayMap([]string{"gateway.public"}, []string{"gateway.private"}))
+	AdvertiseHTTP3(vs, sets.New("gateway.public"), sets.New("domain.com"))
+
+	if got := len(vs.Spec.Http); got != 3 {
+		t.Fatalf("len(Http) = %d, want 3", got)
+	}
+	wantPublic := &istiov1beta1.Headers{
+		Request: &istiov1beta1.Headers_HeaderOperations{
+			Set: map[string]string{"foo": "bar"},
+		},
+		Response: &istiov1beta1.Headers_HeaderOperations{
+			Set: map[string]string{"alt-svc": `h3=":443"; ma=86400`},
+		},
+	}
+	if diff := cmp.Diff(wantPublic, vs.Spec.Http[0].Headers, protocmp.Transform()); diff != "" {
+		t.Error("Unexpected headers of the public route (-want +got):", diff)
+	}
+	// Cluster-local routes are not served by the external gateways.
+	if vs.Spec.Http[1].Headers != nil {
+		t.Errorf("Headers of the cluster-local route = %v, want nil", vs.Spec.Http[1].Headers)
+	}
+	// Hosts without certificate are only served over plain HTTP.
+	if vs.Spec.Http[2].Headers != nil {
+		t.Errorf("Headers of the plain HTTP route = %v, want nil", vs.Spec.Http[2].Headers)
+	}
+}
+
+func TestRoutedGateways(t *testing.T) {
+	split := []v1alpha1.IngressBackendSplit{{
+		IngressBackend: v1alpha1.IngressBackend{
+			ServiceNamespace: "test-ns",
+			ServiceName:      "v1-service",
+			ServicePort:      intstr.FromInt(80),
+		},
+		Percent: 100,
+	}}
+	ci := &v1alpha1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-ingress",
+			Namespace: system.Namespace(),
+		},
+		Spec: v1alpha1.IngressSpec{
+			Rules: []v1alpha1.IngressRule{{
+				Hosts:      []string{"domain.com"},
+				HTTP:       &v1alpha1.HTTPIngressRuleValue{Paths: []v1alpha1.HTTPIngressPath{{Splits: split}}},
+				Visibility: v1alpha1.IngressVisibilityExternalIP,
+			}, {
+				Hosts:      []string{"test-route.test-ns.svc.cluster.local"},
+				HTTP:       &v1alpha1.HTTPIngressRuleValue{Paths: []v1alpha1.HTTPIngressPath{{Splits: split}}},
+				Visibility: v1alpha1.IngressVisibilityClusterLocal,
+			}},
+		},
+	}
+
+	tests := []struct {
+		name string
+		ing  *v1alpha1.Ingress
+		want map[v1alpha1.IngressVisibility]sets.Set[string]
+	}{{
+		name: "HTTP routes",
+		ing:  ci,
+		want: map[v1alpha1.IngressVisibility]sets.Set[string]{
+			v1alpha1.IngressVisibilityExternalIP:   sets.New("gateway.public", "gateway.public-2"),
+			v1alpha1.IngressVisibilityClusterLocal: sets.New("gateway.private"),
+		},
+	}, {
+		name: "TLS passthrough routes",
+		ing: func() *v1alpha1.Ingress {
+			ing := ci.DeepCopy()
+			ing.Annotations = map[string]string{TLSModeAnnotationKey: TLSModePassthrough}
+			return ing
+		}(),
+		want: map[v1alpha1.IngressVisibility]sets.Set[string]{
+			v1alpha1.IngressVisibilityExternalIP:   sets.New("gateway.public", "gateway.public-2"),
+			v1alpha1.IngressVisibilityClusterLocal: sets.New("gateway.private"),
+		},
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			vs := MakeIngressVirtualService(tc.ing, makeGatewayMap([]string{"gateway.public", "gateway.public-2"}, []string{"gateway.private"}))
+			if got := RoutedGateways(tc.ing, vs); !cmp.Equal(got, tc.want) {
+				t.Errorf("RoutedGateways() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
 func TestMakeVirtualServiceRoute_RewriteHost(t *testing.T) {
 	ingressPath := &v1alpha1.HTTPIngressPath{
 		RewriteHost: "the.target.host",
@@ -830,3 +1123,22 @@ func TestGetDistinctHostPrefixes(t *testing.T) {
 		})
 	}
 }
+
+func TestVirtualServiceRef(t *testing.T) {
+	vs := &v1beta1.VirtualService{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-ingress",
+			Namespace: "test-ns",
+		},
+	}
+	want := tracker.Reference{
+		APIVersion: "networking.istio.io/v1beta1",
+		Kind:       "VirtualService",
+		Name:       "test-ingress",
+		Namespace:  "test-ns",
+	}
+	got := VirtualServiceRef(vs)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatal("VirtualServiceRef failed. diff", diff)
+	}
+}
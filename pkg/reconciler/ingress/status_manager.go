@@ -0,0 +1,99 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+
+	"knative.dev/net-istio/pkg/reconciler/ingress/config"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+	"knative.dev/networking/pkg/status"
+)
+
+// ingressStatusManager checks the readiness of Ingresses using TLS passthrough with
+// the passthrough prober and the readiness of all other Ingresses with the HTTP prober,
+// or with the host prober for Ingresses with TLS and when more than a single host is
+// probed.
+type ingressStatusManager struct {
+	http        status.Manager
+	passthrough status.Manager
+	hosts       status.Manager
+
+	// configStatus checks the config status of the generated resources, when the readiness
+	// strategy asks for it.
+	configStatus status.Manager
+}
+
+var (
+	_ status.Manager        = (*ingressStatusManager)(nil)
+	_ hostFailureReporter   = (*ingressStatusManager)(nil)
+	_ probeCoverageReporter = (*ingressStatusManager)(nil)
+)
+
+// IsReady implements status.Manager.
+func (m *ingressStatusManager) IsReady(ctx context.Context, ing *v1alpha1.Ingress) (bool, error) {
+	if m.configStatus != nil {
+		if cfg := config.FromContext(ctx).Istio; cfg.ReadinessStrategy.UsesConfigStatus() {
+			ready, err := m.configStatus.IsReady(ctx, ing)
+			// There is nothing to probe in mesh-only mode.
+			if err != nil || !ready || cfg.ReadinessStrategy == config.ReadinessStrategyConfigStatus || !cfg.GatewaysEnabled() {
+				return ready, err
+			}
+		}
+	}
+	if shouldReconcileTLSPassthrough(ing) {
+		return m.passthrough.IsReady(ctx, ing)
+	}
+	if m.usesHostProber(ctx, ing) {
+		return m.hosts.IsReady(ctx, ing)
+	}
+	return m.http.IsReady(ctx, ing)
+}
+
+// FailingHosts implements hostFailureReporter.
+func (m *ingressStatusManager) FailingHosts(ctx context.Context, ing *v1alpha1.Ingress) []hostFailure {
+	if reporter, ok := m.hosts.(hostFailureReporter); ok && !shouldReconcileTLSPassthrough(ing) && m.usesHostProber(ctx, ing) {
+		return reporter.FailingHosts(ctx, ing)
+	}
+	return nil
+}
+
+// ProbeCoverage implements probeCoverageReporter.
+func (m *ingressStatusManager) ProbeCoverage(ctx context.Context, ing *v1alpha1.Ingress) (probeCoverage, bool) {
+	if reporter, ok := m.hosts.(probeCoverageReporter); ok && !shouldReconcileTLSPassthrough(ing) && m.usesHostProber(ctx, ing) {
+		return reporter.ProbeCoverage(ctx, ing)
+	}
+	return probeCoverage{}, false
+}
+
+// usesHostProber returns true if the host prober checks the readiness of the given Ingress.
+func (m *ingressStatusManager) usesHostProber(ctx context.Context, ing *v1alpha1.Ingress) bool {
+	if m.hosts == nil {
+		return false
+	}
+	istio := config.FromContext(ctx).Istio
+	return len(ing.Spec.TLS) > 0 || istio.ProbeHosts != 0 || istio.ProbePods != 0 || (istio.ProbeQuorum != 0 && istio.ProbeQuorum != 100)
+}
+
+// CancelIngressProbing cancels probing of the provided Ingress by all the probers.
+func (m *ingressStatusManager) CancelIngressProbing(obj interface{}) {
+	for _, manager := range []status.Manager{m.http, m.passthrough, m.hosts} {
+		if canceler, ok := manager.(ingressProbeCanceler); ok {
+			canceler.CancelIngressProbing(obj)
+		}
+	}
+}
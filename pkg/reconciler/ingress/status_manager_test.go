@@ -0,0 +1,46 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"testing"
+
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+	fakestatusmanager "knative.dev/networking/pkg/testing/status"
+)
+
+func TestIngressStatusManager(t *testing.T) {
+	manager := &ingressStatusManager{
+		http: &fakestatusmanager.FakeStatusManager{
+			FakeIsReady: func(context.Context, *v1alpha1.Ingress) (bool, error) { return false, nil },
+		},
+		passthrough: &fakestatusmanager.FakeStatusManager{
+			FakeIsReady: func(context.Context, *v1alpha1.Ingress) (bool, error) { return true, nil },
+		},
+	}
+
+	if ready, _ := manager.IsReady(context.Background(), passthroughIngress("foo.bar.com")); !ready {
+		t.Error("Passthrough Ingress was not checked by the passthrough prober")
+	}
+
+	ing := passthroughIngress("foo.bar.com")
+	ing.Annotations = nil
+	if ready, _ := manager.IsReady(context.Background(), ing); ready {
+		t.Error("Ingress was not checked by the HTTP prober")
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+	"knative.dev/pkg/observability/attributekey"
+	"knative.dev/pkg/observability/semconv"
+)
+
+// The spans of the stages of the reconciliation of an Ingress.
+const (
+	spanReconcile        = "ingress.reconcile"
+	spanSecrets          = "ingress.secrets"
+	spanGateways         = "ingress.gateways"
+	spanWildcardGateways = "ingress.wildcard_gateways"
+	spanVirtualServices  = "ingress.virtualservices"
+	spanProbing          = "ingress.probing"
+)
+
+var (
+	ingressNameAttr       = attributekey.String("kn.netistio.ingress.name")
+	ingressGenerationAttr = attributekey.Int64("kn.netistio.ingress.generation")
+	readyAttr             = attributekey.Bool("kn.netistio.ingress.ready")
+)
+
+// startSpan starts a span of the reconciliation of the given Ingress. Reconcilers
+// without a tracer, e.g. in tests, start no-op spans.
+func (r *Reconciler) startSpan(ctx context.Context, name string, ing *v1alpha1.Ingress) (context.Context, trace.Span) {
+	tracer := r.tracer
+	if tracer == nil {
+		tracer = noop.NewTracerProvider().Tracer(scopeName)
+	}
+	return tracer.Start(ctx, name, trace.WithAttributes(
+		semconv.K8SNamespaceName(ing.Namespace),
+		ingressNameAttr.With(ing.Name),
+		ingressGenerationAttr.With(ing.Generation),
+	))
+}
+
+// traceStage runs the given stage of the reconciliation of the given Ingress in a span
+// recording its error.
+func (r *Reconciler) traceStage(ctx context.Context, name string, ing *v1alpha1.Ingress, stage func(context.Context) error) error {
+	ctx, span := r.startSpan(ctx, name, ing)
+	defer span.End()
+	return recordSpanError(span, stage(ctx))
+}
+
+// recordSpanError records the given error in the span, and returns it.
+func recordSpanError(span trace.Span, err error) error {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
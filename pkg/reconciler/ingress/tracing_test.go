@@ -0,0 +1,99 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+)
+
+func TestTraceStage(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	r := &Reconciler{
+		tracer: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer(scopeName),
+	}
+	ing := &v1alpha1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ingress", Generation: 3},
+	}
+	errStage := errors.New("stage failed")
+
+	ctx, span := r.startSpan(context.Background(), spanReconcile, ing)
+	r.traceStage(ctx, spanSecrets, ing, func(context.Context) error {
+		return nil
+	})
+	err := r.traceStage(ctx, spanGateways, ing, func(context.Context) error {
+		return errStage
+	})
+	span.End()
+	if !errors.Is(err, errStage) {
+		t.Errorf("traceStage() = %v, want %v", err, errStage)
+	}
+
+	spans := recorder.Ended()
+	got := make([]string, 0, len(spans))
+	for _, span := range spans {
+		got = append(got, span.Name())
+	}
+	if want := []string{spanSecrets, spanGateways, spanReconcile}; !cmp.Equal(got, want) {
+		t.Fatal("Spans (-want, +got):", cmp.Diff(want, got))
+	}
+
+	wantAttrs := attribute.NewSet(
+		attribute.String("k8s.namespace.name", "default"),
+		attribute.String("kn.netistio.ingress.name", "ingress"),
+		attribute.Int64("kn.netistio.ingress.generation", 3),
+	)
+	root := spans[2]
+	for _, span := range spans {
+		if got := attribute.NewSet(span.Attributes()...); !got.Equals(&wantAttrs) {
+			t.Errorf("Attributes of %s = %v, want %v", span.Name(), got.ToSlice(), wantAttrs.ToSlice())
+		}
+		if span != root && span.Parent().SpanID() != root.SpanContext().SpanID() {
+			t.Errorf("Parent of %s = %v, want the reconcile span", span.Name(), span.Parent().SpanID())
+		}
+	}
+
+	if got := spans[0].Status().Code; got != codes.Unset {
+		t.Errorf("Status of %s = %v, want %v", spans[0].Name(), got, codes.Unset)
+	}
+	if got := spans[1].Status(); got.Code != codes.Error || got.Description != errStage.Error() {
+		t.Errorf("Status of %s = %v, want an error", spans[1].Name(), got)
+	}
+}
+
+func TestTraceStageWithoutTracer(t *testing.T) {
+	r := &Reconciler{}
+	ran := false
+	if err := r.traceStage(context.Background(), spanProbing, &v1alpha1.Ingress{}, func(context.Context) error {
+		ran = true
+		return nil
+	}); err != nil {
+		t.Error("traceStage() =", err)
+	}
+	if !ran {
+		t.Error("The stage did not run")
+	}
+}
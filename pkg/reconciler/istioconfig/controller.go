@@ -0,0 +1,41 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package istioconfig
+
+import (
+	"context"
+
+	istioconfiginformer "knative.dev/net-istio/pkg/client/injection/informers/istio/v1alpha1/istioconfig"
+	istioconfigreconciler "knative.dev/net-istio/pkg/client/injection/reconciler/istio/v1alpha1/istioconfig"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+)
+
+// NewController initializes the controller reporting whether the IstioConfigs are valid
+// and used in their status.
+func NewController(
+	ctx context.Context,
+	_ configmap.Watcher,
+) *controller.Impl {
+	istioConfigInformer := istioconfiginformer.Get(ctx)
+
+	impl := istioconfigreconciler.NewImpl(ctx, &reconciler{})
+
+	istioConfigInformer.Informer().AddEventHandler(controller.HandleAll(impl.Enqueue))
+
+	return impl
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package istioconfig
+
+import (
+	"context"
+	"fmt"
+
+	"knative.dev/net-istio/pkg/apis/istio/v1alpha1"
+	istioconfigreconciler "knative.dev/net-istio/pkg/client/injection/reconciler/istio/v1alpha1/istioconfig"
+	"knative.dev/net-istio/pkg/reconciler/ingress/config"
+	pkgreconciler "knative.dev/pkg/reconciler"
+	"knative.dev/pkg/system"
+)
+
+// reconciler implements controller.Reconciler for IstioConfig resources. The configuration
+// itself is loaded by the Ingress controller, this only reports its state.
+type reconciler struct{}
+
+// Check that our reconciler implements the generated interface.
+var _ istioconfigreconciler.Interface = (*reconciler)(nil)
+
+// ReconcileKind validates the given IstioConfig and reports whether it is used.
+func (r *reconciler) ReconcileKind(_ context.Context, ic *v1alpha1.IstioConfig) pkgreconciler.Event {
+	ic.Status.InitializeConditions()
+
+	if _, err := config.NewIstioFromIstioConfig(&ic.Spec); err != nil {
+		ic.Status.MarkInvalid(err.Error())
+	} else {
+		ic.Status.MarkValid()
+	}
+
+	if ic.Namespace != system.Namespace() || ic.Name != config.IstioConfigName {
+		ic.Status.MarkInactive("NotWatched", fmt.Sprintf("Only the IstioConfig %s/%s is used.",
+			system.Namespace(), config.IstioConfigName))
+	} else {
+		ic.Status.MarkActive()
+	}
+	return nil
+}
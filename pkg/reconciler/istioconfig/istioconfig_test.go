@@ -0,0 +1,114 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package istioconfig
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgotesting "k8s.io/client-go/testing"
+	"knative.dev/net-istio/pkg/apis/istio/v1alpha1"
+	fakeistioconfigclient "knative.dev/net-istio/pkg/client/injection/client/fake"
+	istioconfigreconciler "knative.dev/net-istio/pkg/client/injection/reconciler/istio/v1alpha1/istioconfig"
+	"knative.dev/net-istio/pkg/reconciler/ingress/config"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/system"
+
+	. "knative.dev/net-istio/pkg/reconciler/testing"
+	. "knative.dev/pkg/reconciler/testing"
+)
+
+func istioConfig(namespace, name string, spec v1alpha1.IstioConfigSpec, status func(*v1alpha1.IstioConfigStatus)) *v1alpha1.IstioConfig {
+	ic := &v1alpha1.IstioConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+		Spec: spec,
+	}
+	if status != nil {
+		ic.Status.InitializeConditions()
+		status(&ic.Status)
+	}
+	return ic
+}
+
+var invalidSpec = v1alpha1.IstioConfigSpec{
+	ExternalGateways: []v1alpha1.Gateway{{
+		Namespace: "knative-serving",
+		Name:      "gateway",
+	}},
+}
+
+func TestReconcile(t *testing.T) {
+	table := TableTest{{
+		Name: "bad workqueue key",
+		Key:  "too/many/parts",
+	}, {
+		Name: "key not found",
+		Key:  "foo/not-found",
+	}, {
+		Name: "valid and active",
+		Key:  system.Namespace() + "/" + config.IstioConfigName,
+		Objects: []runtime.Object{
+			istioConfig(system.Namespace(), config.IstioConfigName, v1alpha1.IstioConfigSpec{}, nil),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: istioConfig(system.Namespace(), config.IstioConfigName, v1alpha1.IstioConfigSpec{},
+				func(s *v1alpha1.IstioConfigStatus) {
+					s.MarkValid()
+					s.MarkActive()
+				}),
+		}},
+	}, {
+		Name: "invalid",
+		Key:  system.Namespace() + "/" + config.IstioConfigName,
+		Objects: []runtime.Object{
+			istioConfig(system.Namespace(), config.IstioConfigName, invalidSpec, nil),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: istioConfig(system.Namespace(), config.IstioConfigName, invalidSpec,
+				func(s *v1alpha1.IstioConfigStatus) {
+					_, err := config.NewIstioFromIstioConfig(&invalidSpec)
+					s.MarkInvalid(err.Error())
+					s.MarkActive()
+				}),
+		}},
+	}, {
+		Name: "not watched",
+		Key:  "default/" + config.IstioConfigName,
+		Objects: []runtime.Object{
+			istioConfig("default", config.IstioConfigName, v1alpha1.IstioConfigSpec{}, nil),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: istioConfig("default", config.IstioConfigName, v1alpha1.IstioConfigSpec{},
+				func(s *v1alpha1.IstioConfigStatus) {
+					s.MarkValid()
+					s.MarkInactive("NotWatched", "Only the IstioConfig "+system.Namespace()+"/"+config.IstioConfigName+" is used.")
+				}),
+		}},
+	}}
+
+	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, _ configmap.Watcher) controller.Reconciler {
+		return istioconfigreconciler.NewReconciler(ctx, logging.FromContext(ctx), fakeistioconfigclient.Get(ctx),
+			listers.GetIstioConfigLister(), controller.GetEventRecorder(ctx), &reconciler{})
+	}))
+}
@@ -19,6 +19,7 @@ package serverlessservice
 import (
 	"context"
 
+	"go.opentelemetry.io/otel"
 	"k8s.io/client-go/tools/cache"
 	istioclient "knative.dev/net-istio/pkg/client/istio/injection/client"
 	destinationruleinformer "knative.dev/net-istio/pkg/client/istio/injection/informers/networking/v1beta1/destinationrule"
@@ -47,6 +48,7 @@ func NewController(
 		istioclient:           istioclient.Get(ctx),
 		virtualServiceLister:  virtualServiceInformer.Lister(),
 		destinationRuleLister: destinationRuleInformer.Lister(),
+		metrics:               newMetrics(otel.GetMeterProvider()),
 	}
 	impl := sksreconciler.NewImpl(ctx, c, func(impl *controller.Impl) controller.Options {
 		resync := configmap.TypeFilter(&config.Istio{})(func(string, interface{}) {
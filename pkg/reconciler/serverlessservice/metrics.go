@@ -0,0 +1,53 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serverlessservice
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/metric"
+	"knative.dev/pkg/observability/attributekey"
+)
+
+const scopeName = "knative.dev/net-istio/pkg/reconciler/serverlessservice"
+
+var kindAttr = attributekey.String("kn.netistio.resource.kind")
+
+// metrics are the metrics of the ServerlessService reconciler. The methods do nothing
+// on nil metrics, e.g. in tests.
+type metrics struct {
+	errors metric.Int64Counter
+}
+
+func newMetrics(provider metric.MeterProvider) *metrics {
+	errors, err := provider.Meter(scopeName).Int64Counter(
+		"kn.netistio.sks.errors",
+		metric.WithDescription("The number of failures to reconcile the resources of a ServerlessService."),
+		metric.WithUnit("{error}"),
+	)
+	if err != nil {
+		panic(err)
+	}
+	return &metrics{errors: errors}
+}
+
+func (m *metrics) recordError(ctx context.Context, kind string) {
+	if m == nil {
+		return
+	}
+	m.errors.Add(ctx, 1, metric.WithAttributes(kindAttr.With(kind)))
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serverlessservice
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestRecordError(t *testing.T) {
+	ctx := context.Background()
+
+	// Nil metrics, as in the table tests, record nothing.
+	var nilMetrics *metrics
+	nilMetrics.recordError(ctx, "VirtualService")
+
+	reader := sdkmetric.NewManualReader()
+	m := newMetrics(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	m.recordError(ctx, "VirtualService")
+	m.recordError(ctx, "VirtualService")
+	m.recordError(ctx, "DestinationRule")
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatal("Collect() =", err)
+	}
+	got := make(map[string]int64)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			for _, point := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				kind, _ := point.Attributes.Value(kindAttr.With("").Key)
+				got[kind.AsString()] = point.Value
+			}
+		}
+	}
+	want := map[string]int64{"VirtualService": 2, "DestinationRule": 1}
+	if !cmp.Equal(got, want) {
+		t.Error("Errors (-want, +got):", cmp.Diff(want, got))
+	}
+}
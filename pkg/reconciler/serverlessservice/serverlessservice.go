@@ -37,6 +37,8 @@ type reconciler struct {
 
 	virtualServiceLister  istiolisters.VirtualServiceLister
 	destinationRuleLister istiolisters.DestinationRuleLister
+
+	metrics *metrics
 }
 
 // Check that our Reconciler implements various interfaces.
@@ -60,11 +62,13 @@ func (r *reconciler) ReconcileKind(ctx context.Context, sks *netv1alpha1.Serverl
 
 	vs := resources.MakeVirtualService(sks)
 	if _, err := istioaccessor.ReconcileVirtualService(ctx, sks, vs, r); err != nil {
+		r.metrics.recordError(ctx, "VirtualService")
 		return fmt.Errorf("failed to reconcile VirtualService: %w", err)
 	}
 
 	dr := resources.MakeDestinationRule(sks)
 	if _, err := istioaccessor.ReconcileDestinationRule(ctx, sks, dr, r); err != nil {
+		r.metrics.recordError(ctx, "DestinationRule")
 		return fmt.Errorf("failed to reconcile DestinationRule: %w", err)
 	}
 
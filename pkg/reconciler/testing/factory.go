@@ -20,6 +20,7 @@ import (
 	"context"
 	"testing"
 
+	fakeistioconfigclient "knative.dev/net-istio/pkg/client/injection/client/fake"
 	fakeistioclient "knative.dev/net-istio/pkg/client/istio/injection/client/fake"
 	"knative.dev/networking/pkg/apis/networking/v1alpha1"
 	fakenetworkingclient "knative.dev/networking/pkg/client/injection/client/fake"
@@ -66,6 +67,7 @@ func MakeFactory(ctor Ctor) rtesting.Factory {
 		ctx, client := fakenetworkingclient.With(ctx, ls.GetNetworkingObjects()...)
 		ctx, istioclient := fakeistioclient.With(ctx, ls.GetIstioObjects()...)
 		ctx, kubeclient := fakekubeclient.With(ctx, ls.GetKubeObjects()...)
+		ctx, istioconfigclient := fakeistioconfigclient.With(ctx, ls.GetIstioConfigObjects()...)
 
 		ctx = context.WithValue(ctx, FakeStatusManagerKey, &fakestatusmanager.FakeStatusManager{
 			FakeIsReady: func(ctx context.Context, ing *v1alpha1.Ingress) (bool, error) {
@@ -78,6 +80,7 @@ func MakeFactory(ctor Ctor) rtesting.Factory {
 		rtesting.PrependGenerateNameReactor(&client.Fake)
 		rtesting.PrependGenerateNameReactor(&istioclient.Fake)
 		rtesting.PrependGenerateNameReactor(&kubeclient.Fake)
+		rtesting.PrependGenerateNameReactor(&istioconfigclient.Fake)
 
 		// Set up our Controller from the fakes.
 		c := ctor(ctx, &ls, configmap.NewStaticWatcher())
@@ -93,6 +96,7 @@ func MakeFactory(ctor Ctor) rtesting.Factory {
 			client.PrependReactor("*", "*", reactor)
 			istioclient.PrependReactor("*", "*", reactor)
 			kubeclient.PrependReactor("*", "*", reactor)
+			istioconfigclient.PrependReactor("*", "*", reactor)
 		}
 
 		// Validate all Create operations through the serving client.
@@ -105,7 +109,7 @@ func MakeFactory(ctor Ctor) rtesting.Factory {
 			return rtesting.ValidateUpdates(context.Background(), action)
 		})
 
-		actionRecorderList := rtesting.ActionRecorderList{client, istioclient, kubeclient}
+		actionRecorderList := rtesting.ActionRecorderList{client, istioclient, kubeclient, istioconfigclient}
 		eventList := rtesting.EventList{Recorder: eventRecorder}
 
 		return c, actionRecorderList, eventList
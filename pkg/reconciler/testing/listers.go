@@ -25,6 +25,9 @@ import (
 	fakekubeclientset "k8s.io/client-go/kubernetes/fake"
 	corev1listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
+	istioconfigv1alpha1 "knative.dev/net-istio/pkg/apis/istio/v1alpha1"
+	fakeistioconfigclientset "knative.dev/net-istio/pkg/client/clientset/versioned/fake"
+	istioconfiglisters "knative.dev/net-istio/pkg/client/listers/istio/v1alpha1"
 	networking "knative.dev/networking/pkg/apis/networking/v1alpha1"
 	fakenetworkingclientset "knative.dev/networking/pkg/client/clientset/versioned/fake"
 	networkinglisters "knative.dev/networking/pkg/client/listers/networking/v1alpha1"
@@ -35,6 +38,7 @@ var clientSetSchemes = []func(*runtime.Scheme) error{
 	fakenetworkingclientset.AddToScheme,
 	fakeistioclientset.AddToScheme,
 	fakekubeclientset.AddToScheme,
+	fakeistioconfigclientset.AddToScheme,
 }
 
 type Listers struct {
@@ -83,6 +87,10 @@ func (l *Listers) GetKubeObjects() []runtime.Object {
 	return l.sorter.ObjectsForSchemeFunc(fakekubeclientset.AddToScheme)
 }
 
+func (l *Listers) GetIstioConfigObjects() []runtime.Object {
+	return l.sorter.ObjectsForSchemeFunc(fakeistioconfigclientset.AddToScheme)
+}
+
 // GetIngressLister get lister for Ingress resource.
 func (l *Listers) GetIngressLister() networkinglisters.IngressLister {
 	return networkinglisters.NewIngressLister(l.IndexerFor(&networking.Ingress{}))
@@ -93,6 +101,11 @@ func (l *Listers) GetServerlessServiceLister() networkinglisters.ServerlessServi
 	return networkinglisters.NewServerlessServiceLister(l.IndexerFor(&networking.ServerlessService{}))
 }
 
+// GetIstioConfigLister get lister for IstioConfig resource.
+func (l *Listers) GetIstioConfigLister() istioconfiglisters.IstioConfigLister {
+	return istioconfiglisters.NewIstioConfigLister(l.IndexerFor(&istioconfigv1alpha1.IstioConfig{}))
+}
+
 // GetGatewayLister get lister for Gateway resource.
 func (l *Listers) GetGatewayLister() istiolisters.GatewayLister {
 	return istiolisters.NewGatewayLister(l.IndexerFor(&istiov1beta1.Gateway{}))
@@ -118,6 +131,11 @@ func (l *Listers) GetEndpointsLister() corev1listers.EndpointsLister {
 	return corev1listers.NewEndpointsLister(l.IndexerFor(&corev1.Endpoints{}))
 }
 
+// GetNamespaceLister get lister for K8s Namespace resource.
+func (l *Listers) GetNamespaceLister() corev1listers.NamespaceLister {
+	return corev1listers.NewNamespaceLister(l.IndexerFor(&corev1.Namespace{}))
+}
+
 // GetSecretLister get lister for K8s Secret resource.
 func (l *Listers) GetSecretLister() corev1listers.SecretLister {
 	return corev1listers.NewSecretLister(l.IndexerFor(&corev1.Secret{}))
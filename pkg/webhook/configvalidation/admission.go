@@ -0,0 +1,135 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configvalidation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/net-istio/pkg/reconciler/ingress/config"
+	"knative.dev/pkg/webhook"
+)
+
+const (
+	// AllowGatewayChangesAnnotationKey is the annotation of config-istio allowing an update
+	// to move Ingresses to other gateways in strict mode.
+	AllowGatewayChangesAnnotationKey = "istio.networking.knative.dev/allow-gateway-changes"
+
+	// legacyFormatWarning is returned for ConfigMaps configuring gateways with the legacy keys.
+	legacyFormatWarning = "the gateway.* and local-gateway.* keys are deprecated, " +
+		"use external-gateways and local-gateways instead, e.g. by running cmd/migrate-config on the ConfigMap"
+
+	// maxGatewayChanges is the number of Ingresses listed when an update moves Ingresses.
+	maxGatewayChanges = 10
+)
+
+// admissionController adds the checks against the cluster state to the admission of
+// config-istio. As it reads informers, it is not stateless and requests are only
+// admitted once they synced.
+type admissionController struct {
+	admissionReconciler
+
+	checker *Checker
+
+	// strictGatewayChanges rejects updates moving Ingresses to other gateways, unless
+	// allowed by annotation.
+	strictGatewayChanges bool
+}
+
+// Admit implements webhook.AdmissionController.
+func (ac *admissionController) Admit(ctx context.Context, request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	resp := ac.admissionReconciler.Admit(ctx, request)
+	if !resp.Allowed || request.Name != config.IstioConfigName {
+		return resp
+	}
+	switch request.Operation {
+	case admissionv1.Create, admissionv1.Update:
+	default:
+		return resp
+	}
+
+	var configMap corev1.ConfigMap
+	if err := json.Unmarshal(request.Object.Raw, &configMap); err != nil {
+		return webhook.MakeErrorStatus("cannot decode incoming new object: %v", err)
+	}
+	istio, err := config.NewIstioFromConfigMap(&configMap)
+	if err != nil {
+		return webhook.MakeErrorStatus("validation failed: %v", err)
+	}
+
+	if config.UsesLegacyFormat(&configMap) {
+		resp.Warnings = append(resp.Warnings, legacyFormatWarning)
+	}
+
+	warnings, err := ac.checker.Check(ctx, istio)
+	if err != nil {
+		return webhook.MakeErrorStatus("validation failed: %v", err)
+	}
+	resp.Warnings = append(resp.Warnings, warnings...)
+
+	networkWarnings, err := ac.checker.NetworkWarnings(ctx, istio)
+	if err != nil {
+		return webhook.MakeErrorStatus("validation failed: %v", err)
+	}
+	resp.Warnings = append(resp.Warnings, networkWarnings...)
+
+	if request.Operation == admissionv1.Update {
+		changeWarnings, err := ac.checkGatewayChanges(request, &configMap, istio)
+		if err != nil {
+			return webhook.MakeErrorStatus("validation failed: %v", err)
+		}
+		resp.Warnings = append(resp.Warnings, changeWarnings...)
+	}
+	return resp
+}
+
+// checkGatewayChanges returns a warning for each Ingress the update moves to other gateways,
+// or an error listing them in strict mode.
+func (ac *admissionController) checkGatewayChanges(request *admissionv1.AdmissionRequest, configMap *corev1.ConfigMap, istio *config.Istio) ([]string, error) {
+	var oldConfigMap corev1.ConfigMap
+	if err := json.Unmarshal(request.OldObject.Raw, &oldConfigMap); err != nil {
+		return nil, fmt.Errorf("cannot decode incoming old object: %w", err)
+	}
+	oldIstio, err := config.NewIstioFromConfigMap(&oldConfigMap)
+	if err != nil {
+		//nolint:nilerr // The controller never used the invalid configuration, nothing moves.
+		return nil, nil
+	}
+
+	changes, err := ac.checker.GatewayChanges(oldIstio, istio)
+	if err != nil || len(changes) == 0 {
+		return nil, err
+	}
+
+	listed := make([]string, 0, min(len(changes), maxGatewayChanges)+1)
+	for _, change := range changes[:min(len(changes), maxGatewayChanges)] {
+		listed = append(listed, change.String())
+	}
+	if len(changes) > maxGatewayChanges {
+		listed = append(listed, fmt.Sprintf("%d more Ingresses move to other gateways", len(changes)-maxGatewayChanges))
+	}
+
+	if ac.strictGatewayChanges && configMap.Annotations[AllowGatewayChangesAnnotationKey] != "true" {
+		return nil, fmt.Errorf("the update moves %d Ingresses to other gateways, annotate the ConfigMap with %s: \"true\" to allow it: %s",
+			len(changes), AllowGatewayChangesAnnotationKey, strings.Join(listed, "; "))
+	}
+	return listed, nil
+}
@@ -0,0 +1,240 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configvalidation
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"istio.io/client-go/pkg/apis/networking/v1beta1"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakekubeclientset "k8s.io/client-go/kubernetes/fake"
+	"knative.dev/net-istio/pkg/reconciler/ingress/config"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+	netconfig "knative.dev/networking/pkg/config"
+	pkgreconciler "knative.dev/pkg/reconciler"
+	"knative.dev/pkg/system"
+
+	. "knative.dev/net-istio/pkg/reconciler/testing"
+)
+
+// allowAll stands in for the ConfigMap admission controller of knative.dev/pkg.
+type allowAll struct {
+	pkgreconciler.LeaderAwareFuncs
+}
+
+func (*allowAll) Reconcile(context.Context, string) error { return nil }
+func (*allowAll) Path() string                            { return "/config-validation" }
+func (*allowAll) Admit(context.Context, *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{Allowed: true}
+}
+
+var (
+	defaultGatewayData = map[string]string{
+		"external-gateways": `[{"namespace": "knative-serving", "name": "knative-ingress-gateway", "service": "istio-ingressgateway.istio-system.svc.cluster.local"}]`,
+		"local-gateways":    "[]",
+	}
+
+	internalGatewayData = map[string]string{
+		"external-gateways": `[{"namespace": "knative-serving", "name": "knative-ingress-gateway", "service": "istio-ingressgateway.istio-system.svc.cluster.local"},
+			{"namespace": "knative-serving", "name": "internal-gateway", "service": "istio-ingressgateway.istio-system.svc.cluster.local", "namespaceSelector": {"matchLabels": {"tier": "internal"}}}]`,
+		"local-gateways": "[]",
+	}
+
+	internalGatewayObjects = []runtime.Object{
+		ingressGateway, ingressService, ingressPod,
+		&v1beta1.Gateway{ObjectMeta: metav1.ObjectMeta{Namespace: "knative-serving", Name: "internal-gateway"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "internal", Labels: map[string]string{"tier": "internal"}}},
+		ingress("internal", "foo", v1alpha1.IngressVisibilityExternalIP, nil),
+	}
+
+	internalGatewayChange = "Ingress internal/foo moves from gateways [knative-serving/knative-ingress-gateway] to [knative-serving/internal-gateway]"
+)
+
+func TestAdmit(t *testing.T) {
+	tests := []struct {
+		name         string
+		operation    admissionv1.Operation
+		configName   string
+		data         map[string]string
+		oldData      map[string]string
+		annotations  map[string]string
+		strict       bool
+		network      map[string]string
+		objects      []runtime.Object
+		wantAllowed  bool
+		wantWarnings []string
+	}{{
+		name:        "valid",
+		operation:   admissionv1.Update,
+		configName:  config.IstioConfigName,
+		data:        defaultGatewayData,
+		oldData:     defaultGatewayData,
+		objects:     []runtime.Object{ingressGateway, ingressService, ingressPod},
+		wantAllowed: true,
+	}, {
+		name:        "missing gateway",
+		operation:   admissionv1.Create,
+		configName:  config.IstioConfigName,
+		data:        defaultGatewayData,
+		objects:     []runtime.Object{ingressService, ingressPod},
+		wantAllowed: false,
+	}, {
+		name:        "delete is not checked",
+		operation:   admissionv1.Delete,
+		configName:  config.IstioConfigName,
+		wantAllowed: true,
+	}, {
+		name:        "other ConfigMap",
+		operation:   admissionv1.Update,
+		configName:  "config-network",
+		wantAllowed: true,
+	}, {
+		name:        "warnings",
+		operation:   admissionv1.Create,
+		configName:  config.IstioConfigName,
+		data:        defaultGatewayData,
+		objects:     []runtime.Object{ingressGateway, ingressPod, withPorts(ingressService, corev1.ServicePort{Port: 80})},
+		wantAllowed: true,
+		wantWarnings: []string{
+			"gateway knative-serving/knative-ingress-gateway: the Service istio-system/istio-ingressgateway does not expose port 443, HTTPS traffic can not reach the gateway",
+		},
+	}, {
+		name:       "inconsistent with config-network",
+		operation:  admissionv1.Create,
+		configName: config.IstioConfigName,
+		data: map[string]string{
+			"external-gateways": "[]",
+			"local-gateways":    "[]",
+		},
+		network:     map[string]string{netconfig.ClusterLocalDomainTLSKey: "enabled"},
+		wantAllowed: true,
+		wantWarnings: []string{
+			"cluster-local-domain-tls is enabled in mesh-only mode, where no gateway terminates TLS for cluster-local domains",
+		},
+	}, {
+		name:       "legacy format",
+		operation:  admissionv1.Create,
+		configName: config.IstioConfigName,
+		data: map[string]string{
+			"gateway.knative-serving.knative-ingress-gateway":     "istio-ingressgateway.istio-system.svc.cluster.local",
+			"local-gateway.knative-serving.knative-local-gateway": "istio-ingressgateway.istio-system.svc.cluster.local",
+		},
+		objects: []runtime.Object{ingressService, ingressPod, ingressGateway,
+			&v1beta1.Gateway{ObjectMeta: metav1.ObjectMeta{Namespace: "knative-serving", Name: "knative-local-gateway"}}},
+		wantAllowed:  true,
+		wantWarnings: []string{legacyFormatWarning},
+	}, {
+		name:         "update moving Ingresses",
+		operation:    admissionv1.Update,
+		configName:   config.IstioConfigName,
+		data:         internalGatewayData,
+		oldData:      defaultGatewayData,
+		objects:      internalGatewayObjects,
+		wantAllowed:  true,
+		wantWarnings: []string{internalGatewayChange},
+	}, {
+		name:        "strict update moving Ingresses",
+		operation:   admissionv1.Update,
+		configName:  config.IstioConfigName,
+		data:        internalGatewayData,
+		oldData:     defaultGatewayData,
+		strict:      true,
+		objects:     internalGatewayObjects,
+		wantAllowed: false,
+	}, {
+		name:         "strict update moving Ingresses allowed by annotation",
+		operation:    admissionv1.Update,
+		configName:   config.IstioConfigName,
+		data:         internalGatewayData,
+		oldData:      defaultGatewayData,
+		annotations:  map[string]string{AllowGatewayChangesAnnotationKey: "true"},
+		strict:       true,
+		objects:      internalGatewayObjects,
+		wantAllowed:  true,
+		wantWarnings: []string{internalGatewayChange},
+	}, {
+		name:        "update from an invalid configuration",
+		operation:   admissionv1.Update,
+		configName:  config.IstioConfigName,
+		data:        internalGatewayData,
+		oldData:     map[string]string{"external-gateways": "invalid"},
+		strict:      true,
+		objects:     internalGatewayObjects,
+		wantAllowed: true,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listers := NewListers(tt.objects)
+			kubeObjects := listers.GetKubeObjects()
+			if tt.network != nil {
+				kubeObjects = append(kubeObjects, &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: system.Namespace(), Name: netconfig.ConfigMapName},
+					Data:       tt.network,
+				})
+			}
+			kubeClient := fakekubeclientset.NewSimpleClientset(kubeObjects...)
+			ac := &admissionController{
+				admissionReconciler: &allowAll{},
+				checker: &Checker{
+					GatewayLister:   listers.GetGatewayLister(),
+					ServiceLister:   listers.GetK8sServiceLister(),
+					IngressLister:   listers.GetIngressLister(),
+					NamespaceLister: listers.GetNamespaceLister(),
+					KubeClient:      kubeClient,
+				},
+				strictGatewayChanges: tt.strict,
+			}
+
+			request := &admissionv1.AdmissionRequest{
+				Operation: tt.operation,
+				Namespace: system.Namespace(),
+				Name:      tt.configName,
+				Object:    rawConfigMap(t, tt.configName, tt.annotations, tt.data),
+			}
+			if tt.oldData != nil {
+				request.OldObject = rawConfigMap(t, tt.configName, nil, tt.oldData)
+			}
+
+			resp := ac.Admit(context.Background(), request)
+			if resp.Allowed != tt.wantAllowed {
+				t.Errorf("Allowed = %v, want %v: %v", resp.Allowed, tt.wantAllowed, resp.Result)
+			}
+			if diff := cmp.Diff(tt.wantWarnings, resp.Warnings); diff != "" {
+				t.Error("Unexpected warnings (-want, +got):", diff)
+			}
+		})
+	}
+}
+
+func rawConfigMap(t *testing.T, name string, annotations, data map[string]string) runtime.RawExtension {
+	t.Helper()
+	raw, err := json.Marshal(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: system.Namespace(), Name: name, Annotations: annotations},
+		Data:       data,
+	})
+	if err != nil {
+		t.Fatal("json.Marshal() =", err)
+	}
+	return runtime.RawExtension{Raw: raw}
+}
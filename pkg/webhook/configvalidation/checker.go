@@ -0,0 +1,135 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configvalidation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	istiolisters "istio.io/client-go/pkg/listers/networking/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"knative.dev/net-istio/pkg/reconciler/ingress/config"
+	networkinglisters "knative.dev/networking/pkg/client/listers/networking/v1alpha1"
+)
+
+// Checker validates the config-istio against the state of the cluster.
+type Checker struct {
+	GatewayLister istiolisters.GatewayLister
+	ServiceLister corev1listers.ServiceLister
+
+	IngressLister   networkinglisters.IngressLister
+	NamespaceLister corev1listers.NamespaceLister
+
+	KubeClient kubernetes.Interface
+}
+
+// Check returns an error when a configured Gateway or the Service backing it does not
+// exist or can not serve plain HTTP traffic. Problems that likely break some of the
+// traffic, but may be intended or transient, are returned as warnings.
+func (c *Checker) Check(ctx context.Context, istio *config.Istio) ([]string, error) {
+	var (
+		warnings []string
+		errs     []error
+	)
+	gateways := make([]config.Gateway, 0, len(istio.IngressGateways)+len(istio.LocalGateways))
+	gateways = append(gateways, istio.IngressGateways...)
+	gateways = append(gateways, istio.LocalGateways...)
+	for _, gateway := range gateways {
+		gatewayWarnings, err := c.checkGateway(ctx, gateway)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("gateway %s: %w", gateway.QualifiedName(), err))
+		}
+		for _, warning := range gatewayWarnings {
+			warnings = append(warnings, fmt.Sprintf("gateway %s: %s", gateway.QualifiedName(), warning))
+		}
+	}
+	return warnings, errors.Join(errs...)
+}
+
+func (c *Checker) checkGateway(ctx context.Context, gateway config.Gateway) ([]string, error) {
+	gtw, err := c.GatewayLister.Gateways(gateway.Namespace).Get(gateway.Name)
+	if apierrs.IsNotFound(err) {
+		return nil, errors.New("the Gateway does not exist")
+	} else if err != nil {
+		return nil, err
+	}
+
+	svc, err := c.gatewayService(gateway.ServiceURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings []string
+	if !hasPort(svc, 80) {
+		return nil, fmt.Errorf("the Service %s/%s does not expose port 80", svc.Namespace, svc.Name)
+	}
+	if !hasPort(svc, 443) {
+		warnings = append(warnings, fmt.Sprintf("the Service %s/%s does not expose port 443, HTTPS traffic can not reach the gateway",
+			svc.Namespace, svc.Name))
+	}
+
+	gatewaySelector := gtw.Spec.GetSelector()
+	if len(svc.Spec.Selector) == 0 || len(gatewaySelector) == 0 {
+		return warnings, nil
+	}
+	// Only the pods selected by both the Service and the Gateway are listed, rather than
+	// caching all the pods of the cluster in the webhook.
+	requirements, _ := labels.SelectorFromSet(gatewaySelector).Requirements()
+	selector := labels.SelectorFromSet(svc.Spec.Selector).Add(requirements...)
+	pods, err := c.KubeClient.CoreV1().Pods(svc.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: selector.String(),
+		Limit:         1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(pods.Items) == 0 {
+		warnings = append(warnings, fmt.Sprintf("the selector %v of the Gateway does not match any pod of the Service %s/%s",
+			gatewaySelector, svc.Namespace, svc.Name))
+	}
+	return warnings, nil
+}
+
+// gatewayService returns the Service the given hostname, e.g.
+// istio-ingressgateway.istio-system.svc.cluster.local, resolves to.
+func (c *Checker) gatewayService(serviceURL string) (*corev1.Service, error) {
+	parts := strings.SplitN(strings.TrimSuffix(serviceURL, "."), ".", 3)
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("the service %q is not a Service hostname", serviceURL)
+	}
+	svc, err := c.ServiceLister.Services(parts[1]).Get(parts[0])
+	if apierrs.IsNotFound(err) {
+		return nil, fmt.Errorf("the Service %s/%s does not exist", parts[1], parts[0])
+	}
+	return svc, err
+}
+
+func hasPort(svc *corev1.Service, port int32) bool {
+	for _, p := range svc.Spec.Ports {
+		if p.Port == port {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configvalidation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	istiov1beta1 "istio.io/api/networking/v1beta1"
+	"istio.io/client-go/pkg/apis/networking/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakekubeclientset "k8s.io/client-go/kubernetes/fake"
+	"knative.dev/net-istio/pkg/reconciler/ingress/config"
+
+	. "knative.dev/net-istio/pkg/reconciler/testing"
+)
+
+var (
+	gatewayLabels = map[string]string{"istio": "ingressgateway"}
+
+	ingressGateway = &v1beta1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "knative-serving", Name: "knative-ingress-gateway"},
+		Spec:       istiov1beta1.Gateway{Selector: gatewayLabels},
+	}
+
+	ingressService = &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "istio-system", Name: "istio-ingressgateway"},
+		Spec: corev1.ServiceSpec{
+			Selector: gatewayLabels,
+			Ports:    []corev1.ServicePort{{Name: "http2", Port: 80}, {Name: "https", Port: 443}},
+		},
+	}
+
+	ingressPod = &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "istio-system", Name: "istio-ingressgateway-abcde", Labels: gatewayLabels},
+	}
+
+	istioConfig = &config.Istio{
+		IngressGateways: []config.Gateway{{
+			Namespace:  "knative-serving",
+			Name:       "knative-ingress-gateway",
+			ServiceURL: "istio-ingressgateway.istio-system.svc.cluster.local",
+		}},
+	}
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name         string
+		objects      []runtime.Object
+		wantWarnings []string
+		wantErr      string
+	}{{
+		name:    "valid",
+		objects: []runtime.Object{ingressGateway, ingressService, ingressPod},
+	}, {
+		name:    "missing gateway",
+		objects: []runtime.Object{ingressService, ingressPod},
+		wantErr: "gateway knative-serving/knative-ingress-gateway: the Gateway does not exist",
+	}, {
+		name:    "missing service",
+		objects: []runtime.Object{ingressGateway, ingressPod},
+		wantErr: "gateway knative-serving/knative-ingress-gateway: the Service istio-system/istio-ingressgateway does not exist",
+	}, {
+		name: "missing http port",
+		objects: []runtime.Object{ingressGateway, ingressPod, withPorts(ingressService,
+			corev1.ServicePort{Name: "https", Port: 443})},
+		wantErr: "gateway knative-serving/knative-ingress-gateway: the Service istio-system/istio-ingressgateway does not expose port 80",
+	}, {
+		name: "missing https port",
+		objects: []runtime.Object{ingressGateway, ingressPod, withPorts(ingressService,
+			corev1.ServicePort{Name: "http2", Port: 80})},
+		wantWarnings: []string{
+			"gateway knative-serving/knative-ingress-gateway: the Service istio-system/istio-ingressgateway does not expose port 443, HTTPS traffic can not reach the gateway",
+		},
+	}, {
+		name: "gateway selector does not match the pods",
+		objects: []runtime.Object{ingressService, ingressPod, &v1beta1.Gateway{
+			ObjectMeta: ingressGateway.ObjectMeta,
+			Spec:       istiov1beta1.Gateway{Selector: map[string]string{"istio": "other-gateway"}},
+		}},
+		wantWarnings: []string{
+			"gateway knative-serving/knative-ingress-gateway: the selector map[istio:other-gateway] of the Gateway does not match any pod of the Service istio-system/istio-ingressgateway",
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listers := NewListers(tt.objects)
+			checker := &Checker{
+				GatewayLister: listers.GetGatewayLister(),
+				ServiceLister: listers.GetK8sServiceLister(),
+				KubeClient:    fakekubeclientset.NewSimpleClientset(listers.GetKubeObjects()...),
+			}
+
+			warnings, err := checker.Check(context.Background(), istioConfig)
+			if got := errString(err); got != tt.wantErr {
+				t.Errorf("Check() error = %q, want %q", got, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.wantWarnings, warnings); diff != "" {
+				t.Error("Unexpected warnings (-want, +got):", diff)
+			}
+		})
+	}
+}
+
+func withPorts(svc *corev1.Service, ports ...corev1.ServicePort) *corev1.Service {
+	svc = svc.DeepCopy()
+	svc.Spec.Ports = ports
+	return svc
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
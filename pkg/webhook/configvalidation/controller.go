@@ -0,0 +1,86 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configvalidation
+
+import (
+	"context"
+	"os"
+	"strconv"
+
+	gatewayinformer "knative.dev/net-istio/pkg/client/istio/injection/informers/networking/v1beta1/gateway"
+	"knative.dev/net-istio/pkg/reconciler/ingress/config"
+	ingressinformer "knative.dev/networking/pkg/client/injection/informers/networking/v1alpha1/ingress"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	namespaceinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/namespace"
+	serviceinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/service"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	pkgreconciler "knative.dev/pkg/reconciler"
+	"knative.dev/pkg/webhook"
+	"knative.dev/pkg/webhook/configmaps"
+)
+
+// StrictGatewayChangesEnvKey is the environment variable which, when set to true, makes the
+// webhook reject updates of config-istio moving existing Ingresses to other gateways.
+const StrictGatewayChangesEnvKey = "STRICT_GATEWAY_CHANGES"
+
+// admissionReconciler is implemented by the ConfigMap admission controller of knative.dev/pkg.
+type admissionReconciler interface {
+	controller.Reconciler
+	pkgreconciler.LeaderAware
+	webhook.AdmissionController
+}
+
+// NewAdmissionController constructs the webhook validating the config-istio ConfigMap.
+// On top of the parsing done for every ConfigMap, it checks the configuration against
+// the Gateways and Services in the cluster and the config-network ConfigMap, and reports
+// the Ingresses an update moves to other gateways.
+func NewAdmissionController(ctx context.Context, _ configmap.Watcher) *controller.Impl {
+	impl := configmaps.NewAdmissionController(ctx,
+
+		// Name of the resource webhook.
+		"config.webhook.istio.networking.internal.knative.dev",
+
+		// The path on which to serve the webhook.
+		"/config-validation",
+
+		// The configmaps to validate.
+		configmap.Constructors{
+			config.IstioConfigName: config.NewIstioFromConfigMap,
+		},
+	)
+
+	impl.Reconciler = &admissionController{
+		admissionReconciler: impl.Reconciler.(admissionReconciler),
+		checker: &Checker{
+			GatewayLister: gatewayinformer.Get(ctx).Lister(),
+			ServiceLister: serviceinformer.Get(ctx).Lister(),
+
+			IngressLister:   ingressinformer.Get(ctx).Lister(),
+			NamespaceLister: namespaceinformer.Get(ctx).Lister(),
+
+			KubeClient: kubeclient.Get(ctx),
+		},
+		strictGatewayChanges: strictGatewayChangesFromEnv(),
+	}
+	return impl
+}
+
+func strictGatewayChangesFromEnv() bool {
+	strict, _ := strconv.ParseBool(os.Getenv(StrictGatewayChangesEnvKey))
+	return strict
+}
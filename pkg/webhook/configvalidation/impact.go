@@ -0,0 +1,113 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configvalidation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"knative.dev/net-istio/pkg/reconciler/ingress/config"
+	"knative.dev/net-istio/pkg/reconciler/ingress/resources"
+	"knative.dev/networking/pkg/apis/networking"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+	netconfig "knative.dev/networking/pkg/config"
+	"knative.dev/pkg/reconciler"
+)
+
+// GatewayChange is an Ingress whose gateways change with a new configuration.
+type GatewayChange struct {
+	Namespace string
+	Name      string
+	Old       []string
+	New       []string
+}
+
+// String implements fmt.Stringer.
+func (c GatewayChange) String() string {
+	return fmt.Sprintf("Ingress %s/%s moves from gateways %v to %v", c.Namespace, c.Name, c.Old, c.New)
+}
+
+var isIstioIngress = reconciler.AnnotationFilterFunc(networking.IngressClassAnnotationKey, netconfig.IstioIngressClassName, true)
+
+// GatewayChanges returns the Ingresses whose gateways change when replacing the old with the
+// new configuration, sorted by namespace and name. The gateways are selected like the
+// Ingress reconciler does.
+func (c *Checker) GatewayChanges(oldIstio, newIstio *config.Istio) ([]GatewayChange, error) {
+	ingresses, err := c.IngressLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(ingresses, func(i, j int) bool {
+		if ingresses[i].Namespace != ingresses[j].Namespace {
+			return ingresses[i].Namespace < ingresses[j].Namespace
+		}
+		return ingresses[i].Name < ingresses[j].Name
+	})
+
+	var changes []GatewayChange
+	for _, ing := range ingresses {
+		if !isIstioIngress(ing) || ing.GetDeletionTimestamp() != nil {
+			continue
+		}
+		ctx := context.Background()
+		ns, err := c.NamespaceLister.Get(ing.Namespace)
+		if err != nil && !apierrs.IsNotFound(err) {
+			return nil, err
+		} else if err == nil {
+			ctx = resources.WithNamespaceLabels(ctx, ns.Labels)
+		}
+
+		oldGateways, err := ingressGateways(config.ToContext(ctx, &config.Config{Istio: oldIstio}), ing)
+		if err != nil {
+			return nil, err
+		}
+		newGateways, err := ingressGateways(config.ToContext(ctx, &config.Config{Istio: newIstio}), ing)
+		if err != nil {
+			return nil, err
+		}
+		if !oldGateways.Equal(newGateways) {
+			changes = append(changes, GatewayChange{
+				Namespace: ing.Namespace,
+				Name:      ing.Name,
+				Old:       sets.List(oldGateways),
+				New:       sets.List(newGateways),
+			})
+		}
+	}
+	return changes, nil
+}
+
+// ingressGateways returns the qualified names of the gateways serving the given Ingress.
+// The external gateways are only relevant when the Ingress has public rules.
+func ingressGateways(ctx context.Context, ing *v1alpha1.Ingress) (sets.Set[string], error) {
+	gateways, err := resources.QualifiedGatewayNamesFromContext(ctx, ing)
+	if err != nil {
+		return nil, err
+	}
+	ret := gateways[v1alpha1.IngressVisibilityClusterLocal]
+	for _, rule := range ing.Spec.Rules {
+		if rule.Visibility == v1alpha1.IngressVisibilityExternalIP {
+			ret = ret.Union(gateways[v1alpha1.IngressVisibilityExternalIP])
+			break
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configvalidation
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/net-istio/pkg/reconciler/ingress/config"
+	"knative.dev/networking/pkg/apis/networking"
+	"knative.dev/networking/pkg/apis/networking/v1alpha1"
+
+	. "knative.dev/net-istio/pkg/reconciler/testing"
+)
+
+var (
+	localGateway = config.Gateway{
+		Namespace:  "knative-serving",
+		Name:       "knative-local-gateway",
+		ServiceURL: "knative-local-gateway.istio-system.svc.cluster.local",
+	}
+
+	defaultGateway = config.Gateway{
+		Namespace:  "knative-serving",
+		Name:       "knative-ingress-gateway",
+		ServiceURL: "istio-ingressgateway.istio-system.svc.cluster.local",
+	}
+
+	internalGateway = config.Gateway{
+		Namespace:  "knative-serving",
+		Name:       "internal-gateway",
+		ServiceURL: "internal-ingressgateway.istio-system.svc.cluster.local",
+		NamespaceSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"tier": "internal"},
+		},
+	}
+)
+
+func ingress(namespace, name string, visibility v1alpha1.IngressVisibility, annotations map[string]string) *v1alpha1.Ingress {
+	return &v1alpha1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Annotations: annotations},
+		Spec: v1alpha1.IngressSpec{Rules: []v1alpha1.IngressRule{{
+			Hosts:      []string{name + "." + namespace + ".example.com"},
+			Visibility: visibility,
+		}}},
+	}
+}
+
+func TestGatewayChanges(t *testing.T) {
+	oldIstio := &config.Istio{
+		IngressGateways: []config.Gateway{defaultGateway},
+		LocalGateways:   []config.Gateway{localGateway},
+	}
+
+	tests := []struct {
+		name     string
+		newIstio *config.Istio
+		objects  []runtime.Object
+		want     []GatewayChange
+	}{{
+		name:     "no change",
+		newIstio: oldIstio,
+		objects: []runtime.Object{
+			ingress("default", "foo", v1alpha1.IngressVisibilityExternalIP, nil),
+		},
+	}, {
+		name: "namespace selected by new gateway",
+		newIstio: &config.Istio{
+			IngressGateways: []config.Gateway{defaultGateway, internalGateway},
+			LocalGateways:   []config.Gateway{localGateway},
+		},
+		objects: []runtime.Object{
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "internal", Labels: map[string]string{"tier": "internal"}}},
+			ingress("internal", "foo", v1alpha1.IngressVisibilityExternalIP, nil),
+			ingress("internal", "local", v1alpha1.IngressVisibilityClusterLocal, nil),
+			ingress("default", "bar", v1alpha1.IngressVisibilityExternalIP, nil),
+		},
+		want: []GatewayChange{{
+			Namespace: "internal",
+			Name:      "foo",
+			Old:       []string{"knative-serving/knative-ingress-gateway", "knative-serving/knative-local-gateway"},
+			New:       []string{"knative-serving/internal-gateway", "knative-serving/knative-local-gateway"},
+		}},
+	}, {
+		name: "local gateways removed",
+		newIstio: &config.Istio{
+			IngressGateways: []config.Gateway{defaultGateway},
+			LocalGateways:   []config.Gateway{},
+		},
+		objects: []runtime.Object{
+			ingress("default", "foo", v1alpha1.IngressVisibilityClusterLocal, nil),
+			ingress("default", "other-class", v1alpha1.IngressVisibilityClusterLocal, map[string]string{
+				networking.IngressClassAnnotationKey: "kourier.ingress.networking.knative.dev",
+			}),
+		},
+		want: []GatewayChange{{
+			Namespace: "default",
+			Name:      "foo",
+			Old:       []string{"knative-serving/knative-local-gateway"},
+			New:       []string{},
+		}},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listers := NewListers(tt.objects)
+			checker := &Checker{
+				IngressLister:   listers.GetIngressLister(),
+				NamespaceLister: listers.GetNamespaceLister(),
+			}
+
+			got, err := checker.GatewayChanges(oldIstio, tt.newIstio)
+			if err != nil {
+				t.Fatal("GatewayChanges() =", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Error("Unexpected changes (-want, +got):", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2026 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configvalidation
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/net-istio/pkg/reconciler/ingress/config"
+	netconfig "knative.dev/networking/pkg/config"
+	"knative.dev/pkg/system"
+)
+
+// NetworkWarnings returns the settings of the current config-network which do not work
+// with the given configuration.
+func (c *Checker) NetworkWarnings(ctx context.Context, istio *config.Istio) ([]string, error) {
+	configMap, err := c.KubeClient.CoreV1().ConfigMaps(system.Namespace()).Get(ctx, netconfig.ConfigMapName, metav1.GetOptions{})
+	if apierrs.IsNotFound(err) {
+		configMap = &corev1.ConfigMap{}
+	} else if err != nil {
+		return nil, err
+	}
+
+	network, err := netconfig.NewConfigFromConfigMap(configMap)
+	if err != nil {
+		//nolint:nilerr // config-network is validated on its own and the controller keeps the last valid one.
+		return nil, nil
+	}
+	return istio.NetworkWarnings(network), nil
+}